@@ -21,6 +21,11 @@ type DataSetConfiguration struct {
 type DataSetConfigurationSpec struct {
 	// Components attaches component information to specific files.
 	Components ComponentSpec `json:"components,omitempty"`
+	// SBOM is the path to a syft, SPDX, or CycloneDX JSON document
+	// describing the workspace. Component information is attached to
+	// files matched by path or checksum and fills unset Components fields.
+	// A relative path is resolved against the directory of the configuration file.
+	SBOM string `json:"sbom,omitempty"`
 	// Runtime attaches runtime information to the artifact manifest
 	Runtime ocispec.ImageConfig `json:"runtime,omitempty"`
 	// Files defines custom attributes to add the files in the
@@ -32,6 +37,58 @@ type DataSetConfigurationSpec struct {
 	// LinkedCollections are the remote addresses of collection that are
 	// linked to the collection.
 	LinkedCollections []string `json:"linkedCollections,omitempty"`
+	// Extractors configures attributes that are derived
+	// from file content.
+	Extractors ExtractorSpec `json:"extractors,omitempty"`
+	// Variants declares platform or variant specific sub-collections.
+	// When set, one manifest is built per variant and the manifests
+	// are referenced by an index.
+	Variants []Variant `json:"variants,omitempty"`
+}
+
+// Variant defines a platform or variant specific
+// sub-collection.
+type Variant struct {
+	// Name is a unique name for the variant.
+	Name string `json:"name"`
+	// Workspace is the location of the variant content
+	// relative to the root workspace.
+	Workspace string `json:"workspace"`
+	// Platform is the target platform of the variant
+	// in the form os/arch[/variant]. Defaults to the
+	// component platform.
+	Platform string `json:"platform,omitempty"`
+	// Attributes are used to select the variant
+	// when pulling the collection.
+	Attributes Attributes `json:"attributes,omitempty"`
+}
+
+// ExtractorSpec defines configuration information for attribute extraction.
+type ExtractorSpec struct {
+	// BuiltIn enables the built-in extractor. Derived attributes
+	// are stored under the reserved "core-extracted" schema.
+	BuiltIn bool `json:"builtIn,omitempty"`
+	// Plugins are external commands that derive attributes from
+	// matching files. Plugin attributes are stored under the collection schema.
+	Plugins []ExtractorPlugin `json:"plugins,omitempty"`
+}
+
+// ExtractorPlugin defines an external command that implements
+// the extractor plugin protocol.
+type ExtractorPlugin struct {
+	// Name is a unique name for the plugin.
+	Name string `json:"name"`
+	// Command is the path to the plugin executable.
+	Command string `json:"command"`
+	// Args are the arguments passed to the command before the
+	// file path.
+	Args []string `json:"args,omitempty"`
+	// Files are the file patterns the plugin will run against.
+	// Patterns follow the same rules as File.
+	Files []string `json:"files"`
+	// Timeout is the maximum duration of a single plugin run
+	// (e.g. 30s). Defaults to one minute.
+	Timeout string `json:"timeout,omitempty"`
 }
 
 // ComponentSpec defines configuration information when creating component lists.
@@ -39,6 +96,7 @@ type DataSetConfigurationSpec struct {
 // information. All workspace items will have their component information collection on a best-effort
 // basis.
 type ComponentSpec struct {
+	// Platform is the default platform for collection variants.
 	Platform  string   `json:"platform"`
 	Name      string   `json:"name"`
 	Version   string   `json:"version"`
@@ -55,14 +113,31 @@ type ComponentSpec struct {
 
 // File associates attributes with file names.
 type File struct {
-	// File is a string that can be compiled into a regular expression
-	// for grouping attributes.
+	// File is a file pattern for grouping attributes. Patterns are
+	// globs supporting "**" path segments, or regular expressions
+	// when prefixed with "regex:".
 	File string `json:"file,omitempty"`
 	// FileInfo sets target path, ownership, and
 	// permissions for files that can be used with container runtimes.
 	FileInfo empspec.File `json:"fileInfo,omitempty"`
 	// Attributes is the lists of to associate to the file.
 	Attributes Attributes `json:"attributes,omitempty"`
+
+	// fileInfoSet records whether file information
+	// was present in the unmarshalled data.
+	fileInfoSet bool
+}
+
+// HasFileInfo returns whether file information is set. File information
+// unmarshalled from data is set if any of its fields are set, where
+// unset user and group IDs are represented as -1. A zero value FileInfo
+// that was not unmarshalled from data is not set.
+func (f File) HasFileInfo() bool {
+	info := f.FileInfo
+	if !f.fileInfoSet && info == (empspec.File{}) {
+		return false
+	}
+	return info.Permissions != 0 || info.UID != -1 || info.GID != -1
 }
 
 // Attributes is a map structure that holds all
@@ -85,6 +160,11 @@ func (f *File) UnmarshalJSON(data []byte) error {
 	if err != nil {
 		return err
 	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
 	*f = File(*test)
+	_, f.fileInfoSet = fields["fileInfo"]
 	return nil
 }
@@ -0,0 +1,18 @@
+package v1alpha1
+
+// DirectoryAttributesKind object kind of DirectoryAttributes.
+const DirectoryAttributesKind = "DirectoryAttributes"
+
+// DirectoryAttributesFileName is the name of the sidecar file
+// that configures DirectoryAttributes for a workspace directory.
+const DirectoryAttributesFileName = ".attributes.yaml"
+
+// DirectoryAttributes configures attributes for the files in a
+// workspace directory and its descendants.
+type DirectoryAttributes struct {
+	TypeMeta `json:",inline"`
+	// Attributes are added to each file in the directory and its
+	// descendants. Attributes in deeper directories override attributes
+	// with the same key.
+	Attributes Attributes `json:"attributes,omitempty"`
+}
@@ -0,0 +1,22 @@
+package v1alpha1
+
+// LockFileKind object kind of LockFile.
+const LockFileKind = "LockFile"
+
+// LockFile pins the schema and linked collections of a
+// DataSetConfiguration to the digests they resolved to.
+type LockFile struct {
+	TypeMeta `json:",inline"`
+	// Schema is the schema address and its resolved digest.
+	Schema *LockedReference `json:"schema,omitempty"`
+	// Links are the linked collection addresses and their resolved digests.
+	Links []LockedReference `json:"links,omitempty"`
+}
+
+// LockedReference is a reference and the digest it resolved to.
+type LockedReference struct {
+	// Reference is the address from the dataset configuration.
+	Reference string `json:"reference"`
+	// Digest is the manifest digest the reference resolved to.
+	Digest string `json:"digest"`
+}
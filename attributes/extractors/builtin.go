@@ -0,0 +1,277 @@
+package extractors
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	// Register the image formats supported for dimension extraction.
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/gabriel-vasile/mimetype"
+	"github.com/rwcarlsen/goexif/exif"
+	"github.com/rwcarlsen/goexif/tiff"
+
+	"github.com/emporous/emporous-go/attributes"
+	"github.com/emporous/emporous-go/log"
+	"github.com/emporous/emporous-go/model"
+	"github.com/emporous/emporous-go/util/workspace"
+)
+
+// Attribute keys written by the built-in extractor.
+const (
+	KeyMediaType = "mediaType"
+	KeySize      = "size"
+	KeyExtension = "extension"
+	KeyLines     = "lines"
+	KeyWidth     = "width"
+	KeyHeight    = "height"
+	// KeyExifPrefix prefixes each EXIF field name.
+	KeyExifPrefix = "exif."
+)
+
+const (
+	mediaTypeJPEG = "image/jpeg"
+	mediaTypePNG  = "image/png"
+	// headerLimit is the number of bytes read for
+	// media type detection.
+	headerLimit = 3072
+)
+
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+// errInvalidImage denotes image content that cannot be decoded.
+var errInvalidImage = errors.New("invalid image")
+
+type builtIn struct {
+	logger log.Logger
+}
+
+var _ Extractor = builtIn{}
+
+// NewBuiltIn returns an Extractor that derives the media type, size,
+// extension, text line count, and image dimensions and EXIF data for JPEG and PNG files.
+// Images that cannot be decoded are logged as warnings and have no image attributes.
+func NewBuiltIn(logger log.Logger) Extractor {
+	return builtIn{logger: logger}
+}
+
+// Extract returns attributes derived from the file at the
+// given path in the workspace.
+func (b builtIn) Extract(ctx context.Context, space workspace.Workspace, path string) (model.AttributeSet, error) {
+	reader, err := space.GetReader(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	header := make([]byte, headerLimit)
+	n, err := io.ReadFull(reader, header)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, err
+	}
+	header = header[:n]
+
+	counter := &lineCounter{}
+	if _, err := counter.Write(header); err != nil {
+		return nil, err
+	}
+	if _, err := io.Copy(counter, reader); err != nil {
+		return nil, err
+	}
+
+	mType := mimetype.Detect(header)
+	set := attributes.Attributes{
+		KeyMediaType: attributes.NewString(KeyMediaType, mType.String()),
+		KeySize:      attributes.NewInt(KeySize, counter.size),
+	}
+
+	if ext := strings.TrimPrefix(filepath.Ext(path), "."); ext != "" {
+		set[KeyExtension] = attributes.NewString(KeyExtension, ext)
+	}
+
+	if isText(mType) {
+		set[KeyLines] = attributes.NewInt(KeyLines, counter.lines())
+	}
+
+	if mType.Is(mediaTypeJPEG) || mType.Is(mediaTypePNG) {
+		imageSet, err := b.extractImage(ctx, space, path, mType)
+		switch {
+		case errors.Is(err, errInvalidImage):
+			b.logger.Warnf("file %q: skipping image attributes: %v", path, err)
+		case err != nil:
+			return nil, fmt.Errorf("file %q: %w", path, err)
+		}
+		for key, value := range imageSet {
+			set[key] = value
+		}
+	}
+
+	return set, nil
+}
+
+// extractImage returns the image dimensions and EXIF data. An errInvalidImage
+// error is returned if the image cannot be decoded.
+func (b builtIn) extractImage(ctx context.Context, space workspace.Workspace, path string, mType *mimetype.MIME) (attributes.Attributes, error) {
+	reader, err := space.GetReader(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	// The image is buffered to allow the dimensions
+	// and EXIF data to be read from the same content.
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only the image header is decoded for the dimensions.
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errInvalidImage, err)
+	}
+	set := attributes.Attributes{
+		KeyWidth:  attributes.NewInt(KeyWidth, int64(config.Width)),
+		KeyHeight: attributes.NewInt(KeyHeight, int64(config.Height)),
+	}
+
+	var exifReader io.Reader = bytes.NewReader(data)
+	if mType.Is(mediaTypePNG) {
+		exifData, err := pngExif(data)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", errInvalidImage, err)
+		}
+		if exifData == nil {
+			return set, nil
+		}
+		exifReader = bytes.NewReader(exifData)
+	}
+
+	x, err := exif.Decode(exifReader)
+	if err != nil {
+		// Images are not required to contain EXIF data
+		// and any partially decoded data is still usable.
+		if x == nil || exif.IsCriticalError(err) {
+			return set, nil
+		}
+	}
+
+	if err := x.Walk(exifWalker(set)); err != nil {
+		return nil, err
+	}
+	return set, nil
+}
+
+// exifWalker adds each EXIF field with a single
+// scalar value to the attribute set.
+type exifWalker attributes.Attributes
+
+func (w exifWalker) Walk(name exif.FieldName, tag *tiff.Tag) error {
+	key := KeyExifPrefix + string(name)
+	switch tag.Format() {
+	case tiff.StringVal:
+		value, err := tag.StringVal()
+		if err != nil {
+			return nil
+		}
+		w[key] = attributes.NewString(key, strings.TrimSpace(value))
+	case tiff.IntVal:
+		if tag.Count != 1 {
+			return nil
+		}
+		value, err := tag.Int64(0)
+		if err != nil {
+			return nil
+		}
+		w[key] = attributes.NewInt(key, value)
+	case tiff.RatVal:
+		if tag.Count != 1 {
+			return nil
+		}
+		num, den, err := tag.Rat2(0)
+		if err != nil || den == 0 {
+			return nil
+		}
+		w[key] = attributes.NewFloat(key, float64(num)/float64(den))
+	case tiff.FloatVal:
+		if tag.Count != 1 {
+			return nil
+		}
+		value, err := tag.Float(0)
+		if err != nil {
+			return nil
+		}
+		w[key] = attributes.NewFloat(key, value)
+	}
+	return nil
+}
+
+// pngExif returns the content of the eXIf chunk in PNG data.
+// If the chunk does not exist, nil is returned.
+func pngExif(data []byte) ([]byte, error) {
+	if !bytes.HasPrefix(data, pngSignature) {
+		return nil, errors.New("invalid png signature")
+	}
+	data = data[len(pngSignature):]
+	// Each chunk has a 4 byte length, 4 byte type,
+	// the chunk data, and a 4 byte CRC.
+	for len(data) >= 12 {
+		length := binary.BigEndian.Uint32(data[:4])
+		chunkType := string(data[4:8])
+		if uint64(length)+12 > uint64(len(data)) {
+			return nil, errors.New("invalid png chunk length")
+		}
+		switch chunkType {
+		case "eXIf":
+			return data[8 : 8+length], nil
+		case "IEND":
+			return nil, nil
+		}
+		data = data[12+length:]
+	}
+	return nil, nil
+}
+
+// isText returns whether the media type
+// is a text type.
+func isText(mType *mimetype.MIME) bool {
+	for m := mType; m != nil; m = m.Parent() {
+		if m.Is("text/plain") {
+			return true
+		}
+	}
+	return false
+}
+
+// lineCounter counts the bytes and lines written to it.
+type lineCounter struct {
+	size     int64
+	newlines int64
+	last     byte
+}
+
+func (c *lineCounter) Write(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	c.size += int64(len(p))
+	c.newlines += int64(bytes.Count(p, []byte{'\n'}))
+	c.last = p[len(p)-1]
+	return len(p), nil
+}
+
+// lines returns the number of lines counted. A final line
+// without a trailing newline is included.
+func (c *lineCounter) lines() int64 {
+	if c.size > 0 && c.last != '\n' {
+		return c.newlines + 1
+	}
+	return c.newlines
+}
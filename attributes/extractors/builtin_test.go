@@ -0,0 +1,163 @@
+package extractors
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/emporous/emporous-go/attributes"
+	"github.com/emporous/emporous-go/log"
+	"github.com/emporous/emporous-go/model"
+	"github.com/emporous/emporous-go/util/workspace"
+)
+
+func TestBuiltInExtract(t *testing.T) {
+	type spec struct {
+		name string
+		path string
+		data []byte
+		exp  model.AttributeSet
+		// expWarning is contained in the logged warnings.
+		expWarning string
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, 4, 2))
+	var pngData bytes.Buffer
+	require.NoError(t, png.Encode(&pngData, img))
+	var jpegData bytes.Buffer
+	require.NoError(t, jpeg.Encode(&jpegData, img, nil))
+
+	cases := []spec{
+		{
+			name: "Success/Text",
+			path: "level1/file.txt",
+			data: []byte("line1\nline2\nline3"),
+			exp: attributes.Attributes{
+				KeyMediaType: attributes.NewString(KeyMediaType, "text/plain; charset=utf-8"),
+				KeySize:      attributes.NewInt(KeySize, 17),
+				KeyExtension: attributes.NewString(KeyExtension, "txt"),
+				KeyLines:     attributes.NewInt(KeyLines, 3),
+			},
+		},
+		{
+			name: "Success/JSON",
+			path: "test.json",
+			data: []byte("{\"name\":\"test\"}\n"),
+			exp: attributes.Attributes{
+				KeyMediaType: attributes.NewString(KeyMediaType, "application/json"),
+				KeySize:      attributes.NewInt(KeySize, 16),
+				KeyExtension: attributes.NewString(KeyExtension, "json"),
+				KeyLines:     attributes.NewInt(KeyLines, 1),
+			},
+		},
+		{
+			name: "Success/JPEG",
+			path: "image.jpg",
+			data: jpegData.Bytes(),
+			exp: attributes.Attributes{
+				KeyMediaType: attributes.NewString(KeyMediaType, "image/jpeg"),
+				KeySize:      attributes.NewInt(KeySize, int64(jpegData.Len())),
+				KeyExtension: attributes.NewString(KeyExtension, "jpg"),
+				KeyWidth:     attributes.NewInt(KeyWidth, 4),
+				KeyHeight:    attributes.NewInt(KeyHeight, 2),
+			},
+		},
+		{
+			name: "Success/PNGWithExif",
+			path: "image",
+			data: withExifChunk(t, pngData.Bytes()),
+			exp: attributes.Attributes{
+				KeyMediaType:           attributes.NewString(KeyMediaType, "image/png"),
+				KeySize:                attributes.NewInt(KeySize, int64(pngData.Len()+len(testExif)+12)),
+				KeyWidth:               attributes.NewInt(KeyWidth, 4),
+				KeyHeight:              attributes.NewInt(KeyHeight, 2),
+				KeyExifPrefix + "Make": attributes.NewString(KeyExifPrefix+"Make", "Canon"),
+			},
+		},
+		{
+			name: "Success/InvalidPNG",
+			path: "image.png",
+			data: pngData.Bytes()[:20],
+			exp: attributes.Attributes{
+				KeyMediaType: attributes.NewString(KeyMediaType, "image/png"),
+				KeySize:      attributes.NewInt(KeySize, 20),
+				KeyExtension: attributes.NewString(KeyExtension, "png"),
+			},
+			expWarning: `file \"image.png\": skipping image attributes: invalid image: unexpected EOF`,
+		},
+		{
+			name: "Success/InvalidJPEG",
+			path: "image.jpg",
+			data: jpegData.Bytes()[:20],
+			exp: attributes.Attributes{
+				KeyMediaType: attributes.NewString(KeyMediaType, "image/jpeg"),
+				KeySize:      attributes.NewInt(KeySize, 20),
+				KeyExtension: attributes.NewString(KeyExtension, "jpg"),
+			},
+			expWarning: `file \"image.jpg\": skipping image attributes: invalid image: unexpected EOF`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ctx := context.Background()
+			space, err := workspace.NewMemoryWorkspace()
+			require.NoError(t, err)
+			require.NoError(t, space.WriteObject(ctx, c.path, c.data))
+
+			out := new(bytes.Buffer)
+			testlogr, err := log.NewLogrusLogger(out, "debug")
+			require.NoError(t, err)
+
+			set, err := NewBuiltIn(testlogr).Extract(ctx, space, c.path)
+			require.NoError(t, err)
+			require.Equal(t, c.exp, set)
+			if c.expWarning != "" {
+				require.Contains(t, out.String(), c.expWarning)
+			} else {
+				require.Empty(t, out.String())
+			}
+		})
+	}
+}
+
+// testExif is a little endian TIFF structure with
+// a single IFD entry for the camera make.
+var testExif = []byte{
+	'I', 'I', 0x2A, 0x00, 0x08, 0x00, 0x00, 0x00,
+	// One IFD entry
+	0x01, 0x00,
+	// Make tag, ASCII type, count 6, offset 26
+	0x0F, 0x01, 0x02, 0x00, 0x06, 0x00, 0x00, 0x00, 0x1A, 0x00, 0x00, 0x00,
+	// No next IFD
+	0x00, 0x00, 0x00, 0x00,
+	'C', 'a', 'n', 'o', 'n', 0x00,
+}
+
+// withExifChunk inserts an eXIf chunk after the IHDR chunk.
+func withExifChunk(t *testing.T, data []byte) []byte {
+	ihdrEnd := len(pngSignature) + 12 + 13
+	require.Greater(t, len(data), ihdrEnd)
+
+	var chunk bytes.Buffer
+	require.NoError(t, binary.Write(&chunk, binary.BigEndian, uint32(len(testExif))))
+	chunk.WriteString("eXIf")
+	chunk.Write(testExif)
+	crc := crc32.NewIEEE()
+	crc.Write([]byte("eXIf"))
+	crc.Write(testExif)
+	require.NoError(t, binary.Write(&chunk, binary.BigEndian, crc.Sum32()))
+
+	var out bytes.Buffer
+	out.Write(data[:ihdrEnd])
+	out.Write(chunk.Bytes())
+	out.Write(data[ihdrEnd:])
+	return out.Bytes()
+}
@@ -0,0 +1,32 @@
+/*
+Copyright 2022 Emporous Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package extractors
+
+// This package contains implementations that derive
+// attributes from file content during collection building.
+//
+// Extractor plugins are executables that follow a simple protocol:
+//
+//  1. The plugin is invoked with any configured arguments followed
+//     by the workspace path of the file.
+//  2. The file content is written to the plugin's standard input. For
+//     workspaces that are not on disk, the path may not exist and the
+//     content must be read from standard input.
+//  3. The plugin writes a single JSON object to standard output. Each
+//     key is an attribute name and each value must be a string, number, boolean, or null.
+//  4. A non-zero exit code indicates failure. Standard error is included
+//     in the returned error.
+//
+// The relative location of the file in the workspace is also set in
+// the environment variable named by EnvFileLocation.
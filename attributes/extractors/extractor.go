@@ -0,0 +1,19 @@
+package extractors
+
+import (
+	"context"
+
+	"github.com/emporous/emporous-go/model"
+	"github.com/emporous/emporous-go/util/workspace"
+)
+
+// SchemaID is the reserved schema ID that derived attributes
+// are stored under in the descriptor properties.
+const SchemaID = "core-extracted"
+
+// Extractor defines methods for deriving attributes from files.
+type Extractor interface {
+	// Extract returns attributes derived from the file at the
+	// given path in the workspace.
+	Extract(ctx context.Context, space workspace.Workspace, path string) (model.AttributeSet, error)
+}
@@ -0,0 +1,88 @@
+package extractors
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/emporous/emporous-go/attributes"
+	"github.com/emporous/emporous-go/model"
+	"github.com/emporous/emporous-go/util/workspace"
+)
+
+// EnvFileLocation is the environment variable containing the
+// relative location of the file in the workspace.
+const EnvFileLocation = "EMPOROUS_FILE_LOCATION"
+
+type plugin struct {
+	command string
+	args    []string
+	timeout time.Duration
+}
+
+var _ Extractor = plugin{}
+
+// NewPlugin returns an Extractor that runs an external command following the extractor
+// plugin protocol. A timeout of zero disables the timeout.
+func NewPlugin(command string, args []string, timeout time.Duration) Extractor {
+	return plugin{
+		command: command,
+		args:    args,
+		timeout: timeout,
+	}
+}
+
+// Extract runs the plugin command and returns the attributes
+// written to standard output.
+func (p plugin) Extract(ctx context.Context, space workspace.Workspace, path string) (model.AttributeSet, error) {
+	if p.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.timeout)
+		defer cancel()
+	}
+
+	reader, err := space.GetReader(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	var stdout, stderr bytes.Buffer
+	args := append(append([]string{}, p.args...), space.Path(path))
+	cmd := exec.CommandContext(ctx, p.command, args...)
+	cmd.Env = append(os.Environ(), fmt.Sprintf("%s=%s", EnvFileLocation, path))
+	cmd.Stdin = reader
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("plugin %q: timed out after %s", p.command, p.timeout)
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("plugin %q: %w: %s", p.command, err, msg)
+		}
+		return nil, fmt.Errorf("plugin %q: %w", p.command, err)
+	}
+
+	var output map[string]interface{}
+	if err := json.Unmarshal(stdout.Bytes(), &output); err != nil {
+		return nil, fmt.Errorf("plugin %q: invalid output: %w", p.command, err)
+	}
+
+	set := attributes.Attributes{}
+	for key, value := range output {
+		attr, err := attributes.Reflect(key, value)
+		if err != nil {
+			return nil, fmt.Errorf("plugin %q: attribute %s: %w", p.command, key, err)
+		}
+		set[key] = attr
+	}
+	return set, nil
+}
@@ -0,0 +1,79 @@
+package extractors
+
+import (
+	"context"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/emporous/emporous-go/attributes"
+	"github.com/emporous/emporous-go/model"
+	"github.com/emporous/emporous-go/util/workspace"
+)
+
+func TestPluginExtract(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugin tests require a POSIX shell")
+	}
+
+	type spec struct {
+		name     string
+		script   string
+		timeout  time.Duration
+		exp      model.AttributeSet
+		expError string
+	}
+
+	cases := []spec{
+		{
+			name:   "Success/AttributesFromStdin",
+			script: `printf '{"lines": %d, "location": "%s", "checked": true}' "$(wc -l | tr -d ' ')" "$EMPOROUS_FILE_LOCATION"`,
+			exp: attributes.Attributes{
+				"lines":    attributes.NewFloat("lines", 2),
+				"location": attributes.NewString("location", "data/test.txt"),
+				"checked":  attributes.NewBool("checked", true),
+			},
+		},
+		{
+			name:     "Failure/NonZeroExit",
+			script:   `echo "bad file" >&2; exit 1`,
+			expError: "plugin \"sh\": exit status 1: bad file",
+		},
+		{
+			name:     "Failure/InvalidOutput",
+			script:   `echo "not json"`,
+			expError: "plugin \"sh\": invalid output: invalid character 'o' in literal null (expecting 'u')",
+		},
+		{
+			name:     "Failure/NestedAttribute",
+			script:   `echo '{"nested": {"key": "value"}}'`,
+			expError: "plugin \"sh\": attribute nested: invalid attribute type",
+		},
+		{
+			name:     "Failure/Timeout",
+			script:   `exec sleep 5`,
+			timeout:  100 * time.Millisecond,
+			expError: "plugin \"sh\": timed out after 100ms",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ctx := context.Background()
+			space, err := workspace.NewMemoryWorkspace()
+			require.NoError(t, err)
+			require.NoError(t, space.WriteObject(ctx, "data/test.txt", "line1\nline2\n"))
+
+			extractor := NewPlugin("sh", []string{"-c", c.script, "plugin"}, c.timeout)
+			set, err := extractor.Extract(ctx, space, "data/test.txt")
+			if c.expError != "" {
+				require.EqualError(t, err, c.expError)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, c.exp, set)
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/spf13/cobra"
+	"oras.land/oras-go/v2/registry"
+
+	"github.com/emporous/emporous-go/cmd/client/commands/options"
+	"github.com/emporous/emporous-go/registryclient/orasclient"
+	"github.com/emporous/emporous-go/util/examples"
+	"github.com/emporous/emporous-go/util/workspace"
+)
+
+// AttachOptions describe configuration options that can
+// be set using the attach subcommand.
+type AttachOptions struct {
+	*options.Common
+	options.Remote
+	options.RemoteAuth
+	Reference    string
+	ArtifactType string
+	Files        []string
+}
+
+var clientAttachExamples = examples.Example{
+	RootCommand:   filepath.Base(os.Args[0]),
+	Descriptions:  []string{"Attach an SBOM to a collection."},
+	CommandString: "attach localhost:5001/test:latest --artifact-type application/spdx+json sbom.spdx.json",
+}
+
+// NewAttachCmd creates a new cobra.Command for the attach subcommand.
+func NewAttachCmd(common *options.Common) *cobra.Command {
+	o := AttachOptions{Common: common}
+
+	cmd := &cobra.Command{
+		Use:           "attach REF FILE...",
+		Short:         "Attach files to a Emporous collection as a referrer artifact",
+		Example:       examples.FormatExamples(clientAttachExamples),
+		SilenceErrors: false,
+		SilenceUsage:  false,
+		Args:          cobra.MinimumNArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			cobra.CheckErr(o.Complete(args))
+			cobra.CheckErr(o.Validate())
+			cobra.CheckErr(o.Run(cmd.Context()))
+		},
+	}
+
+	o.Remote.BindFlags(cmd.Flags())
+	o.RemoteAuth.BindFlags(cmd.Flags())
+
+	cmd.Flags().StringVar(&o.ArtifactType, "artifact-type", o.ArtifactType, "artifact type of the attached artifact")
+
+	return cmd
+}
+
+func (o *AttachOptions) Complete(args []string) error {
+	if len(args) < 2 {
+		return errors.New("bug: expecting at least two arguments")
+	}
+	o.Reference = args[0]
+	o.Files = args[1:]
+	return nil
+}
+
+func (o *AttachOptions) Validate() error {
+	if o.ArtifactType == "" {
+		return errors.New("--artifact-type must be set")
+	}
+	// Files are titled by name, so names must be unique.
+	names := map[string]string{}
+	for _, file := range o.Files {
+		if _, err := os.Stat(file); err != nil {
+			return fmt.Errorf("file %q: %w", file, err)
+		}
+		name := filepath.Base(file)
+		if other, ok := names[name]; ok {
+			return fmt.Errorf("file %q: name %q is also used by %q", file, name, other)
+		}
+		names[name] = file
+	}
+	return nil
+}
+
+func (o *AttachOptions) Run(ctx context.Context) error {
+	ref, err := registry.ParseReference(o.Reference)
+	if err != nil {
+		return err
+	}
+
+	client, err := orasclient.NewClient(
+		orasclient.SkipTLSVerify(o.Insecure),
+		orasclient.WithAuthConfigs(o.Configs),
+		orasclient.WithPlainHTTP(o.PlainHTTP),
+	)
+	if err != nil {
+		return fmt.Errorf("error configuring client: %v", err)
+	}
+	defer func() {
+		if err := client.Destroy(); err != nil {
+			o.Logger.Errorf(err.Error())
+		}
+	}()
+
+	subject, rc, err := client.GetManifest(ctx, o.Reference)
+	if err != nil {
+		return fmt.Errorf("reference %s: %w", o.Reference, err)
+	}
+	if err := rc.Close(); err != nil {
+		return err
+	}
+
+	// Files are titled with their name so the location
+	// they are attached from is not recorded.
+	var descs []ocispec.Descriptor
+	for _, file := range o.Files {
+		space, err := workspace.NewLocalWorkspace(filepath.Dir(file))
+		if err != nil {
+			return err
+		}
+		fileDescs, err := client.AddWorkspaceFiles(ctx, "", space, filepath.Base(file))
+		if err != nil {
+			return err
+		}
+		descs = append(descs, fileDescs...)
+	}
+	desc, err := client.AddArtifact(ctx, "", o.ArtifactType, subject, nil, descs...)
+	if err != nil {
+		return err
+	}
+
+	// The artifact is pushed by digest, so no tags
+	// are created in the repository.
+	destination := fmt.Sprintf("%s/%s@%s", ref.Registry, ref.Repository, desc.Digest)
+	store, err := client.Store()
+	if err != nil {
+		return err
+	}
+	if err := store.Tag(ctx, desc, destination); err != nil {
+		return err
+	}
+	if _, err := client.Push(ctx, store, destination); err != nil {
+		return err
+	}
+
+	o.Logger.Infof("Artifact %s attached to %s", desc.Digest, o.Reference)
+	return nil
+}
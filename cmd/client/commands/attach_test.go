@@ -0,0 +1,192 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/stretchr/testify/require"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/emporous/emporous-go/cmd/client/commands/options"
+	"github.com/emporous/emporous-go/log"
+)
+
+func TestAttachValidate(t *testing.T) {
+	type spec struct {
+		name     string
+		opts     *AttachOptions
+		expError string
+	}
+
+	cases := []spec{
+		{
+			name: "Valid/ArtifactType",
+			opts: &AttachOptions{
+				ArtifactType: "application/spdx+json",
+				Files:        []string{"./testdata/sbom/spdx.json"},
+			},
+		},
+		{
+			name: "Invalid/NoArtifactType",
+			opts: &AttachOptions{
+				Files: []string{"./testdata/sbom/spdx.json"},
+			},
+			expError: "--artifact-type must be set",
+		},
+		{
+			name: "Invalid/FileNotExist",
+			opts: &AttachOptions{
+				ArtifactType: "application/spdx+json",
+				Files:        []string{"./testdata/sbom/fake.json"},
+			},
+			expError: `file "./testdata/sbom/fake.json": stat ./testdata/sbom/fake.json: no such file or directory`,
+		},
+		{
+			name: "Invalid/DuplicateName",
+			opts: &AttachOptions{
+				ArtifactType: "application/spdx+json",
+				Files:        []string{"./testdata/sbom/spdx.json", "./testdata/sbom/../sbom/spdx.json"},
+			},
+			expError: `file "./testdata/sbom/../sbom/spdx.json": name "spdx.json" is also used by "./testdata/sbom/spdx.json"`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := c.opts.Validate()
+			if c.expError != "" {
+				require.EqualError(t, err, c.expError)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestAttachRun(t *testing.T) {
+	testlogr, err := log.NewLogrusLogger(ioutil.Discard, "debug")
+	require.NoError(t, err)
+
+	server := httptest.NewServer(registry.New())
+	t.Cleanup(server.Close)
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	ctx := context.TODO()
+	cache := filepath.Join(t.TempDir(), "cache")
+	require.NoError(t, os.MkdirAll(cache, 0750))
+	reference := fmt.Sprintf("%s/client-attach:latest", u.Host)
+	buildOpts := &BuildCollectionOptions{
+		BuildOptions: &BuildOptions{
+			Common: &options.Common{
+				Logger:   testlogr,
+				CacheDir: cache,
+			},
+			Destination: reference,
+		},
+		RootDir: "./testdata/flatworkspace",
+	}
+	require.NoError(t, buildOpts.Run(ctx))
+	pushOpts := &PushOptions{
+		Common: &options.Common{
+			Logger:   testlogr,
+			CacheDir: cache,
+		},
+		Remote: options.Remote{
+			PlainHTTP: true,
+		},
+		Destination: reference,
+	}
+	require.NoError(t, pushOpts.Run(ctx))
+	subject, _ := fetchManifest(t, reference)
+
+	attachOpts := &AttachOptions{
+		Common: &options.Common{
+			Logger: testlogr,
+		},
+		Remote: options.Remote{
+			PlainHTTP: true,
+		},
+		ArtifactType: "application/spdx+json",
+	}
+	// Files attached by absolute path are titled with their name.
+	sbomPath, err := filepath.Abs("./testdata/sbom/spdx.json")
+	require.NoError(t, err)
+	require.NoError(t, attachOpts.Complete([]string{reference, sbomPath}))
+	require.NoError(t, attachOpts.Validate())
+	require.NoError(t, attachOpts.Run(ctx))
+
+	// Attaching does not move the collection tag.
+	desc, _ := fetchManifest(t, reference)
+	require.Equal(t, subject.Digest, desc.Digest)
+
+	t.Run("Success/Referrers", func(t *testing.T) {
+		out := new(bytes.Buffer)
+		opts := &ReferrersOptions{
+			Common: &options.Common{
+				IOStreams: genericclioptions.IOStreams{Out: out},
+				Logger:    testlogr,
+			},
+			Remote: options.Remote{
+				PlainHTTP: true,
+			},
+			ArtifactType: "application/spdx+json",
+		}
+		require.NoError(t, opts.Complete([]string{reference}))
+		require.NoError(t, opts.Run(ctx))
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		require.Len(t, lines, 2)
+		require.Contains(t, lines[1], "application/spdx+json")
+	})
+
+	t.Run("Success/ReferrersOtherType", func(t *testing.T) {
+		out := new(bytes.Buffer)
+		opts := &ReferrersOptions{
+			Common: &options.Common{
+				IOStreams: genericclioptions.IOStreams{Out: out},
+				Logger:    testlogr,
+			},
+			Remote: options.Remote{
+				PlainHTTP: true,
+			},
+			ArtifactType: "application/vnd.example.signature",
+		}
+		require.NoError(t, opts.Complete([]string{reference}))
+		require.NoError(t, opts.Run(ctx))
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		require.Len(t, lines, 1)
+	})
+
+	t.Run("Success/PullReferrers", func(t *testing.T) {
+		pullCache := filepath.Join(t.TempDir(), "cache")
+		require.NoError(t, os.MkdirAll(pullCache, 0750))
+		opts := &PullOptions{
+			Common: &options.Common{
+				Logger:   testlogr,
+				CacheDir: pullCache,
+			},
+			Remote: options.Remote{
+				PlainHTTP: true,
+			},
+			Source:        reference,
+			Output:        t.TempDir(),
+			NoVerify:      true,
+			ReferrerTypes: []string{"application/spdx+json"},
+		}
+		require.NoError(t, opts.Run(ctx))
+		_, err := os.Stat(filepath.Join(opts.Output, "fish.jpg"))
+		require.NoError(t, err)
+		matches, err := filepath.Glob(filepath.Join(opts.Output, "referrers", "*", "spdx.json"))
+		require.NoError(t, err)
+		require.Len(t, matches, 1)
+	})
+}
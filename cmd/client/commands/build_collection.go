@@ -4,8 +4,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -29,8 +33,29 @@ type BuildCollectionOptions struct {
 	RootDir  string
 	// Dataset Config
 	DSConfig string
+	// Reproducible builds collections with a deterministic manifest. The creation
+	// timestamp is set from SOURCE_DATE_EPOCH, if set, or omitted. File permissions
+	// that are not set in the dataset configuration are recorded as 0755 for
+	// files executable by their owner and 0644 otherwise.
+	Reproducible bool
+	// LockFile is the path of the lock file pinning the schema and linked
+	// collections. Defaults to the dataset configuration path with a
+	// ".lock.yaml" extension.
+	LockFile string
+	// Locked fails the build when the schema or linked collections resolve
+	// to digests other than those recorded in the lock file.
+	Locked bool
+	// DryRun prints the build plan without writing to the cache.
+	DryRun bool
+	// Format is the output format of the build plan.
+	Format string
 }
 
+// sourceDateEpochEnv is the environment variable containing the timestamp
+// used for reproducible builds in seconds since the Unix epoch.
+// Reference: https://reproducible-builds.org/specs/source-date-epoch/
+const sourceDateEpochEnv = "SOURCE_DATE_EPOCH"
+
 var clientBuildCollectionExamples = []examples.Example{
 	{
 		RootCommand:   filepath.Base(os.Args[0]),
@@ -42,6 +67,21 @@ var clientBuildCollectionExamples = []examples.Example{
 		Descriptions:  []string{"Build artifacts with custom annotations."},
 		CommandString: "build collection my-directory localhost:5000/myartifacts:latest --dsconfig dataset-config.yaml",
 	},
+	{
+		RootCommand:   filepath.Base(os.Args[0]),
+		Descriptions:  []string{"Build artifacts reproducibly using the SOURCE_DATE_EPOCH timestamp."},
+		CommandString: "build collection my-directory localhost:5000/myartifacts:latest --reproducible",
+	},
+	{
+		RootCommand:   filepath.Base(os.Args[0]),
+		Descriptions:  []string{"Build artifacts with the linked collections and schema pinned by the lock file."},
+		CommandString: "build collection my-directory localhost:5000/myartifacts:latest --dsconfig dataset-config.yaml --locked",
+	},
+	{
+		RootCommand:   filepath.Base(os.Args[0]),
+		Descriptions:  []string{"Print the files, attributes, and links of the collection as JSON without building it."},
+		CommandString: "build collection my-directory localhost:5000/myartifacts:latest --dsconfig dataset-config.yaml --dry-run --format json",
+	},
 }
 
 // NewBuildCollectionCmd creates a new cobra.Command for the build collection subcommand.
@@ -67,6 +107,11 @@ func NewBuildCollectionCmd(buildOpts *BuildOptions) *cobra.Command {
 
 	cmd.Flags().StringVarP(&o.DSConfig, "dsconfig", "d", o.DSConfig, "config path for artifact building and dataset configuration")
 	cmd.Flags().BoolVar(&o.NoVerify, "no-verify", o.NoVerify, "skip schema signature verification")
+	cmd.Flags().BoolVar(&o.Reproducible, "reproducible", o.Reproducible, "build a reproducible collection: use SOURCE_DATE_EPOCH as the creation timestamp, if set, and record file permissions not set in the dataset configuration as 0755 for executable files and 0644 otherwise")
+	cmd.Flags().StringVar(&o.LockFile, "lockfile", o.LockFile, "lock file path for linked collection and schema digests. Defaults to the dataset configuration path with a .lock.yaml extension")
+	cmd.Flags().BoolVar(&o.Locked, "locked", o.Locked, "fail if linked collections or the schema resolve to digests other than those in the lock file")
+	cmd.Flags().BoolVar(&o.DryRun, "dry-run", o.DryRun, "print the build plan without writing to the cache or the lock file")
+	cmd.Flags().StringVar(&o.Format, "format", planFormatTable, "build plan output format (table, json)")
 
 	return cmd
 }
@@ -84,6 +129,12 @@ func (o *BuildCollectionOptions) Validate() error {
 	if _, err := os.Stat(o.RootDir); err != nil {
 		return fmt.Errorf("workspace directory %q: %v", o.RootDir, err)
 	}
+	if o.Locked && o.DSConfig == "" {
+		return errors.New("--locked requires a dataset configuration")
+	}
+	if o.DryRun && o.Format != "" && o.Format != planFormatTable && o.Format != planFormatJSON {
+		return fmt.Errorf("unsupported output format %q: must be one of %s, %s", o.Format, planFormatTable, planFormatJSON)
+	}
 	return nil
 }
 
@@ -93,11 +144,24 @@ func (o *BuildCollectionOptions) Run(ctx context.Context) error {
 		return err
 	}
 
-	absCache, err := filepath.Abs(o.CacheDir)
+	storeDir, err := filepath.Abs(o.CacheDir)
 	if err != nil {
 		return err
 	}
-	cache, err := layout.NewWithContext(ctx, absCache)
+	if o.DryRun {
+		// Dry runs build into a temporary store to leave the cache unchanged.
+		tmp, err := ioutil.TempDir("", "emporous-dry-run-")
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if err := os.RemoveAll(tmp); err != nil {
+				o.Logger.Errorf(err.Error())
+			}
+		}()
+		storeDir = tmp
+	}
+	cache, err := layout.NewWithContext(ctx, storeDir)
 	if err != nil {
 		return err
 	}
@@ -108,6 +172,16 @@ func (o *BuildCollectionOptions) Run(ctx context.Context) error {
 		orasclient.WithPlainHTTP(o.PlainHTTP),
 	}
 
+	if o.Reproducible {
+		created, err := sourceDateEpoch()
+		if err != nil {
+			return err
+		}
+		if created != nil {
+			clientOpts = append(clientOpts, orasclient.WithTimestamp(*created))
+		}
+	}
+
 	if !o.NoVerify {
 		verificationFn := func(ctx context.Context, reference string) error {
 			o.Logger.Debugf("Checking signature of %s", reference)
@@ -138,8 +212,85 @@ func (o *BuildCollectionOptions) Run(ctx context.Context) error {
 		}
 	}
 
-	manager := defaultmanager.New(cache, o.Logger)
+	var lock *v1alpha1.LockFile
+	lockPath := o.LockFile
+	if lockPath == "" && o.DSConfig != "" {
+		lockPath = load.LockFilePath(o.DSConfig)
+	}
+	if len(config.Collection.LinkedCollections) != 0 || config.Collection.SchemaAddress != "" {
+		resolved, err := resolveLock(ctx, client, config)
+		if err != nil {
+			return err
+		}
+		if o.Locked {
+			locked, err := load.ReadLockFile(lockPath)
+			if err != nil {
+				return fmt.Errorf("lock file %s: %w", lockPath, err)
+			}
+			if diffs := load.DiffLockFile(locked, resolved); len(diffs) != 0 {
+				return fmt.Errorf("lock file %s does not match the dataset configuration: %s", lockPath, strings.Join(diffs, "; "))
+			}
+		}
+		if err := pinLinks(&config, resolved); err != nil {
+			return err
+		}
+		lock = &resolved
+	}
+
+	var managerOpts []defaultmanager.Option
+	if o.Reproducible {
+		managerOpts = append(managerOpts, defaultmanager.WithNormalizedFileInfo())
+	}
+	// Dry runs report schema validation failures in the build plan.
+	if o.DryRun {
+		managerOpts = append(managerOpts, defaultmanager.WithDeferredValidation())
+	}
+	manager := defaultmanager.New(cache, o.Logger, managerOpts...)
 
 	_, err = manager.Build(ctx, space, config, o.Destination, client)
-	return err
+	invalid, err := schemaValidationFailures(err)
+	if err != nil {
+		return err
+	}
+
+	if o.DryRun {
+		plan, err := newBuildPlan(ctx, cache, o.Destination, config, lock, invalid)
+		if err != nil {
+			return err
+		}
+		format := o.Format
+		if format == "" {
+			format = planFormatTable
+		}
+		if err := plan.write(o.IOStreams.Out, format); err != nil {
+			return err
+		}
+		if invalid != nil {
+			return invalid
+		}
+		return nil
+	}
+
+	if lock != nil && !o.Locked && lockPath != "" {
+		if err := load.WriteLockFile(lockPath, *lock); err != nil {
+			return err
+		}
+		o.Logger.Infof("Lock file written to %s", lockPath)
+	}
+	return nil
+}
+
+// sourceDateEpoch returns the timestamp set in the SOURCE_DATE_EPOCH
+// environment variable. If the variable is not set, nil is returned.
+func sourceDateEpoch() (*time.Time, error) {
+	value, ok := os.LookupEnv(sourceDateEpochEnv)
+	if !ok || value == "" {
+		return nil, nil
+	}
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s value %q: must be an integer", sourceDateEpochEnv, value)
+	}
+	created := time.Unix(seconds, 0).UTC()
+	return &created, nil
 }
@@ -1,7 +1,9 @@
 package commands
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http/httptest"
@@ -21,6 +23,8 @@ import (
 	"oras.land/oras-go/v2/registry/remote"
 
 	"github.com/emporous/emporous-go/cmd/client/commands/options"
+	load "github.com/emporous/emporous-go/config"
+	"github.com/emporous/emporous-go/content/layout"
 	"github.com/emporous/emporous-go/log"
 )
 
@@ -154,6 +158,27 @@ func TestBuildCollectionRun(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "Success/WithExtractors",
+			opts: &BuildCollectionOptions{
+				BuildOptions: &BuildOptions{
+					Common: &options.Common{
+						IOStreams: genericclioptions.IOStreams{
+							Out:    os.Stdout,
+							In:     os.Stdin,
+							ErrOut: os.Stderr,
+						},
+						Logger: testlogr,
+					},
+					Destination: fmt.Sprintf("%s/client-extractors:latest", u.Host),
+				},
+				DSConfig: "./testdata/configs/dataset-config-extractors.yaml",
+				RootDir:  "./testdata/multi-level-workspace",
+				Remote: options.Remote{
+					PlainHTTP: true,
+				},
+			},
+		},
 		{
 			name: "Success/WithSchema",
 			opts: &BuildCollectionOptions{
@@ -198,6 +223,93 @@ func TestBuildCollectionRun(t *testing.T) {
 				NoVerify: true,
 			},
 		},
+		{
+			name: "Success/WithVariants",
+			opts: &BuildCollectionOptions{
+				BuildOptions: &BuildOptions{
+					Common: &options.Common{
+						IOStreams: genericclioptions.IOStreams{
+							Out:    os.Stdout,
+							In:     os.Stdin,
+							ErrOut: os.Stderr,
+						},
+						Logger: testlogr,
+					},
+					Destination: fmt.Sprintf("%s/client-variants:latest", u.Host),
+				},
+				DSConfig: "./testdata/configs/dataset-config-variants.yaml",
+				RootDir:  "./testdata/multi-level-workspace",
+				Remote: options.Remote{
+					PlainHTTP: true,
+				},
+			},
+		},
+		{
+			name: "Failure/DuplicateVariant",
+			opts: &BuildCollectionOptions{
+				BuildOptions: &BuildOptions{
+					Common: &options.Common{
+						IOStreams: genericclioptions.IOStreams{
+							Out:    os.Stdout,
+							In:     os.Stdin,
+							ErrOut: os.Stderr,
+						},
+						Logger: testlogr,
+					},
+					Destination: fmt.Sprintf("%s/client-badvariants:latest", u.Host),
+				},
+				DSConfig: "./testdata/configs/dataset-config-invalidvariants.yaml",
+				RootDir:  "./testdata/multi-level-workspace",
+				Remote: options.Remote{
+					PlainHTTP: true,
+				},
+			},
+			expError: "variant \"images\": duplicate name",
+		},
+		{
+			name: "Failure/MissingVariantWorkspace",
+			opts: &BuildCollectionOptions{
+				BuildOptions: &BuildOptions{
+					Common: &options.Common{
+						IOStreams: genericclioptions.IOStreams{
+							Out:    os.Stdout,
+							In:     os.Stdin,
+							ErrOut: os.Stderr,
+						},
+						Logger: testlogr,
+					},
+					Destination: fmt.Sprintf("%s/client-missingvariant:latest", u.Host),
+				},
+				DSConfig: "./testdata/configs/dataset-config-missingvariant.yaml",
+				RootDir:  "./testdata/multi-level-workspace",
+				Remote: options.Remote{
+					PlainHTTP: true,
+				},
+			},
+			expError: "variant \"missing\": workspace missing does not exist",
+		},
+		{
+			name: "Failure/VariantWorkspaceIsFile",
+			opts: &BuildCollectionOptions{
+				BuildOptions: &BuildOptions{
+					Common: &options.Common{
+						IOStreams: genericclioptions.IOStreams{
+							Out:    os.Stdout,
+							In:     os.Stdin,
+							ErrOut: os.Stderr,
+						},
+						Logger: testlogr,
+					},
+					Destination: fmt.Sprintf("%s/client-filevariant:latest", u.Host),
+				},
+				DSConfig: "./testdata/configs/dataset-config-filevariant.yaml",
+				RootDir:  "./testdata/multi-level-workspace",
+				Remote: options.Remote{
+					PlainHTTP: true,
+				},
+			},
+			expError: "variant \"info\": workspace info.json is not a directory",
+		},
 		{
 			name: "Failure/InvalidSchema",
 			opts: &BuildCollectionOptions{
@@ -304,3 +416,524 @@ func prepCollectionArtifacts(t *testing.T, host string) map[string]string {
 		"schemaAddress":    schemaRef,
 	}
 }
+
+func TestBuildCollectionReproducible(t *testing.T) {
+	testlogr, err := log.NewLogrusLogger(ioutil.Discard, "debug")
+	require.NoError(t, err)
+
+	type spec struct {
+		name            string
+		sourceDateEpoch string
+		rootDir         string
+		dsConfig        string
+		expCreated      string
+		expError        string
+		// defaultBuild builds without the reproducible mode.
+		defaultBuild bool
+		// fileModes are the modes of the workspace files written
+		// for each build. If set, rootDir is not used.
+		fileModes [2]map[string]os.FileMode
+		// expFileInfo is the core-file information recorded by title.
+		expFileInfo map[string]string
+	}
+
+	cases := []spec{
+		{
+			name:         "Success/DefaultBuild",
+			rootDir:      "./testdata/multi-level-workspace",
+			defaultBuild: true,
+		},
+		{
+			name:            "Success/DefaultBuildIgnoresSourceDateEpoch",
+			sourceDateEpoch: "1672531200",
+			rootDir:         "./testdata/multi-level-workspace",
+			defaultBuild:    true,
+		},
+		{
+			name:    "Success/NoSourceDateEpoch",
+			rootDir: "./testdata/multi-level-workspace",
+		},
+		{
+			name:            "Success/WithSourceDateEpoch",
+			sourceDateEpoch: "1672531200",
+			rootDir:         "./testdata/multi-level-workspace",
+			dsConfig:        "./testdata/configs/dataset-config-basic.yaml",
+			expCreated:      "2023-01-01T00:00:00Z",
+		},
+		{
+			name:     "Success/DifferingFileModes",
+			dsConfig: "./testdata/configs/dataset-config-fileinfo.yaml",
+			fileModes: [2]map[string]os.FileMode{
+				{"bin/run.sh": 0700, "bin/tool": 0700, "data.txt": 0600},
+				{"bin/run.sh": 0775, "bin/tool": 0755, "data.txt": 0664},
+			},
+			expFileInfo: map[string]string{
+				"bin/run.sh": `{"gid":1000,"permissions":488,"uid":1000}`,
+				"bin/tool":   `{"gid":-1,"permissions":493,"uid":-1}`,
+				"data.txt":   `{"gid":-1,"permissions":420,"uid":-1}`,
+			},
+		},
+		{
+			name:            "Failure/InvalidSourceDateEpoch",
+			sourceDateEpoch: "yesterday",
+			rootDir:         "./testdata/multi-level-workspace",
+			expError:        "invalid SOURCE_DATE_EPOCH value \"yesterday\": must be an integer",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Setenv(sourceDateEpochEnv, c.sourceDateEpoch)
+			ctx := context.TODO()
+			reference := "localhost:5000/reproducible:latest"
+
+			var manifests []ocispec.Descriptor
+			for i := 0; i < 2; i++ {
+				cache := filepath.Join(t.TempDir(), "cache")
+				require.NoError(t, os.MkdirAll(cache, 0750))
+				rootDir := c.rootDir
+				if c.fileModes[i] != nil {
+					rootDir = t.TempDir()
+					for name, mode := range c.fileModes[i] {
+						path := filepath.Join(rootDir, name)
+						require.NoError(t, os.MkdirAll(filepath.Dir(path), 0750))
+						require.NoError(t, os.WriteFile(path, []byte(name), mode))
+						require.NoError(t, os.Chmod(path, mode))
+					}
+				}
+				opts := &BuildCollectionOptions{
+					BuildOptions: &BuildOptions{
+						Common: &options.Common{
+							Logger:   testlogr,
+							CacheDir: cache,
+						},
+						Destination: reference,
+					},
+					RootDir:      rootDir,
+					DSConfig:     c.dsConfig,
+					Reproducible: !c.defaultBuild,
+				}
+				err := opts.Run(ctx)
+				if c.expError != "" {
+					require.EqualError(t, err, c.expError)
+					return
+				}
+				require.NoError(t, err)
+
+				store, err := layout.New(cache)
+				require.NoError(t, err)
+				desc, err := store.Resolve(ctx, reference)
+				require.NoError(t, err)
+				manifests = append(manifests, desc)
+
+				if c.expFileInfo != nil {
+					rc, err := store.Fetch(ctx, desc)
+					require.NoError(t, err)
+					var manifest ocispec.Manifest
+					require.NoError(t, json.NewDecoder(rc).Decode(&manifest))
+					require.NoError(t, rc.Close())
+					fileInfo := map[string]string{}
+					for _, layer := range manifest.Layers {
+						var attrs map[string]json.RawMessage
+						require.NoError(t, json.Unmarshal([]byte(layer.Annotations[empspec.AnnotationEmporousAttributes]), &attrs))
+						fileInfo[layer.Annotations[ocispec.AnnotationTitle]] = string(attrs["core-file"])
+					}
+					require.Equal(t, c.expFileInfo, fileInfo)
+				}
+			}
+
+			require.Equal(t, manifests[0].Digest, manifests[1].Digest)
+			created, ok := manifests[0].Annotations[ocispec.AnnotationArtifactCreated]
+			if c.expCreated != "" {
+				require.True(t, ok)
+				require.Equal(t, c.expCreated, created)
+			} else {
+				require.False(t, ok)
+			}
+		})
+	}
+}
+
+func TestBuildCollectionSidecars(t *testing.T) {
+	testlogr, err := log.NewLogrusLogger(ioutil.Discard, "debug")
+	require.NoError(t, err)
+
+	ctx := context.TODO()
+	reference := "localhost:5000/sidecars:latest"
+	cache := filepath.Join(t.TempDir(), "cache")
+	require.NoError(t, os.MkdirAll(cache, 0750))
+	opts := &BuildCollectionOptions{
+		BuildOptions: &BuildOptions{
+			Common: &options.Common{
+				Logger:   testlogr,
+				CacheDir: cache,
+			},
+			Destination: reference,
+		},
+		RootDir:  "./testdata/sidecar-workspace",
+		DSConfig: "./testdata/configs/dataset-config-sidecars.yaml",
+	}
+	require.NoError(t, opts.Run(ctx))
+
+	store, err := layout.New(cache)
+	require.NoError(t, err)
+	desc, err := store.Resolve(ctx, reference)
+	require.NoError(t, err)
+	rc, err := store.Fetch(ctx, desc)
+	require.NoError(t, err)
+	defer rc.Close()
+	var manifest ocispec.Manifest
+	require.NoError(t, json.NewDecoder(rc).Decode(&manifest))
+
+	expected := map[string]string{
+		"images/fish.jpg":          `{"converted":{"org.opencontainers.image.title":"images/fish.jpg"},"unknown":{"animal":"fish","size":"small"}}`,
+		"info.json":                `{"converted":{"org.opencontainers.image.title":"info.json"},"unknown":{"animal":"unknown","size":"large"}}`,
+		"supplementary/about.json": `{"converted":{"org.opencontainers.image.title":"supplementary/about.json"},"unknown":{"animal":"unknown","size":"small"}}`,
+	}
+	actual := map[string]string{}
+	for _, layer := range manifest.Layers {
+		actual[layer.Annotations[ocispec.AnnotationTitle]] = layer.Annotations[empspec.AnnotationEmporousAttributes]
+	}
+	require.Equal(t, expected, actual)
+}
+
+func TestBuildCollectionImportSBOM(t *testing.T) {
+	testlogr, err := log.NewLogrusLogger(ioutil.Discard, "debug")
+	require.NoError(t, err)
+
+	ctx := context.TODO()
+	reference := "localhost:5000/importsbom:latest"
+	cache := filepath.Join(t.TempDir(), "cache")
+	require.NoError(t, os.MkdirAll(cache, 0750))
+	rootDir, err := filepath.Abs("./testdata/multi-level-workspace")
+	require.NoError(t, err)
+	dsConfig, err := filepath.Abs("./testdata/configs/dataset-config-importsbom.yaml")
+	require.NoError(t, err)
+	opts := &BuildCollectionOptions{
+		BuildOptions: &BuildOptions{
+			Common: &options.Common{
+				Logger:   testlogr,
+				CacheDir: cache,
+			},
+			Destination: reference,
+		},
+		RootDir:  rootDir,
+		DSConfig: dsConfig,
+	}
+
+	// The SBOM path is resolved against the dataset
+	// configuration, not the working directory.
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(t.TempDir()))
+	t.Cleanup(func() {
+		require.NoError(t, os.Chdir(wd))
+	})
+	require.NoError(t, opts.Run(ctx))
+
+	store, err := layout.New(cache)
+	require.NoError(t, err)
+	desc, err := store.Resolve(ctx, reference)
+	require.NoError(t, err)
+	rc, err := store.Fetch(ctx, desc)
+	require.NoError(t, err)
+	defer rc.Close()
+	var manifest ocispec.Manifest
+	require.NoError(t, json.NewDecoder(rc).Decode(&manifest))
+
+	// The dataset configuration version takes precedence
+	// over the version of the SBOM subject.
+	var manifestAttributes map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal([]byte(manifest.Annotations[empspec.AnnotationEmporousAttributes]), &manifestAttributes))
+	require.JSONEq(t, `{"id":"","name":"multi-level-workspace","version":"v2.0.0","type":"application","foundBy":"","locations":null,"licenses":["Apache-2.0"],"language":"","cpes":null,"purl":"pkg:generic/multi-level-workspace@v1.0.0"}`, string(manifestAttributes["core-descriptor"]))
+
+	expected := map[string]string{
+		"images/fish.jpg":          "fish-images",
+		"info.json":                "",
+		"supplementary/about.json": "docs/about.json",
+		"test.json":                "",
+	}
+	actual := map[string]string{}
+	for _, layer := range manifest.Layers {
+		var layerAttributes struct {
+			Descriptor *empspec.DescriptorAttributes `json:"core-descriptor"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(layer.Annotations[empspec.AnnotationEmporousAttributes]), &layerAttributes))
+		name := ""
+		if layerAttributes.Descriptor != nil {
+			name = layerAttributes.Descriptor.Name
+		}
+		actual[layer.Annotations[ocispec.AnnotationTitle]] = name
+	}
+	require.Equal(t, expected, actual)
+}
+
+func TestBuildCollectionLocked(t *testing.T) {
+	testlogr, err := log.NewLogrusLogger(ioutil.Discard, "debug")
+	require.NoError(t, err)
+
+	server := httptest.NewServer(registry.New())
+	t.Cleanup(server.Close)
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	ctx := context.TODO()
+	templateValues := prepCollectionArtifacts(t, u.Host)
+	initialConfig, err := ioutil.ReadFile("./testdata/configs/dataset-config-links.yaml")
+	require.NoError(t, err)
+	tpl, err := template.New("locked").Parse(string(initialConfig))
+	require.NoError(t, err)
+	configPath := filepath.Join(t.TempDir(), "test.yaml")
+	configFile, err := os.Create(configPath)
+	require.NoError(t, err)
+	require.NoError(t, tpl.Execute(configFile, templateValues))
+	require.NoError(t, configFile.Close())
+	lockPath := filepath.Join(filepath.Dir(configPath), "test.lock.yaml")
+
+	build := func(locked bool) error {
+		cache := filepath.Join(t.TempDir(), "cache")
+		require.NoError(t, os.MkdirAll(cache, 0750))
+		opts := &BuildCollectionOptions{
+			BuildOptions: &BuildOptions{
+				Common: &options.Common{
+					Logger:   testlogr,
+					CacheDir: cache,
+				},
+				Destination: fmt.Sprintf("%s/client-locked:latest", u.Host),
+			},
+			RootDir:  "./testdata/multi-level-workspace",
+			DSConfig: configPath,
+			Remote: options.Remote{
+				PlainHTTP: true,
+			},
+			NoVerify: true,
+			Locked:   locked,
+		}
+		require.NoError(t, opts.Validate())
+		return opts.Run(ctx)
+	}
+
+	// Locked builds require an existing lock file.
+	require.ErrorContains(t, build(true), fmt.Sprintf("lock file %s", lockPath))
+
+	// Building writes the lock file.
+	require.NoError(t, build(false))
+	lock, err := load.ReadLockFile(lockPath)
+	require.NoError(t, err)
+	require.Nil(t, lock.Schema)
+	require.Len(t, lock.Links, 1)
+	require.Equal(t, templateValues["linkedCollection"], lock.Links[0].Reference)
+	initialDigest := lock.Links[0].Digest
+	require.NoError(t, build(true))
+
+	// Moving the linked collection tag fails locked builds.
+	_, err = publishFunc("hello.txt", templateValues["linkedCollection"], []byte("Hello again!\n"), nil, nil)
+	require.NoError(t, err)
+	err = build(true)
+	require.ErrorContains(t, err, fmt.Sprintf("lock file %s does not match the dataset configuration: link %s: resolved to",
+		lockPath, templateValues["linkedCollection"]))
+	require.ErrorContains(t, err, fmt.Sprintf("locked to %s", initialDigest))
+
+	// Updating the links refreshes the pins.
+	out := new(bytes.Buffer)
+	updateOpts := &ConfigUpdateLinksOptions{
+		ConfigOptions: &ConfigOptions{
+			Common: &options.Common{
+				IOStreams: genericclioptions.IOStreams{
+					Out:    out,
+					In:     os.Stdin,
+					ErrOut: os.Stderr,
+				},
+				Logger: testlogr,
+			},
+		},
+		Remote: options.Remote{
+			PlainHTTP: true,
+		},
+	}
+	require.NoError(t, updateOpts.Complete([]string{configPath}))
+	require.NoError(t, updateOpts.Validate())
+	require.NoError(t, updateOpts.Run(ctx))
+	require.Contains(t, out.String(), fmt.Sprintf("link %s: resolved to", templateValues["linkedCollection"]))
+	lock, err = load.ReadLockFile(lockPath)
+	require.NoError(t, err)
+	require.NotEqual(t, initialDigest, lock.Links[0].Digest)
+	require.NoError(t, build(true))
+}
+
+func TestBuildCollectionDryRun(t *testing.T) {
+	testlogr, err := log.NewLogrusLogger(ioutil.Discard, "debug")
+	require.NoError(t, err)
+
+	server := httptest.NewServer(registry.New())
+	t.Cleanup(server.Close)
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	templateValues := prepCollectionArtifacts(t, u.Host)
+	renderConfig := func(path string) string {
+		initialConfig, err := ioutil.ReadFile(path)
+		require.NoError(t, err)
+		tpl, err := template.New("dryrun").Parse(string(initialConfig))
+		require.NoError(t, err)
+		configPath := filepath.Join(t.TempDir(), "test.yaml")
+		configFile, err := os.Create(configPath)
+		require.NoError(t, err)
+		require.NoError(t, tpl.Execute(configFile, templateValues))
+		require.NoError(t, configFile.Close())
+		return configPath
+	}
+	configPath := renderConfig("./testdata/configs/dataset-config-dryrun.yaml")
+	invalidConfigPath := renderConfig("./testdata/configs/dataset-config-dryrun-invalid.yaml")
+
+	type spec struct {
+		name       string
+		format     string
+		rootDir    string
+		dsConfig   string
+		assertFunc func(t *testing.T, out []byte)
+		expError   string
+		// expRunError is the error of a dry run
+		// that writes the build plan.
+		expRunError string
+	}
+
+	cases := []spec{
+		{
+			name:   "Success/JSON",
+			format: "json",
+			assertFunc: func(t *testing.T, out []byte) {
+				var plan buildPlan
+				require.NoError(t, json.Unmarshal(out, &plan))
+				require.Len(t, plan.Files, 4)
+				files := map[string]planFile{}
+				for _, file := range plan.Files {
+					files[file.Path] = file
+				}
+				info := files["info.json"]
+				require.True(t, info.Valid)
+				require.Empty(t, info.Error)
+				require.Equal(t, []string{"*", "*.json"}, info.Patterns)
+				require.NotNil(t, info.FileInfo)
+				require.Equal(t, uint32(0640), info.FileInfo.Permissions)
+				require.NotContains(t, info.Attributes, "core-file")
+				require.JSONEq(t, `{"size":2,"test":"testing"}`, string(info.Attributes["unknown"]))
+				fish := files["images/fish.jpg"]
+				require.Equal(t, []string{"*"}, fish.Patterns)
+				require.Nil(t, fish.FileInfo)
+
+				require.Len(t, plan.Links, 1)
+				require.Equal(t, templateValues["linkedCollection"], plan.Links[0].Reference)
+				require.NotEmpty(t, plan.Links[0].Digest)
+				require.NotNil(t, plan.Schema)
+				require.Equal(t, templateValues["schemaAddress"], plan.Schema.Address)
+				require.True(t, plan.Schema.Valid)
+			},
+		},
+		{
+			name:   "Success/Table",
+			format: "table",
+			assertFunc: func(t *testing.T, out []byte) {
+				require.Contains(t, string(out), fmt.Sprintf("Build plan for reference:  %s/client-dryrun:latest", u.Host))
+				require.Contains(t, string(out), "permissions=0640")
+				require.Contains(t, string(out), templateValues["linkedCollection"])
+				require.Contains(t, string(out), "valid")
+			},
+		},
+		{
+			name:     "Failure/InvalidAttributesJSON",
+			format:   "json",
+			rootDir:  "./testdata/sidecar-workspace",
+			dsConfig: invalidConfigPath,
+			assertFunc: func(t *testing.T, out []byte) {
+				var plan buildPlan
+				require.NoError(t, json.Unmarshal(out, &plan))
+				require.Len(t, plan.Files, 3)
+				files := map[string]planFile{}
+				for _, file := range plan.Files {
+					files[file.Path] = file
+				}
+				fish := files["images/fish.jpg"]
+				require.False(t, fish.Valid)
+				require.Contains(t, fish.Error, "test is required")
+				require.True(t, files["info.json"].Valid)
+				require.True(t, files["supplementary/about.json"].Valid)
+				require.NotNil(t, plan.Schema)
+				require.False(t, plan.Schema.Valid)
+				require.Empty(t, plan.Schema.Error)
+			},
+			expRunError: fmt.Sprintf("attributes are not valid for schema %s: images/fish.jpg: file images/fish.jpg: schema validation error: (root): test is required", templateValues["schemaAddress"]),
+		},
+		{
+			name:     "Failure/InvalidAttributesTable",
+			format:   "table",
+			rootDir:  "./testdata/sidecar-workspace",
+			dsConfig: invalidConfigPath,
+			assertFunc: func(t *testing.T, out []byte) {
+				require.Contains(t, string(out), "invalid: file images/fish.jpg: schema validation error: (root): test is required")
+			},
+			expRunError: fmt.Sprintf("attributes are not valid for schema %s: images/fish.jpg: file images/fish.jpg: schema validation error: (root): test is required", templateValues["schemaAddress"]),
+		},
+		{
+			name:     "Failure/UnsupportedFormat",
+			format:   "yaml",
+			expError: "unsupported output format \"yaml\": must be one of table, json",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			out := new(bytes.Buffer)
+			cache := filepath.Join(t.TempDir(), "cache")
+			rootDir := c.rootDir
+			if rootDir == "" {
+				rootDir = "./testdata/multi-level-workspace"
+			}
+			dsConfig := c.dsConfig
+			if dsConfig == "" {
+				dsConfig = configPath
+			}
+			opts := &BuildCollectionOptions{
+				BuildOptions: &BuildOptions{
+					Common: &options.Common{
+						IOStreams: genericclioptions.IOStreams{
+							Out:    out,
+							In:     os.Stdin,
+							ErrOut: os.Stderr,
+						},
+						Logger:   testlogr,
+						CacheDir: cache,
+					},
+					Destination: fmt.Sprintf("%s/client-dryrun:latest", u.Host),
+				},
+				RootDir:  rootDir,
+				DSConfig: dsConfig,
+				Remote: options.Remote{
+					PlainHTTP: true,
+				},
+				NoVerify: true,
+				DryRun:   true,
+				Format:   c.format,
+			}
+			err := opts.Validate()
+			if c.expError != "" {
+				require.EqualError(t, err, c.expError)
+				return
+			}
+			require.NoError(t, err)
+			err = opts.Run(context.TODO())
+			if c.expRunError != "" {
+				require.EqualError(t, err, c.expRunError)
+			} else {
+				require.NoError(t, err)
+			}
+			c.assertFunc(t, out.Bytes())
+
+			// Dry runs do not write to the cache or the lock file.
+			_, err = os.Stat(cache)
+			require.True(t, os.IsNotExist(err))
+			_, err = os.Stat(load.LockFilePath(dsConfig))
+			require.True(t, os.IsNotExist(err))
+		})
+	}
+}
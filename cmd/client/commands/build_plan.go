@@ -0,0 +1,318 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	empspec "github.com/emporous/collection-spec/specs-go/v1alpha1"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	orascontent "oras.land/oras-go/v2/content"
+
+	"github.com/emporous/emporous-go/api/client/v1alpha1"
+	load "github.com/emporous/emporous-go/config"
+	"github.com/emporous/emporous-go/content"
+	"github.com/emporous/emporous-go/manager"
+	"github.com/emporous/emporous-go/nodes/descriptor"
+)
+
+// Supported build plan output formats.
+const (
+	planFormatTable = "table"
+	planFormatJSON  = "json"
+)
+
+// buildPlan describes the collection produced by a build.
+type buildPlan struct {
+	Reference string      `json:"reference"`
+	Files     []planFile  `json:"files"`
+	Links     []planLink  `json:"links,omitempty"`
+	Schema    *planSchema `json:"schema,omitempty"`
+}
+
+// planFile describes a file added to the collection.
+type planFile struct {
+	Variant   string `json:"variant,omitempty"`
+	Path      string `json:"path"`
+	MediaType string `json:"mediaType"`
+	Digest    string `json:"digest"`
+	Size      int64  `json:"size"`
+	// Patterns are the dataset configuration file patterns matching the file.
+	Patterns []string `json:"patterns,omitempty"`
+	// Attributes are the merged attributes of the file keyed by schema ID.
+	Attributes map[string]json.RawMessage `json:"attributes,omitempty"`
+	// FileInfo is the core-file information of the file.
+	FileInfo *empspec.File `json:"fileInfo,omitempty"`
+	// Valid is whether the attributes of the file are valid for the collection schema.
+	Valid bool `json:"valid"`
+	// Error is the schema validation error of the file, if any.
+	Error string `json:"error,omitempty"`
+}
+
+// planLink describes a linked collection and its resolved digest.
+type planLink struct {
+	Reference string `json:"reference"`
+	Digest    string `json:"digest"`
+}
+
+// planSchema describes the collection schema and the validation result.
+type planSchema struct {
+	Address string `json:"address"`
+	Digest  string `json:"digest"`
+	Valid   bool   `json:"valid"`
+	// Error is the schema validation error of the
+	// dataset configuration attributes, if any.
+	Error string `json:"error,omitempty"`
+}
+
+// newBuildPlan creates a build plan from the collection built under the reference
+// in the store. The resolved links and schema are read from the lock. Files with
+// schema validation failures in invalid are reported as not valid.
+func newBuildPlan(ctx context.Context, store content.Store, reference string, config v1alpha1.DataSetConfiguration, lock *v1alpha1.LockFile, invalid *manager.ErrSchemaValidation) (buildPlan, error) {
+	plan := buildPlan{Reference: reference}
+
+	// Validation errors are keyed by variant and file.
+	fileErrors := map[[2]string]string{}
+	if invalid != nil {
+		for _, file := range invalid.Files {
+			fileErrors[[2]string{file.Variant, file.File}] = file.Err.Error()
+		}
+	}
+
+	var patterns []*load.Pattern
+	for _, file := range config.Collection.Files {
+		pattern, err := load.CompilePattern(file.File)
+		if err != nil {
+			return plan, err
+		}
+		patterns = append(patterns, pattern)
+	}
+
+	root, err := store.Resolve(ctx, reference)
+	if err != nil {
+		return plan, err
+	}
+	manifests := []ocispec.Descriptor{root}
+	if root.MediaType == ocispec.MediaTypeImageIndex {
+		data, err := orascontent.FetchAll(ctx, store, root)
+		if err != nil {
+			return plan, err
+		}
+		var index ocispec.Index
+		if err := json.Unmarshal(data, &index); err != nil {
+			return plan, err
+		}
+		manifests = index.Manifests
+	}
+
+	for _, manifestDesc := range manifests {
+		data, err := orascontent.FetchAll(ctx, store, manifestDesc)
+		if err != nil {
+			return plan, err
+		}
+		var manifest ocispec.Manifest
+		if err := json.Unmarshal(data, &manifest); err != nil {
+			return plan, err
+		}
+		for _, layer := range manifest.Layers {
+			file, ok, err := newPlanFile(layer, patterns)
+			if err != nil {
+				return plan, err
+			}
+			if !ok {
+				continue
+			}
+			file.Variant = manifestDesc.Annotations[descriptor.AnnotationVariant]
+			file.Error = fileErrors[[2]string{file.Variant, file.Path}]
+			file.Valid = file.Error == ""
+			plan.Files = append(plan.Files, file)
+		}
+	}
+
+	if lock != nil {
+		for _, link := range lock.Links {
+			plan.Links = append(plan.Links, planLink{Reference: link.Reference, Digest: link.Digest})
+		}
+		if lock.Schema != nil {
+			plan.Schema = &planSchema{
+				Address: lock.Schema.Reference,
+				Digest:  lock.Schema.Digest,
+				Valid:   invalid == nil,
+			}
+			// Dataset configuration errors are not
+			// specific to a file or variant.
+			for key, err := range fileErrors {
+				if key[1] == "" {
+					plan.Schema.Error = err
+					break
+				}
+			}
+		}
+	}
+	return plan, nil
+}
+
+// newPlanFile creates a planFile for a titled layer. The
+// core-file information is separated from the other attributes.
+func newPlanFile(layer ocispec.Descriptor, patterns []*load.Pattern) (planFile, bool, error) {
+	location, ok := layer.Annotations[ocispec.AnnotationTitle]
+	if !ok {
+		return planFile{}, false, nil
+	}
+	file := planFile{
+		Path:      location,
+		MediaType: layer.MediaType,
+		Digest:    layer.Digest.String(),
+		Size:      layer.Size,
+	}
+	for _, pattern := range patterns {
+		if pattern.Match(location) {
+			file.Patterns = append(file.Patterns, pattern.String())
+		}
+	}
+
+	attrs, ok := layer.Annotations[empspec.AnnotationEmporousAttributes]
+	if !ok {
+		return file, true, nil
+	}
+	if err := json.Unmarshal([]byte(attrs), &file.Attributes); err != nil {
+		return planFile{}, false, fmt.Errorf("file %s: %w", location, err)
+	}
+	if fileInfo, ok := file.Attributes[descriptor.TypeFile]; ok {
+		file.FileInfo = &empspec.File{}
+		if err := json.Unmarshal(fileInfo, file.FileInfo); err != nil {
+			return planFile{}, false, fmt.Errorf("file %s: %w", location, err)
+		}
+		delete(file.Attributes, descriptor.TypeFile)
+	}
+	return file, true, nil
+}
+
+// schemaValidationFailures returns the schema validation failures of a build
+// error. Other errors are returned.
+func schemaValidationFailures(err error) (*manager.ErrSchemaValidation, error) {
+	var validationErr *manager.ErrSchemaValidation
+	if errors.As(err, &validationErr) {
+		return validationErr, nil
+	}
+	return nil, err
+}
+
+// write writes the build plan to w in the specified format.
+func (p buildPlan) write(w io.Writer, format string) error {
+	switch format {
+	case planFormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(p)
+	case planFormatTable:
+		return p.writeTable(w)
+	default:
+		return fmt.Errorf("unsupported output format %q: must be one of %s, %s", format, planFormatTable, planFormatJSON)
+	}
+}
+
+func (p buildPlan) writeTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	if _, err := fmt.Fprintf(tw, "Build plan for reference:\t%s\n", p.Reference); err != nil {
+		return err
+	}
+
+	hasVariants := false
+	for _, file := range p.Files {
+		if file.Variant != "" {
+			hasVariants = true
+			break
+		}
+	}
+	header := "Name\tMediaType\tPatterns\tAttributes\tFileInfo"
+	if hasVariants {
+		header = "Variant\t" + header
+	}
+	if p.Schema != nil {
+		header += "\tValidation"
+	}
+	if _, err := fmt.Fprintln(tw, header); err != nil {
+		return err
+	}
+	for _, file := range p.Files {
+		patterns := "None"
+		if len(file.Patterns) != 0 {
+			patterns = strings.Join(file.Patterns, ",")
+		}
+		attrs := "None"
+		if len(file.Attributes) != 0 {
+			attrJSON, err := json.Marshal(file.Attributes)
+			if err != nil {
+				return err
+			}
+			attrs = string(attrJSON)
+		}
+		row := fmt.Sprintf("%s\t%s\t%s\t%s\t%s", file.Path, file.MediaType, patterns, attrs, formatFileInfo(file.FileInfo))
+		if hasVariants {
+			row = file.Variant + "\t" + row
+		}
+		if p.Schema != nil {
+			row += "\t" + formatValidation(file.Valid, file.Error)
+		}
+		if _, err := fmt.Fprintln(tw, row); err != nil {
+			return err
+		}
+	}
+
+	if len(p.Links) != 0 {
+		if _, err := fmt.Fprintln(tw, "\nLink\tDigest"); err != nil {
+			return err
+		}
+		for _, link := range p.Links {
+			if _, err := fmt.Fprintf(tw, "%s\t%s\n", link.Reference, link.Digest); err != nil {
+				return err
+			}
+		}
+	}
+
+	if p.Schema != nil {
+		result := formatValidation(p.Schema.Valid, p.Schema.Error)
+		if _, err := fmt.Fprintf(tw, "\nSchema\tDigest\tValidation\n%s\t%s\t%s\n", p.Schema.Address, p.Schema.Digest, result); err != nil {
+			return err
+		}
+	}
+	return tw.Flush()
+}
+
+// formatValidation formats a schema validation result for table output.
+func formatValidation(valid bool, validationErr string) string {
+	switch {
+	case valid:
+		return "valid"
+	case validationErr != "":
+		return "invalid: " + validationErr
+	default:
+		return "invalid"
+	}
+}
+
+// formatFileInfo formats core-file information for table output.
+func formatFileInfo(info *empspec.File) string {
+	if info == nil {
+		return "None"
+	}
+	var fields []string
+	if info.Permissions != 0 {
+		fields = append(fields, fmt.Sprintf("permissions=%#o", info.Permissions))
+	}
+	if info.UID >= 0 {
+		fields = append(fields, fmt.Sprintf("uid=%d", info.UID))
+	}
+	if info.GID >= 0 {
+		fields = append(fields, fmt.Sprintf("gid=%d", info.GID))
+	}
+	if len(fields) == 0 {
+		return "None"
+	}
+	return strings.Join(fields, " ")
+}
@@ -175,6 +175,54 @@ func TestCLIE2E(t *testing.T) {
 				NoVerify: true,
 			},
 		},
+		{
+			name: "Success/Variants",
+			buildOpts: &BuildCollectionOptions{
+				BuildOptions: &BuildOptions{
+					Common: &options.Common{
+						IOStreams: genericclioptions.IOStreams{
+							Out:    os.Stdout,
+							In:     os.Stdin,
+							ErrOut: os.Stderr,
+						},
+						Logger: testlogr,
+					},
+					Destination: fmt.Sprintf("%s/client-variants-test:latest", u.Host),
+				},
+				RootDir:  "testdata/multi-level-workspace",
+				DSConfig: "testdata/configs/dataset-config-variants.yaml",
+			},
+			pushOpts: &PushOptions{
+				Common: &options.Common{
+					IOStreams: genericclioptions.IOStreams{
+						Out:    os.Stdout,
+						In:     os.Stdin,
+						ErrOut: os.Stderr,
+					},
+					Logger: testlogr,
+				},
+				Destination: fmt.Sprintf("%s/client-variants-test:latest", u.Host),
+				Remote: options.Remote{
+					PlainHTTP: true,
+				},
+			},
+			pullOpts: &PullOptions{
+				Common: &options.Common{
+					IOStreams: genericclioptions.IOStreams{
+						Out:    os.Stdout,
+						In:     os.Stdin,
+						ErrOut: os.Stderr,
+					},
+					Logger: testlogr,
+				},
+				Output: t.TempDir(),
+				Remote: options.Remote{
+					PlainHTTP: true,
+				},
+				NoVerify: true,
+				Platform: "linux/arm64",
+			},
+		},
 	}
 
 	for _, c := range cases {
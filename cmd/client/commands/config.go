@@ -0,0 +1,33 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/emporous/emporous-go/cmd/client/commands/options"
+)
+
+// ConfigOptions describe configuration options that can
+// be set using the config subcommand.
+type ConfigOptions struct {
+	*options.Common
+}
+
+// NewConfigCmd creates a new cobra.Command for the config subcommand.
+func NewConfigCmd(common *options.Common) *cobra.Command {
+	o := ConfigOptions{Common: common}
+
+	cmd := &cobra.Command{
+		Use:           "config",
+		Short:         "Work with dataset configurations",
+		SilenceErrors: false,
+		SilenceUsage:  false,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(NewConfigValidateCmd(&o))
+	cmd.AddCommand(NewConfigUpdateLinksCmd(&o))
+
+	return cmd
+}
@@ -0,0 +1,128 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/emporous/emporous-go/api/client/v1alpha1"
+	"github.com/emporous/emporous-go/cmd/client/commands/options"
+	load "github.com/emporous/emporous-go/config"
+	"github.com/emporous/emporous-go/registryclient/orasclient"
+	"github.com/emporous/emporous-go/util/examples"
+)
+
+// ConfigUpdateLinksOptions describe configuration options that can
+// be set using the config update-links subcommand.
+type ConfigUpdateLinksOptions struct {
+	*ConfigOptions
+	options.Remote
+	options.RemoteAuth
+	// Dataset Config
+	DSConfig string
+	LockFile string
+}
+
+var clientConfigUpdateLinksExamples = []examples.Example{
+	{
+		RootCommand:   filepath.Base(os.Args[0]),
+		Descriptions:  []string{"Pin the linked collections and schema of a dataset configuration to their current digests."},
+		CommandString: "config update-links dataset-config.yaml",
+	},
+}
+
+// NewConfigUpdateLinksCmd creates a new cobra.Command for the config update-links subcommand.
+func NewConfigUpdateLinksCmd(configOpts *ConfigOptions) *cobra.Command {
+	o := ConfigUpdateLinksOptions{ConfigOptions: configOpts}
+
+	cmd := &cobra.Command{
+		Use:           "update-links CFG-PATH",
+		Short:         "Update the lock file with the current digests of linked collections and the schema",
+		Example:       examples.FormatExamples(clientConfigUpdateLinksExamples...),
+		SilenceErrors: false,
+		SilenceUsage:  false,
+		Args:          cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			cobra.CheckErr(o.Complete(args))
+			cobra.CheckErr(o.Validate())
+			cobra.CheckErr(o.Run(cmd.Context()))
+		},
+	}
+
+	o.Remote.BindFlags(cmd.Flags())
+	o.RemoteAuth.BindFlags(cmd.Flags())
+
+	cmd.Flags().StringVar(&o.LockFile, "lockfile", o.LockFile, "lock file path. Defaults to the dataset configuration path with a .lock.yaml extension")
+
+	return cmd
+}
+
+func (o *ConfigUpdateLinksOptions) Complete(args []string) error {
+	if len(args) < 1 {
+		return errors.New("bug: expecting one argument")
+	}
+	o.DSConfig = args[0]
+	if o.LockFile == "" {
+		o.LockFile = load.LockFilePath(o.DSConfig)
+	}
+	return nil
+}
+
+func (o *ConfigUpdateLinksOptions) Validate() error {
+	if _, err := os.Stat(o.DSConfig); err != nil {
+		return fmt.Errorf("dataset configuration %q: %v", o.DSConfig, err)
+	}
+	return nil
+}
+
+func (o *ConfigUpdateLinksOptions) Run(ctx context.Context) error {
+	config, err := load.ReadDataSetConfig(o.DSConfig)
+	if err != nil {
+		return err
+	}
+
+	client, err := orasclient.NewClient(
+		orasclient.SkipTLSVerify(o.Insecure),
+		orasclient.WithAuthConfigs(o.Configs),
+		orasclient.WithPlainHTTP(o.PlainHTTP),
+	)
+	if err != nil {
+		return fmt.Errorf("error configuring client: %v", err)
+	}
+	defer func() {
+		if err := client.Destroy(); err != nil {
+			o.Logger.Errorf(err.Error())
+		}
+	}()
+
+	resolved, err := resolveLock(ctx, client, config)
+	if err != nil {
+		return err
+	}
+
+	var previous v1alpha1.LockFile
+	if _, err := os.Stat(o.LockFile); err == nil {
+		previous, err = load.ReadLockFile(o.LockFile)
+		if err != nil {
+			return fmt.Errorf("lock file %s: %w", o.LockFile, err)
+		}
+	}
+	diffs := load.DiffLockFile(previous, resolved)
+	for _, diff := range diffs {
+		fmt.Fprintln(o.IOStreams.Out, diff)
+	}
+
+	if err := load.WriteLockFile(o.LockFile, resolved); err != nil {
+		return err
+	}
+	if len(diffs) == 0 {
+		o.Logger.Infof("Lock file %s is up to date", o.LockFile)
+	} else {
+		o.Logger.Infof("Lock file written to %s", o.LockFile)
+	}
+	return nil
+}
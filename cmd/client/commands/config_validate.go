@@ -0,0 +1,186 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	clientapi "github.com/emporous/emporous-go/api/client/v1alpha1"
+	load "github.com/emporous/emporous-go/config"
+	"github.com/emporous/emporous-go/util/examples"
+	"github.com/emporous/emporous-go/util/workspace"
+)
+
+// ConfigValidateOptions describe configuration options that can
+// be set using the config validate subcommand.
+type ConfigValidateOptions struct {
+	*ConfigOptions
+	// Dataset Config
+	DSConfig string
+	RootDir  string
+}
+
+var clientConfigValidateExamples = []examples.Example{
+	{
+		RootCommand:   filepath.Base(os.Args[0]),
+		Descriptions:  []string{"Validate a dataset configuration against a workspace."},
+		CommandString: "config validate dataset-config.yaml my-directory",
+	},
+}
+
+// NewConfigValidateCmd creates a new cobra.Command for the config validate subcommand.
+func NewConfigValidateCmd(configOpts *ConfigOptions) *cobra.Command {
+	o := ConfigValidateOptions{ConfigOptions: configOpts}
+
+	cmd := &cobra.Command{
+		Use:           "validate CFG-PATH SRC",
+		Short:         "Report file patterns that match no files and conflicting file information",
+		Example:       examples.FormatExamples(clientConfigValidateExamples...),
+		SilenceErrors: false,
+		SilenceUsage:  false,
+		Args:          cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			cobra.CheckErr(o.Complete(args))
+			cobra.CheckErr(o.Validate())
+			cobra.CheckErr(o.Run(cmd.Context()))
+		},
+	}
+
+	return cmd
+}
+
+func (o *ConfigValidateOptions) Complete(args []string) error {
+	if len(args) < 2 {
+		return errors.New("bug: expecting two arguments")
+	}
+	o.DSConfig = args[0]
+	o.RootDir = args[1]
+	return nil
+}
+
+func (o *ConfigValidateOptions) Validate() error {
+	if _, err := os.Stat(o.RootDir); err != nil {
+		return fmt.Errorf("workspace directory %q: %v", o.RootDir, err)
+	}
+	return nil
+}
+
+func (o *ConfigValidateOptions) Run(_ context.Context) error {
+	config, err := load.ReadDataSetConfig(o.DSConfig)
+	if err != nil {
+		return err
+	}
+
+	space, err := workspace.NewLocalWorkspace(o.RootDir)
+	if err != nil {
+		return err
+	}
+
+	// Variant file patterns are matched relative
+	// to each variant workspace.
+	workspaces := []string{"."}
+	if len(config.Collection.Variants) != 0 {
+		workspaces = nil
+		for _, variant := range config.Collection.Variants {
+			if _, err := os.Stat(filepath.Join(o.RootDir, variant.Workspace)); err != nil {
+				return fmt.Errorf("variant %q: %v", variant.Name, err)
+			}
+			workspaces = append(workspaces, variant.Workspace)
+		}
+	}
+
+	var report load.ValidationReport
+	unmatchedCount := map[string]int{}
+	for _, dir := range workspaces {
+		files, err := walkFiles(space, dir)
+		if err != nil {
+			return err
+		}
+		r, err := load.ValidateDataSetConfig(config, files)
+		if err != nil {
+			return err
+		}
+		for _, pattern := range r.UnmatchedPatterns {
+			unmatchedCount[pattern]++
+		}
+		for _, conflict := range r.FileInfoConflicts {
+			conflict.File = path.Join(filepath.ToSlash(dir), conflict.File)
+			report.FileInfoConflicts = append(report.FileInfoConflicts, conflict)
+		}
+	}
+	for pattern, count := range unmatchedCount {
+		if count == len(workspaces) {
+			report.UnmatchedPatterns = append(report.UnmatchedPatterns, pattern)
+		}
+	}
+	sort.Strings(report.UnmatchedPatterns)
+
+	if err := formatValidationReport(o.IOStreams.Out, report); err != nil {
+		return err
+	}
+	if report.HasIssues() {
+		return fmt.Errorf("dataset configuration %q: found %d issue(s)", o.DSConfig, len(report.UnmatchedPatterns)+len(report.FileInfoConflicts))
+	}
+	return nil
+}
+
+// walkFiles returns the paths of the regular files under
+// a directory in the workspace relative to the directory.
+func walkFiles(space workspace.Workspace, dir string) ([]string, error) {
+	if dir != "." {
+		var err error
+		space, err = space.NewDirectory(dir)
+		if err != nil {
+			return nil, err
+		}
+	}
+	var files []string
+	err := space.Walk(func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return fmt.Errorf("traversing %s: %v", path, err)
+		}
+		// Directory attribute sidecar files are not
+		// added to the collection.
+		if info.Mode().IsRegular() && filepath.Base(path) != clientapi.DirectoryAttributesFileName {
+			files = append(files, path)
+		}
+		return nil
+	})
+	return files, err
+}
+
+func formatValidationReport(w io.Writer, report load.ValidationReport) error {
+	if !report.HasIssues() {
+		_, err := fmt.Fprintln(w, "Dataset configuration is valid")
+		return err
+	}
+	if len(report.UnmatchedPatterns) != 0 {
+		if _, err := fmt.Fprintln(w, "Patterns matching no files:"); err != nil {
+			return err
+		}
+		for _, pattern := range report.UnmatchedPatterns {
+			if _, err := fmt.Fprintf(w, "  %s\n", pattern); err != nil {
+				return err
+			}
+		}
+	}
+	if len(report.FileInfoConflicts) != 0 {
+		if _, err := fmt.Fprintln(w, "Files matched by conflicting file information:"); err != nil {
+			return err
+		}
+		for _, conflict := range report.FileInfoConflicts {
+			if _, err := fmt.Fprintf(w, "  %s: %s\n", conflict.File, strings.Join(conflict.Patterns, ", ")); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
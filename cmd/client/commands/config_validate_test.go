@@ -0,0 +1,72 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/emporous/emporous-go/cmd/client/commands/options"
+)
+
+func TestConfigValidateRun(t *testing.T) {
+	type spec struct {
+		name      string
+		opts      *ConfigValidateOptions
+		expOutput string
+		expError  string
+	}
+
+	cases := []spec{
+		{
+			name: "Success/Valid",
+			opts: &ConfigValidateOptions{
+				DSConfig: "testdata/configs/dataset-config-basic.yaml",
+				RootDir:  "testdata/multi-level-workspace",
+			},
+			expOutput: "Dataset configuration is valid\n",
+		},
+		{
+			name: "Success/ValidVariants",
+			opts: &ConfigValidateOptions{
+				DSConfig: "testdata/configs/dataset-config-variants.yaml",
+				RootDir:  "testdata/multi-level-workspace",
+			},
+			expOutput: "Dataset configuration is valid\n",
+		},
+		{
+			name: "Failure/Issues",
+			opts: &ConfigValidateOptions{
+				DSConfig: "testdata/configs/dataset-config-conflicts.yaml",
+				RootDir:  "testdata/multi-level-workspace",
+			},
+			expOutput: "Patterns matching no files:\n" +
+				"  *.txt\n" +
+				"Files matched by conflicting file information:\n" +
+				"  supplementary/about.json: *.json, supplementary/**\n",
+			expError: "dataset configuration \"testdata/configs/dataset-config-conflicts.yaml\": found 2 issue(s)",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			out := new(bytes.Buffer)
+			c.opts.ConfigOptions = &ConfigOptions{
+				Common: &options.Common{
+					IOStreams: genericclioptions.IOStreams{
+						Out: out,
+					},
+				},
+			}
+			err := c.opts.Run(context.TODO())
+			if c.expError != "" {
+				require.EqualError(t, err, c.expError)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, c.expOutput, out.String())
+		})
+	}
+}
@@ -0,0 +1,184 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/spf13/cobra"
+	"oras.land/oras-go/v2/registry"
+
+	"github.com/emporous/emporous-go/cmd/client/commands/options"
+	"github.com/emporous/emporous-go/registryclient"
+	"github.com/emporous/emporous-go/registryclient/orasclient"
+	"github.com/emporous/emporous-go/util/examples"
+)
+
+// CopyOptions describe configuration options that can
+// be set using the copy subcommand.
+type CopyOptions struct {
+	*options.Common
+	options.Remote
+	options.RemoteAuth
+	options.Transfer
+	Source      string
+	Destination string
+	Links       bool
+	// NamespaceMappings map namespaces of linked collections in the form
+	// OLD=NEW. Namespaces that are not mapped are kept.
+	NamespaceMappings []string
+	namespaces        []namespaceMapping
+}
+
+// namespaceMapping maps a source namespace and any
+// namespaces nested under it to a destination namespace.
+type namespaceMapping struct {
+	source      string
+	destination string
+}
+
+var clientCopyExamples = []examples.Example{
+	{
+		RootCommand:   filepath.Base(os.Args[0]),
+		CommandString: "copy staging.example.com/test:latest prod.example.com/test:latest",
+		Descriptions: []string{
+			"Copy a collection between registries.",
+		},
+	},
+	{
+		RootCommand:   filepath.Base(os.Args[0]),
+		CommandString: "copy staging.example.com/apps/test:latest prod.example.com/apps/test:latest --links --map-namespace staging=prod",
+		Descriptions: []string{
+			"Copy a collection and its linked collections to the destination registry, mapping the staging namespace to prod.",
+		},
+	},
+}
+
+// NewCopyCmd creates a new cobra.Command for the copy subcommand.
+func NewCopyCmd(common *options.Common) *cobra.Command {
+	o := CopyOptions{Common: common}
+
+	cmd := &cobra.Command{
+		Use:           "copy SRC DST",
+		Short:         "Copy a Emporous collection between registries",
+		Example:       examples.FormatExamples(clientCopyExamples...),
+		SilenceErrors: false,
+		SilenceUsage:  false,
+		Args:          cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			cobra.CheckErr(o.Complete(args))
+			cobra.CheckErr(o.Validate())
+			cobra.CheckErr(o.Run(cmd.Context()))
+		},
+	}
+
+	o.Remote.BindFlags(cmd.Flags())
+	o.RemoteAuth.BindFlags(cmd.Flags())
+	o.Transfer.BindFlags(cmd.Flags())
+
+	cmd.Flags().BoolVar(&o.Links, "links", o.Links, "copy linked collections to the destination registry and rewrite the link hints")
+	cmd.Flags().StringArrayVar(&o.NamespaceMappings, "map-namespace", o.NamespaceMappings, "map the namespace of linked collections in the form OLD=NEW when copying links")
+
+	return cmd
+}
+
+func (o *CopyOptions) Complete(args []string) error {
+	if len(args) < 2 {
+		return errors.New("bug: expecting two arguments")
+	}
+	o.Source = args[0]
+	o.Destination = args[1]
+	return nil
+}
+
+func (o *CopyOptions) Validate() error {
+	if err := o.Transfer.Validate(); err != nil {
+		return err
+	}
+	if len(o.NamespaceMappings) != 0 && !o.Links {
+		return errors.New("--map-namespace requires --links")
+	}
+	o.namespaces = nil
+	for _, mapping := range o.NamespaceMappings {
+		parts := strings.SplitN(mapping, "=", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return fmt.Errorf("namespace mapping %q: must be in the form OLD=NEW", mapping)
+		}
+		o.namespaces = append(o.namespaces, namespaceMapping{
+			source:      strings.Trim(parts[0], "/"),
+			destination: strings.Trim(parts[1], "/"),
+		})
+	}
+	return nil
+}
+
+func (o *CopyOptions) Run(ctx context.Context) error {
+	clientOpts := []orasclient.ClientOption{
+		orasclient.SkipTLSVerify(o.Insecure),
+		orasclient.WithAuthConfigs(o.Configs),
+		orasclient.WithPlainHTTP(o.PlainHTTP),
+	}
+	transferOpts, err := transferOptions(o.Transfer)
+	if err != nil {
+		return err
+	}
+	clientOpts = append(clientOpts, transferOpts...)
+
+	client, err := orasclient.NewClient(clientOpts...)
+	if err != nil {
+		return fmt.Errorf("error configuring client: %v", err)
+	}
+	defer func() {
+		if err := client.Destroy(); err != nil {
+			o.Logger.Errorf(err.Error())
+		}
+	}()
+
+	var desc ocispec.Descriptor
+	if o.Links {
+		destination, err := registry.ParseReference(o.Destination)
+		if err != nil {
+			return err
+		}
+		desc, err = client.CopyWithLinks(ctx, o.Source, o.Destination, o.linkMapper(destination.Registry))
+		if err != nil {
+			return err
+		}
+	} else {
+		desc, err = client.Copy(ctx, o.Source, o.Destination)
+		if err != nil {
+			return err
+		}
+	}
+
+	o.Logger.Infof("Artifact %s copied to %s", desc.Digest, o.Destination)
+	return nil
+}
+
+// linkMapper returns a LinkMapper that moves linked collections into the destination
+// registry. The first namespace mapping matching the link namespace is applied.
+func (o *CopyOptions) linkMapper(destinationRegistry string) registryclient.LinkMapper {
+	return func(repository string) (string, error) {
+		ref, err := registry.ParseReference(repository)
+		if err != nil {
+			return "", err
+		}
+		namespace := ref.Repository
+		for _, mapping := range o.namespaces {
+			if namespace == mapping.source {
+				namespace = mapping.destination
+				break
+			}
+			if strings.HasPrefix(namespace, mapping.source+"/") {
+				namespace = mapping.destination + strings.TrimPrefix(namespace, mapping.source)
+				break
+			}
+		}
+		o.Logger.Debugf("Copying linked collection from %s to %s/%s", repository, destinationRegistry, namespace)
+		return fmt.Sprintf("%s/%s", destinationRegistry, namespace), nil
+	}
+}
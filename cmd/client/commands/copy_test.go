@@ -0,0 +1,229 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"text/template"
+
+	empspec "github.com/emporous/collection-spec/specs-go/v1alpha1"
+	"github.com/google/go-containerregistry/pkg/registry"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+	orascontent "oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/registry/remote"
+
+	"github.com/emporous/emporous-go/cmd/client/commands/options"
+	"github.com/emporous/emporous-go/log"
+	"github.com/emporous/emporous-go/nodes/descriptor/v2"
+)
+
+func TestCopyValidate(t *testing.T) {
+	type spec struct {
+		name     string
+		opts     *CopyOptions
+		expError string
+	}
+
+	cases := []spec{
+		{
+			name: "Valid/NamespaceMapping",
+			opts: &CopyOptions{
+				Links:             true,
+				NamespaceMappings: []string{"staging=prod"},
+			},
+		},
+		{
+			name: "Invalid/MappingWithoutLinks",
+			opts: &CopyOptions{
+				NamespaceMappings: []string{"staging=prod"},
+			},
+			expError: "--map-namespace requires --links",
+		},
+		{
+			name: "Invalid/MappingFormat",
+			opts: &CopyOptions{
+				Links:             true,
+				NamespaceMappings: []string{"staging"},
+			},
+			expError: `namespace mapping "staging": must be in the form OLD=NEW`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := c.opts.Validate()
+			if c.expError != "" {
+				require.EqualError(t, err, c.expError)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCopyRun(t *testing.T) {
+	testlogr, err := log.NewLogrusLogger(ioutil.Discard, "debug")
+	require.NoError(t, err)
+
+	server := httptest.NewServer(registry.New())
+	t.Cleanup(server.Close)
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	ctx := context.TODO()
+	templateValues := prepCollectionArtifacts(t, u.Host)
+	initialConfig, err := ioutil.ReadFile("./testdata/configs/dataset-config-links.yaml")
+	require.NoError(t, err)
+	tpl, err := template.New("copy").Parse(string(initialConfig))
+	require.NoError(t, err)
+	configPath := filepath.Join(t.TempDir(), "test.yaml")
+	configFile, err := os.Create(configPath)
+	require.NoError(t, err)
+	require.NoError(t, tpl.Execute(configFile, templateValues))
+	require.NoError(t, configFile.Close())
+
+	cache := filepath.Join(t.TempDir(), "cache")
+	require.NoError(t, os.MkdirAll(cache, 0750))
+	source := fmt.Sprintf("%s/staging/root:latest", u.Host)
+	buildOpts := &BuildCollectionOptions{
+		BuildOptions: &BuildOptions{
+			Common: &options.Common{
+				Logger:   testlogr,
+				CacheDir: cache,
+			},
+			Destination: source,
+		},
+		RootDir:  "./testdata/multi-level-workspace",
+		DSConfig: configPath,
+		Remote: options.Remote{
+			PlainHTTP: true,
+		},
+		NoVerify: true,
+	}
+	require.NoError(t, buildOpts.Run(ctx))
+	pushOpts := &PushOptions{
+		Common: &options.Common{
+			Logger:   testlogr,
+			CacheDir: cache,
+		},
+		Remote: options.Remote{
+			PlainHTTP: true,
+		},
+		Destination: source,
+	}
+	require.NoError(t, pushOpts.Run(ctx))
+
+	sourceDesc, _ := fetchManifest(t, source)
+	linkedDesc, _ := fetchManifest(t, templateValues["linkedCollection"])
+
+	type spec struct {
+		name        string
+		destination string
+		links       bool
+		mappings    []string
+		assertFunc  func(t *testing.T, desc ocispec.Descriptor, manifest ocispec.Manifest)
+	}
+
+	cases := []spec{
+		{
+			name:        "Success/DigestPreserved",
+			destination: fmt.Sprintf("%s/prod/root:latest", u.Host),
+			assertFunc: func(t *testing.T, desc ocispec.Descriptor, manifest ocispec.Manifest) {
+				require.Equal(t, sourceDesc.Digest, desc.Digest)
+			},
+		},
+		{
+			name:        "Success/UnchangedLinks",
+			destination: fmt.Sprintf("%s/prod/root:unchanged", u.Host),
+			links:       true,
+			assertFunc: func(t *testing.T, desc ocispec.Descriptor, manifest ocispec.Manifest) {
+				require.Equal(t, sourceDesc.Digest, desc.Digest)
+			},
+		},
+		{
+			name:        "Success/MappedLinks",
+			destination: fmt.Sprintf("%s/prod/root:mapped", u.Host),
+			links:       true,
+			mappings:    []string{"test=prod/test"},
+			assertFunc: func(t *testing.T, desc ocispec.Descriptor, manifest ocispec.Manifest) {
+				require.NotEqual(t, sourceDesc.Digest, desc.Digest)
+
+				var links []ocispec.Descriptor
+				require.NoError(t, json.Unmarshal([]byte(manifest.Annotations[empspec.AnnotationLink]), &links))
+				require.Len(t, links, 1)
+				require.Equal(t, linkedDesc.Digest, links[0].Digest)
+				node, err := v2.NewNode(links[0].Digest.String(), links[0])
+				require.NoError(t, err)
+				require.True(t, node.Properties.IsALink())
+				require.Equal(t, u.Host, node.Properties.Link.RegistryHint)
+				require.Equal(t, "prod/test", node.Properties.Link.NamespaceHint)
+
+				// The linked collection is copied into the mapped namespace.
+				copied, _ := fetchManifest(t, fmt.Sprintf("%s/prod/test@%s", u.Host, linkedDesc.Digest))
+				require.Equal(t, linkedDesc.Digest, copied.Digest)
+
+				// The copy can be pulled with links.
+				pullCache := filepath.Join(t.TempDir(), "cache")
+				require.NoError(t, os.MkdirAll(pullCache, 0750))
+				pullOpts := &PullOptions{
+					Common: &options.Common{
+						Logger:   testlogr,
+						CacheDir: pullCache,
+					},
+					Remote: options.Remote{
+						PlainHTTP: true,
+					},
+					Source:   fmt.Sprintf("%s/prod/root:mapped", u.Host),
+					Output:   t.TempDir(),
+					PullAll:  true,
+					NoVerify: true,
+				}
+				require.NoError(t, pullOpts.Run(ctx))
+				_, err = os.Stat(filepath.Join(pullOpts.Output, "hello.txt"))
+				require.NoError(t, err)
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			opts := &CopyOptions{
+				Common: &options.Common{
+					Logger: testlogr,
+				},
+				Remote: options.Remote{
+					PlainHTTP: true,
+				},
+				Links:             c.links,
+				NamespaceMappings: c.mappings,
+			}
+			require.NoError(t, opts.Complete([]string{source, c.destination}))
+			require.NoError(t, opts.Validate())
+			require.NoError(t, opts.Run(ctx))
+
+			desc, manifest := fetchManifest(t, c.destination)
+			c.assertFunc(t, desc, manifest)
+		})
+	}
+}
+
+func fetchManifest(t *testing.T, reference string) (ocispec.Descriptor, ocispec.Manifest) {
+	repo, err := remote.NewRepository(reference)
+	require.NoError(t, err)
+	repo.PlainHTTP = true
+	desc, rc, err := repo.FetchReference(context.TODO(), reference)
+	require.NoError(t, err)
+	defer rc.Close()
+	data, err := orascontent.ReadAll(rc, desc)
+	require.NoError(t, err)
+	var manifest ocispec.Manifest
+	require.NoError(t, json.Unmarshal(data, &manifest))
+	return desc, manifest
+}
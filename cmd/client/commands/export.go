@@ -0,0 +1,122 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/emporous/emporous-go/cmd/client/commands/options"
+	"github.com/emporous/emporous-go/content"
+	"github.com/emporous/emporous-go/content/layout"
+	"github.com/emporous/emporous-go/util/examples"
+)
+
+// ExportOptions describe configuration options that can
+// be set using the export subcommand.
+type ExportOptions struct {
+	*options.Common
+	References []string
+	Output     string
+	Links      bool
+	Schemas    bool
+}
+
+var clientExportExamples = []examples.Example{
+	{
+		RootCommand:   filepath.Base(os.Args[0]),
+		CommandString: "export localhost:5001/test:latest -o bundle.tar",
+		Descriptions: []string{
+			"Export a cached collection to an OCI layout archive.",
+		},
+	},
+	{
+		RootCommand:   filepath.Base(os.Args[0]),
+		CommandString: "export localhost:5001/test:latest localhost:5001/other:latest -o bundle.tar --links --schemas",
+		Descriptions: []string{
+			"Export cached collections with their linked collections and schemas.",
+		},
+	},
+}
+
+// NewExportCmd creates a new cobra.Command for the export subcommand.
+func NewExportCmd(common *options.Common) *cobra.Command {
+	o := ExportOptions{Common: common}
+
+	cmd := &cobra.Command{
+		Use:           "export REF...",
+		Short:         "Export Emporous collections from the cache to an OCI layout archive",
+		Example:       examples.FormatExamples(clientExportExamples...),
+		SilenceErrors: false,
+		SilenceUsage:  false,
+		Args:          cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			cobra.CheckErr(o.Complete(args))
+			cobra.CheckErr(o.Validate())
+			cobra.CheckErr(o.Run(cmd.Context()))
+		},
+	}
+
+	cmd.Flags().StringVarP(&o.Output, "output", "o", o.Output, "path of the archive to write")
+	cmd.Flags().BoolVar(&o.Links, "links", o.Links, "include linked collections. Linked collections must be in the cache (e.g. pulled with --pull-all)")
+	cmd.Flags().BoolVar(&o.Schemas, "schemas", o.Schemas, "include the schemas the collections were built against. Schemas must be in the cache")
+
+	return cmd
+}
+
+func (o *ExportOptions) Complete(args []string) error {
+	if len(args) < 1 {
+		return errors.New("bug: expecting at least one argument")
+	}
+	o.References = args
+	return nil
+}
+
+func (o *ExportOptions) Validate() error {
+	if o.Output == "" {
+		return errors.New("--output must be set")
+	}
+	return nil
+}
+
+func (o *ExportOptions) Run(ctx context.Context) (err error) {
+	cache, err := layout.NewWithContext(ctx, o.CacheDir)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(o.Output)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		// Do not leave partial archives behind.
+		if err != nil {
+			if removeErr := os.Remove(o.Output); removeErr != nil {
+				o.Logger.Errorf(removeErr.Error())
+			}
+		}
+	}()
+
+	exportOpts := layout.ExportOptions{
+		Links:   o.Links,
+		Schemas: o.Schemas,
+	}
+	manifests, err := cache.Export(ctx, f, o.References, exportOpts)
+	var missingErr *content.ErrMissingContent
+	if errors.As(err, &missingErr) {
+		return fmt.Errorf("%w: run \"rehydrate\" to fetch the missing content", err)
+	}
+	if err != nil {
+		return err
+	}
+
+	o.Logger.Infof("Exported %d manifest(s) to %s", len(manifests), o.Output)
+	return nil
+}
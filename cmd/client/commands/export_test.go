@@ -0,0 +1,181 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"text/template"
+
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/stretchr/testify/require"
+
+	"github.com/emporous/emporous-go/cmd/client/commands/options"
+	"github.com/emporous/emporous-go/content/layout"
+	"github.com/emporous/emporous-go/log"
+)
+
+func TestExportImportRun(t *testing.T) {
+	testlogr, err := log.NewLogrusLogger(ioutil.Discard, "debug")
+	require.NoError(t, err)
+
+	server := httptest.NewServer(registry.New())
+	t.Cleanup(server.Close)
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	ctx := context.TODO()
+	templateValues := prepCollectionArtifacts(t, u.Host)
+	initialConfig, err := ioutil.ReadFile("./testdata/configs/dataset-config-dryrun.yaml")
+	require.NoError(t, err)
+	tpl, err := template.New("export").Parse(string(initialConfig))
+	require.NoError(t, err)
+	configPath := filepath.Join(t.TempDir(), "test.yaml")
+	configFile, err := os.Create(configPath)
+	require.NoError(t, err)
+	require.NoError(t, tpl.Execute(configFile, templateValues))
+	require.NoError(t, configFile.Close())
+
+	// The build cache stores the collection and its schema.
+	cache := filepath.Join(t.TempDir(), "cache")
+	require.NoError(t, os.MkdirAll(cache, 0750))
+	source := fmt.Sprintf("%s/client-export:latest", u.Host)
+	buildOpts := &BuildCollectionOptions{
+		BuildOptions: &BuildOptions{
+			Common: &options.Common{
+				Logger:   testlogr,
+				CacheDir: cache,
+			},
+			Destination: source,
+		},
+		RootDir:  "./testdata/multi-level-workspace",
+		DSConfig: configPath,
+		Remote: options.Remote{
+			PlainHTTP: true,
+		},
+		NoVerify: true,
+	}
+	require.NoError(t, buildOpts.Run(ctx))
+
+	export := func(t *testing.T, links, schemas bool) (string, error) {
+		opts := &ExportOptions{
+			Common: &options.Common{
+				Logger:   testlogr,
+				CacheDir: cache,
+			},
+			Output:  filepath.Join(t.TempDir(), "bundle.tar"),
+			Links:   links,
+			Schemas: schemas,
+		}
+		require.NoError(t, opts.Complete([]string{source}))
+		require.NoError(t, opts.Validate())
+		return opts.Output, opts.Run(ctx)
+	}
+
+	t.Run("Failure/LinksNotCached", func(t *testing.T) {
+		output, err := export(t, true, false)
+		require.ErrorContains(t, err, fmt.Sprintf("descriptor for reference %s/test@", u.Host))
+		_, err = os.Stat(output)
+		require.True(t, os.IsNotExist(err))
+	})
+
+	t.Run("Success/Schemas", func(t *testing.T) {
+		output, err := export(t, false, true)
+		require.NoError(t, err)
+		imported := importArchive(t, output)
+		_, err = imported.Resolve(ctx, templateValues["schemaAddress"])
+		require.NoError(t, err)
+	})
+
+	t.Run("Success/LinksAndPush", func(t *testing.T) {
+		// Pulling with links stores the linked collection in the cache.
+		pushOpts := &PushOptions{
+			Common: &options.Common{
+				Logger:   testlogr,
+				CacheDir: cache,
+			},
+			Remote: options.Remote{
+				PlainHTTP: true,
+			},
+			Destination: source,
+		}
+		require.NoError(t, pushOpts.Run(ctx))
+		pullOpts := &PullOptions{
+			Common: &options.Common{
+				Logger:   testlogr,
+				CacheDir: cache,
+			},
+			Remote: options.Remote{
+				PlainHTTP: true,
+			},
+			Source:   source,
+			Output:   t.TempDir(),
+			PullAll:  true,
+			NoVerify: true,
+		}
+		require.NoError(t, pullOpts.Run(ctx))
+
+		output, err := export(t, true, true)
+		require.NoError(t, err)
+		importCache := filepath.Join(t.TempDir(), "cache")
+		require.NoError(t, os.MkdirAll(importCache, 0750))
+		importOpts := &ImportOptions{
+			Common: &options.Common{
+				Logger:   testlogr,
+				CacheDir: importCache,
+			},
+		}
+		require.NoError(t, importOpts.Complete([]string{output}))
+		require.NoError(t, importOpts.Validate())
+		require.NoError(t, importOpts.Run(ctx))
+
+		imported, err := layout.NewWithContext(ctx, importCache)
+		require.NoError(t, err)
+		linked, _ := fetchManifest(t, templateValues["linkedCollection"])
+		exists, err := imported.Exists(ctx, linked)
+		require.NoError(t, err)
+		require.True(t, exists)
+
+		// The imported collection can be pushed from the cache.
+		destination := fmt.Sprintf("%s/client-import:latest", u.Host)
+		tagOpts := &TagOptions{
+			Common: &options.Common{
+				Logger:   testlogr,
+				CacheDir: importCache,
+			},
+			Source:      source,
+			Destination: destination,
+		}
+		require.NoError(t, tagOpts.Run(ctx))
+		pushOpts = &PushOptions{
+			Common: &options.Common{
+				Logger:   testlogr,
+				CacheDir: importCache,
+			},
+			Remote: options.Remote{
+				PlainHTTP: true,
+			},
+			Destination: destination,
+		}
+		require.NoError(t, pushOpts.Run(ctx))
+		sourceDesc, _ := fetchManifest(t, source)
+		destinationDesc, _ := fetchManifest(t, destination)
+		require.Equal(t, sourceDesc.Digest, destinationDesc.Digest)
+	})
+}
+
+func importArchive(t *testing.T, archive string) *layout.Layout {
+	dir := t.TempDir()
+	l, err := layout.NewWithContext(context.TODO(), dir)
+	require.NoError(t, err)
+	f, err := os.Open(archive)
+	require.NoError(t, err)
+	defer f.Close()
+	_, err = l.Import(context.TODO(), f)
+	require.NoError(t, err)
+	return l
+}
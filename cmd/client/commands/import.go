@@ -0,0 +1,83 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/emporous/emporous-go/cmd/client/commands/options"
+	"github.com/emporous/emporous-go/content/layout"
+	"github.com/emporous/emporous-go/util/examples"
+)
+
+// ImportOptions describe configuration options that can
+// be set using the import subcommand.
+type ImportOptions struct {
+	*options.Common
+	Archive string
+}
+
+var clientImportExamples = examples.Example{
+	RootCommand:   filepath.Base(os.Args[0]),
+	Descriptions:  []string{"Import the collections in an OCI layout archive into the cache."},
+	CommandString: "import bundle.tar",
+}
+
+// NewImportCmd creates a new cobra.Command for the import subcommand.
+func NewImportCmd(common *options.Common) *cobra.Command {
+	o := ImportOptions{Common: common}
+
+	cmd := &cobra.Command{
+		Use:           "import ARCHIVE",
+		Short:         "Import Emporous collections from an OCI layout archive into the cache",
+		Example:       examples.FormatExamples(clientImportExamples),
+		SilenceErrors: false,
+		SilenceUsage:  false,
+		Args:          cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			cobra.CheckErr(o.Complete(args))
+			cobra.CheckErr(o.Validate())
+			cobra.CheckErr(o.Run(cmd.Context()))
+		},
+	}
+
+	return cmd
+}
+
+func (o *ImportOptions) Complete(args []string) error {
+	if len(args) < 1 {
+		return errors.New("bug: expecting one argument")
+	}
+	o.Archive = args[0]
+	return nil
+}
+
+func (o *ImportOptions) Validate() error {
+	_, err := os.Stat(o.Archive)
+	return err
+}
+
+func (o *ImportOptions) Run(ctx context.Context) error {
+	cache, err := layout.NewWithContext(ctx, o.CacheDir)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(o.Archive)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	references, err := cache.Import(ctx, f)
+	if err != nil {
+		return err
+	}
+	for _, reference := range references {
+		o.Logger.Infof("Imported %s", reference)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"oras.land/oras-go/v2/registry"
+
+	clientapi "github.com/emporous/emporous-go/api/client/v1alpha1"
+	"github.com/emporous/emporous-go/registryclient"
+)
+
+// resolveLock resolves the schema address and linked collections
+// of the dataset configuration to their current manifest digests.
+func resolveLock(ctx context.Context, client registryclient.Remote, config clientapi.DataSetConfiguration) (clientapi.LockFile, error) {
+	var lock clientapi.LockFile
+	if config.Collection.SchemaAddress != "" {
+		locked, err := resolveLockedReference(ctx, client, config.Collection.SchemaAddress)
+		if err != nil {
+			return lock, fmt.Errorf("schema %q: %w", config.Collection.SchemaAddress, err)
+		}
+		lock.Schema = &locked
+	}
+	for _, link := range config.Collection.LinkedCollections {
+		locked, err := resolveLockedReference(ctx, client, link)
+		if err != nil {
+			return lock, fmt.Errorf("link %q: %w", link, err)
+		}
+		lock.Links = append(lock.Links, locked)
+	}
+	return lock, nil
+}
+
+func resolveLockedReference(ctx context.Context, client registryclient.Remote, reference string) (clientapi.LockedReference, error) {
+	desc, rc, err := client.GetManifest(ctx, reference)
+	if err != nil {
+		return clientapi.LockedReference{}, err
+	}
+	if err := rc.Close(); err != nil {
+		return clientapi.LockedReference{}, err
+	}
+	return clientapi.LockedReference{
+		Reference: reference,
+		Digest:    desc.Digest.String(),
+	}, nil
+}
+
+// pinLinks replaces the linked collections in the dataset configuration
+// with references to the digests recorded in the lock file.
+func pinLinks(config *clientapi.DataSetConfiguration, lock clientapi.LockFile) error {
+	pinned := make([]string, 0, len(lock.Links))
+	for _, link := range lock.Links {
+		ref, err := registry.ParseReference(link.Reference)
+		if err != nil {
+			return fmt.Errorf("link %q: %w", link.Reference, err)
+		}
+		ref.Reference = link.Digest
+		pinned = append(pinned, ref.String())
+	}
+	config.Collection.LinkedCollections = pinned
+	return nil
+}
@@ -0,0 +1,39 @@
+package options
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+)
+
+const (
+	// ProgressAuto renders progress bars when the error output
+	// is a terminal and periodic log lines otherwise.
+	ProgressAuto = "auto"
+	// ProgressBars renders progress bars.
+	ProgressBars = "bars"
+	// ProgressLog writes progress as periodic log lines.
+	ProgressLog = "log"
+	// ProgressNone disables progress output.
+	ProgressNone = "none"
+)
+
+// Progress describes transfer progress options that can be set.
+type Progress struct {
+	Mode string
+}
+
+// BindFlags binds options from a flag set to Progress options.
+func (o *Progress) BindFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.Mode, "progress", ProgressAuto, "Transfer progress output (auto, bars, log, none). "+
+		"auto renders progress bars when the error output is a terminal and log lines otherwise")
+}
+
+// Validate validates the Progress options.
+func (o *Progress) Validate() error {
+	switch o.Mode {
+	case "", ProgressAuto, ProgressBars, ProgressLog, ProgressNone:
+		return nil
+	}
+	return fmt.Errorf("unsupported progress output %q: must be one of %s, %s, %s, %s", o.Mode, ProgressAuto, ProgressBars, ProgressLog, ProgressNone)
+}
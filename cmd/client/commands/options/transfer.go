@@ -0,0 +1,145 @@
+package options
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+// Transfer describes transfer concurrency and bandwidth options that can be set.
+type Transfer struct {
+	// Concurrency is the number of blobs copied concurrently by each
+	// operation. If zero, the client default is used.
+	Concurrency int64
+	// LimitRate is the global limit on bytes read from and written to registries
+	// per second (e.g. 512K, 10M).
+	LimitRate string
+	// RegistryConcurrency overrides Concurrency by registry in the form HOST=N.
+	RegistryConcurrency []string
+	// RegistryLimitRate overrides LimitRate by registry in the form HOST=RATE.
+	RegistryLimitRate []string
+}
+
+// BindFlags binds options from a flag set to Transfer options.
+func (o *Transfer) BindFlags(fs *pflag.FlagSet) {
+	fs.Int64Var(&o.Concurrency, "concurrency", o.Concurrency, "Number of blobs copied concurrently. Defaults to 3")
+	fs.StringVar(&o.LimitRate, "limit-rate", o.LimitRate, "Limit bytes per second read from and written to registries "+
+		"(e.g. 512K, 10M, 1G). Suffixes are powers of 1024")
+	fs.StringArrayVar(&o.RegistryConcurrency, "registry-concurrency", o.RegistryConcurrency, "Override the concurrency for a "+
+		"registry in the form HOST=N")
+	fs.StringArrayVar(&o.RegistryLimitRate, "registry-limit-rate", o.RegistryLimitRate, "Override the rate limit for a "+
+		"registry in the form HOST=RATE. Traffic to the registry does not count toward --limit-rate")
+}
+
+// Validate validates the Transfer options.
+func (o *Transfer) Validate() error {
+	if o.Concurrency < 0 {
+		return fmt.Errorf("concurrency must be at least 1, got %d", o.Concurrency)
+	}
+	if _, err := o.RateLimit(); err != nil {
+		return err
+	}
+	if _, err := o.RegistryConcurrencies(); err != nil {
+		return err
+	}
+	_, err := o.RegistryRateLimits()
+	return err
+}
+
+// RateLimit returns the global rate limit in bytes per second.
+// If no limit is set, zero is returned.
+func (o *Transfer) RateLimit() (int64, error) {
+	if o.LimitRate == "" {
+		return 0, nil
+	}
+	rate, err := ParseRate(o.LimitRate)
+	if err != nil {
+		return 0, fmt.Errorf("limit rate: %w", err)
+	}
+	return rate, nil
+}
+
+// RegistryConcurrencies returns the concurrency overrides by registry.
+func (o *Transfer) RegistryConcurrencies() (map[string]int64, error) {
+	return parseRegistryValues(o.RegistryConcurrency, "concurrency", func(value string) (int64, error) {
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || n < 1 {
+			return 0, fmt.Errorf("%q is not a positive integer", value)
+		}
+		return n, nil
+	})
+}
+
+// RegistryRateLimits returns the rate limit overrides in bytes per second by registry.
+func (o *Transfer) RegistryRateLimits() (map[string]int64, error) {
+	return parseRegistryValues(o.RegistryLimitRate, "rate limit", ParseRate)
+}
+
+// ParseRate parses a rate in bytes per second with an optional
+// K, M or G suffix (e.g. 512K). Suffixes are powers of 1024.
+func ParseRate(rate string) (int64, error) {
+	bytes, ok := parseBytes(rate)
+	if !ok {
+		return 0, fmt.Errorf("invalid rate %q: must be a positive number of bytes with an optional K, M or G suffix", rate)
+	}
+	if bytes < 1 {
+		return 0, fmt.Errorf("invalid rate %q: must be at least 1 byte per second", rate)
+	}
+	return bytes, nil
+}
+
+// ParseSize parses a size in bytes with an optional K, M or G
+// suffix (e.g. 512K, 10M, 1G). Suffixes are powers of 1024.
+func ParseSize(size string) (int64, error) {
+	bytes, ok := parseBytes(size)
+	if !ok || bytes < 1 {
+		return 0, fmt.Errorf("invalid size %q: must be a positive number of bytes with an optional K, M or G suffix", size)
+	}
+	return bytes, nil
+}
+
+// parseBytes parses a positive number of bytes with an optional
+// K, M or G suffix and an optional B suffix.
+func parseBytes(value string) (int64, bool) {
+	value = strings.ToUpper(strings.TrimSpace(value))
+	value = strings.TrimSuffix(value, "B")
+	var multiplier int64 = 1
+	switch {
+	case strings.HasSuffix(value, "K"):
+		multiplier = 1 << 10
+	case strings.HasSuffix(value, "M"):
+		multiplier = 1 << 20
+	case strings.HasSuffix(value, "G"):
+		multiplier = 1 << 30
+	}
+	if multiplier != 1 {
+		value = value[:len(value)-1]
+	}
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil || n <= 0 {
+		return 0, false
+	}
+	return int64(n * float64(multiplier)), true
+}
+
+// parseRegistryValues parses values in the form HOST=VALUE by registry host.
+func parseRegistryValues(values []string, name string, parse func(string) (int64, error)) (map[string]int64, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	result := make(map[string]int64, len(values))
+	for _, value := range values {
+		parts := strings.SplitN(value, "=", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("registry %s %q: must be in the form HOST=VALUE", name, value)
+		}
+		n, err := parse(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("registry %s for %s: %w", name, parts[0], err)
+		}
+		result[parts[0]] = n
+	}
+	return result, nil
+}
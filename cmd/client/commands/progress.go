@@ -0,0 +1,228 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"golang.org/x/term"
+
+	"github.com/emporous/emporous-go/cmd/client/commands/options"
+	"github.com/emporous/emporous-go/log"
+	"github.com/emporous/emporous-go/registryclient/orasclient"
+	"github.com/emporous/emporous-go/registryclient/progress"
+)
+
+const (
+	// progressBarInterval is the minimum time between redraws of the progress bars.
+	progressBarInterval = 100 * time.Millisecond
+	// progressLogInterval is the minimum time between progress log lines.
+	progressLogInterval = 5 * time.Second
+	// progressBarWidth is the number of characters in a progress bar.
+	progressBarWidth = 30
+)
+
+// progressOptions returns the client options that report transfer progress in the
+// configured mode and a function that completes the output after the transfer.
+func progressOptions(common *options.Common, opts options.Progress) ([]orasclient.ClientOption, func()) {
+	mode := opts.Mode
+	if mode == "" || mode == options.ProgressAuto {
+		mode = options.ProgressLog
+		if isTerminal(common.IOStreams.ErrOut) {
+			mode = options.ProgressBars
+		}
+	}
+
+	switch mode {
+	case options.ProgressBars:
+		bars := newProgressBars(common.IOStreams.ErrOut)
+		return []orasclient.ClientOption{orasclient.WithProgress(bars.update)}, bars.finish
+	case options.ProgressLog:
+		logs := newProgressLog(common.Logger)
+		return []orasclient.ClientOption{orasclient.WithProgress(logs.update)}, logs.finish
+	}
+	return nil, func() {}
+}
+
+// isTerminal returns whether the writer is a terminal.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	return ok && term.IsTerminal(int(f.Fd()))
+}
+
+// progressBars renders a progress bar for each descriptor being copied
+// and a bar for the whole transfer. Completed descriptors are printed
+// above the bars.
+type progressBars struct {
+	w  io.Writer
+	mu sync.Mutex
+	// active stores the copied bytes of the
+	// descriptors being copied in start order.
+	active   []activeDescriptor
+	status   progress.Status
+	lines    int
+	lastDraw time.Time
+	drawn    bool
+}
+
+type activeDescriptor struct {
+	desc   ocispec.Descriptor
+	copied int64
+}
+
+func newProgressBars(w io.Writer) *progressBars {
+	return &progressBars{w: w}
+}
+
+func (p *progressBars) update(event progress.Event) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.status = event.Status
+
+	var completed string
+	switch event.Type {
+	case progress.EventStart:
+		p.active = append(p.active, activeDescriptor{desc: event.Descriptor})
+	case progress.EventProgress:
+		for i := range p.active {
+			if p.active[i].desc.Digest == event.Descriptor.Digest {
+				p.active[i].copied = event.Bytes
+			}
+		}
+		if time.Since(p.lastDraw) < progressBarInterval {
+			return
+		}
+	case progress.EventDone:
+		for i := range p.active {
+			if p.active[i].desc.Digest == event.Descriptor.Digest {
+				p.active = append(p.active[:i], p.active[i+1:]...)
+				break
+			}
+		}
+		completed = fmt.Sprintf("Copied %s (%s)", descriptorName(event.Descriptor), formatBytes(event.Descriptor.Size))
+	case progress.EventSkipped:
+		return
+	}
+	p.draw(completed)
+}
+
+// draw redraws the progress bars in place. If set, the completed
+// line is printed above the bars.
+func (p *progressBars) draw(completed string) {
+	var b strings.Builder
+	if p.lines > 0 {
+		fmt.Fprintf(&b, "\x1b[%dA", p.lines)
+	}
+	if completed != "" {
+		fmt.Fprintf(&b, "\r\x1b[K%s\n", completed)
+	}
+	for _, a := range p.active {
+		fmt.Fprintf(&b, "\r\x1b[K%s %s\n", progressBar(a.copied, a.desc.Size), descriptorName(a.desc))
+	}
+	fmt.Fprintf(&b, "\r\x1b[K%s %s/%s (%d/%d blobs)\n", progressBar(p.status.BytesDone, p.status.BytesTotal),
+		formatBytes(p.status.BytesDone), formatBytes(p.status.BytesTotal), p.status.BlobsDone, p.status.BlobsTotal)
+	// Clear lines left over from descriptors that are no longer active.
+	b.WriteString("\x1b[J")
+
+	p.lines = len(p.active) + 1
+	p.lastDraw = time.Now()
+	p.drawn = true
+	_, _ = io.WriteString(p.w, b.String())
+}
+
+// finish draws the final state of the transfer.
+func (p *progressBars) finish() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.drawn {
+		p.draw("")
+	}
+}
+
+// progressLog writes the progress of the transfer as log lines.
+type progressLog struct {
+	logger  log.Logger
+	mu      sync.Mutex
+	status  progress.Status
+	lastLog time.Time
+	started bool
+}
+
+func newProgressLog(logger log.Logger) *progressLog {
+	return &progressLog{logger: logger}
+}
+
+func (p *progressLog) update(event progress.Event) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.status = event.Status
+	if event.Type == progress.EventSkipped {
+		return
+	}
+	// The first interval starts with the transfer.
+	if !p.started {
+		p.started = true
+		p.lastLog = time.Now()
+		return
+	}
+	if time.Since(p.lastLog) < progressLogInterval {
+		return
+	}
+	p.log()
+}
+
+func (p *progressLog) log() {
+	p.lastLog = time.Now()
+	p.logger.Infof("Transferred %s of %s (%d/%d blobs)", formatBytes(p.status.BytesDone), formatBytes(p.status.BytesTotal),
+		p.status.BlobsDone, p.status.BlobsTotal)
+}
+
+// finish logs the final state of the transfer.
+func (p *progressLog) finish() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.started {
+		p.log()
+	}
+}
+
+// progressBar returns a bar for the fraction of done to total with the percentage.
+func progressBar(done, total int64) string {
+	percent := 100
+	if total > 0 {
+		percent = int(done * 100 / total)
+	}
+	filled := percent * progressBarWidth / 100
+	return fmt.Sprintf("[%s%s] %3d%%", strings.Repeat("=", filled), strings.Repeat(" ", progressBarWidth-filled), percent)
+}
+
+// descriptorName returns the title of the descriptor if set or a
+// shortened digest with the media type.
+func descriptorName(desc ocispec.Descriptor) string {
+	if title, ok := desc.Annotations[ocispec.AnnotationTitle]; ok {
+		return title
+	}
+	encoded := desc.Digest.Encoded()
+	if len(encoded) > 12 {
+		encoded = encoded[:12]
+	}
+	return fmt.Sprintf("%s (%s)", encoded, desc.MediaType)
+}
+
+// formatBytes formats a number of bytes with binary units.
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,62 @@
+package commands
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+
+	"github.com/emporous/emporous-go/cmd/client/commands/options"
+	"github.com/emporous/emporous-go/log"
+	"github.com/emporous/emporous-go/registryclient/progress"
+)
+
+func TestProgressValidate(t *testing.T) {
+	require.NoError(t, (&options.Progress{Mode: options.ProgressBars}).Validate())
+	require.EqualError(t, (&options.Progress{Mode: "fancy"}).Validate(), `unsupported progress output "fancy": must be one of auto, bars, log, none`)
+}
+
+func TestProgressBars(t *testing.T) {
+	desc := ocispec.Descriptor{
+		MediaType:   "text/plain",
+		Digest:      digest.FromString("hello"),
+		Size:        2048,
+		Annotations: map[string]string{ocispec.AnnotationTitle: "hello.txt"},
+	}
+	out := new(bytes.Buffer)
+	bars := newProgressBars(out)
+	tracker := progress.NewTracker(bars.update)
+	tracker.Start(desc)
+	require.Contains(t, out.String(), "[                              ]   0% hello.txt")
+	tracker.Done(desc)
+	bars.finish()
+	require.Contains(t, out.String(), "Copied hello.txt (2.0 KiB)")
+	require.True(t, strings.HasSuffix(out.String(), "[==============================] 100% 2.0 KiB/2.0 KiB (1/1 blobs)\n\x1b[J"))
+}
+
+func TestProgressLog(t *testing.T) {
+	out := new(bytes.Buffer)
+	logger, err := log.NewLogrusLogger(out, "info")
+	require.NoError(t, err)
+	logs := newProgressLog(logger)
+
+	// Nothing is logged without a transfer.
+	logs.finish()
+	require.Empty(t, out.String())
+
+	tracker := progress.NewTracker(logs.update)
+	desc := ocispec.Descriptor{Digest: digest.FromString("hello"), Size: 5}
+	tracker.Start(desc)
+	tracker.Done(desc)
+	logs.finish()
+	require.Contains(t, out.String(), "Transferred 5 B of 5 B (1/1 blobs)")
+}
+
+func TestFormatBytes(t *testing.T) {
+	require.Equal(t, "512 B", formatBytes(512))
+	require.Equal(t, "1.5 KiB", formatBytes(1536))
+	require.Equal(t, "3.0 GiB", formatBytes(3*1024*1024*1024))
+}
@@ -4,17 +4,25 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 
 	"github.com/spf13/cobra"
-	"oras.land/oras-go/v2/content/file"
 
 	"github.com/emporous/emporous-go/cmd/client/commands/options"
 	"github.com/emporous/emporous-go/config"
+	"github.com/emporous/emporous-go/content"
+	"github.com/emporous/emporous-go/content/file"
 	"github.com/emporous/emporous-go/content/layout"
+	"github.com/emporous/emporous-go/manager"
 	"github.com/emporous/emporous-go/manager/defaultmanager"
+	"github.com/emporous/emporous-go/model"
+	"github.com/emporous/emporous-go/model/traversal"
 	"github.com/emporous/emporous-go/nodes/descriptor"
+	"github.com/emporous/emporous-go/registryclient"
 	"github.com/emporous/emporous-go/registryclient/orasclient"
 	"github.com/emporous/emporous-go/util/examples"
 )
@@ -25,11 +33,43 @@ type PullOptions struct {
 	*options.Common
 	options.Remote
 	options.RemoteAuth
+	options.Progress
+	options.Transfer
 	Source         string
 	Output         string
 	PullAll        bool
 	AttributeQuery string
 	NoVerify       bool
+	// Platform selects the variant to pull
+	// from a multi-platform collection.
+	Platform string
+	// VariantQuery is the path to an attribute query
+	// used to select the variant to pull.
+	VariantQuery string
+	// Owner is the user and group ID in the form UID:GID
+	// applied to pulled files that have recorded ownership.
+	Owner string
+	// ReferrerTypes are the artifact types of the referrer
+	// artifacts pulled with the collection.
+	ReferrerTypes []string
+	// OutputTemplate is the template used to render the path
+	// of each pulled file relative to the output location.
+	OutputTemplate string
+	outputTemplate *file.Template
+	// LinkMode is how pulled files are created from
+	// blobs in the cache: copy, hardlink, reflink or auto.
+	LinkMode string
+	linkMode file.LinkMode
+	// MaxDepth is the maximum number of links followed from the
+	// source collection with PullAll. If zero, depth is not limited.
+	MaxDepth int
+	// MaxNodes is the maximum number of collection nodes visited with
+	// PullAll. If zero, the number of nodes is not limited.
+	MaxNodes int64
+	// MaxBytes is the maximum size of the collections pulled with
+	// PullAll (e.g. 512M, 10G). If empty, the size is not limited.
+	MaxBytes string
+	maxBytes int64
 }
 
 var clientPullExamples = []examples.Example{
@@ -54,6 +94,48 @@ var clientPullExamples = []examples.Example{
 			"Pull all content from reference that satisfies the attribute query.",
 		},
 	},
+	{
+		RootCommand:   filepath.Base(os.Args[0]),
+		CommandString: "pull localhost:5001/test:latest --platform linux/arm64",
+		Descriptions: []string{
+			"Pull the linux/arm64 variant of a multi-platform collection.",
+		},
+	},
+	{
+		RootCommand:   filepath.Base(os.Args[0]),
+		CommandString: "pull localhost:5001/test:latest --owner 1000:1000",
+		Descriptions: []string{
+			"Pull collection reference and map recorded file ownership to user and group 1000.",
+		},
+	},
+	{
+		RootCommand:   filepath.Base(os.Args[0]),
+		CommandString: "pull localhost:5001/test:latest --referrers-type application/spdx+json",
+		Descriptions: []string{
+			"Pull collection reference and the attached artifacts of type application/spdx+json into the referrers directory.",
+		},
+	},
+	{
+		RootCommand:   filepath.Base(os.Args[0]),
+		CommandString: "pull localhost:5001/test:latest --output-template '{{.animal}}/{{base .title}}'",
+		Descriptions: []string{
+			"Pull collection reference and arrange the files in directories by the value of the animal attribute.",
+		},
+	},
+	{
+		RootCommand:   filepath.Base(os.Args[0]),
+		CommandString: "pull localhost:5001/test:latest --pull-all --max-depth 2 --max-bytes 10G",
+		Descriptions: []string{
+			"Pull collection reference and the linked references at most two links away, up to 10GiB of content.",
+		},
+	},
+	{
+		RootCommand:   filepath.Base(os.Args[0]),
+		CommandString: "pull localhost:5001/test:latest --link-mode hardlink",
+		Descriptions: []string{
+			"Pull collection reference and create files that are already in the cache as read-only hard links to the cached content.",
+		},
+	},
 }
 
 // NewPullCmd creates a new cobra.Command for the pull subcommand.
@@ -76,11 +158,30 @@ func NewPullCmd(common *options.Common) *cobra.Command {
 
 	o.Remote.BindFlags(cmd.Flags())
 	o.RemoteAuth.BindFlags(cmd.Flags())
+	o.Progress.BindFlags(cmd.Flags())
+	o.Transfer.BindFlags(cmd.Flags())
 
 	cmd.Flags().StringVarP(&o.Output, "output", "o", o.Output, "Output location for artifacts")
 	cmd.Flags().StringVar(&o.AttributeQuery, "attributes", o.AttributeQuery, "Attribute query config path")
 	cmd.Flags().BoolVar(&o.PullAll, "pull-all", o.PullAll, "Pull all linked collections")
+	cmd.Flags().IntVar(&o.MaxDepth, "max-depth", o.MaxDepth, "Maximum number of links followed from the source collection with --pull-all. "+
+		"Zero does not limit depth")
+	cmd.Flags().Int64Var(&o.MaxNodes, "max-nodes", o.MaxNodes, "Maximum number of collection nodes visited with --pull-all. "+
+		"Zero does not limit the number of nodes")
+	cmd.Flags().StringVar(&o.MaxBytes, "max-bytes", o.MaxBytes, "Maximum size of the collections pulled with --pull-all "+
+		"(e.g. 512M, 10G). Suffixes are powers of 1024")
 	cmd.Flags().BoolVar(&o.NoVerify, "no-verify", o.NoVerify, "Skip collection signature verification")
+	cmd.Flags().StringVar(&o.Platform, "platform", o.Platform, "Platform of the variant to pull in the form os/arch[/variant]. Defaults to the current platform")
+	cmd.Flags().StringVar(&o.VariantQuery, "variant-attributes", o.VariantQuery, "Attribute query config path used to select the variant to pull")
+	cmd.Flags().StringVar(&o.Owner, "owner", o.Owner, "User and group ID in the form UID:GID applied to files with recorded ownership. "+
+		"By default, recorded ownership is applied only when running as root")
+	cmd.Flags().StringArrayVar(&o.ReferrerTypes, "referrers-type", o.ReferrerTypes, "Artifact type of the attached artifacts to pull. "+
+		"Each artifact is written to referrers/<digest> in the output location")
+	cmd.Flags().StringVar(&o.OutputTemplate, "output-template", o.OutputTemplate, "Go template for the path of each file in the output location. "+
+		"File attributes are available by key along with .title, .digest, .mediaType and .collection, and the base, dir and ext functions operate on paths")
+	cmd.Flags().StringVar(&o.LinkMode, "link-mode", string(file.LinkCopy), "How files already in the cache are created in the output location: "+
+		"copy, hardlink (read-only files sharing data with the cache), reflink (copy-on-write clones) or auto (reflink, then hardlink). "+
+		"Files are copied when they cannot be linked")
 
 	return cmd
 }
@@ -97,6 +198,50 @@ func (o *PullOptions) Complete(args []string) error {
 }
 
 func (o *PullOptions) Validate() error {
+	if err := o.Progress.Validate(); err != nil {
+		return err
+	}
+	if err := o.Transfer.Validate(); err != nil {
+		return err
+	}
+	if o.Owner != "" {
+		if _, _, err := parseOwner(o.Owner); err != nil {
+			return err
+		}
+	}
+	if o.Platform != "" {
+		if _, err := descriptor.ParsePlatform(o.Platform); err != nil {
+			return err
+		}
+	}
+	if o.OutputTemplate != "" {
+		tmpl, err := file.ParseTemplate(o.OutputTemplate)
+		if err != nil {
+			return fmt.Errorf("output template: %w", err)
+		}
+		o.outputTemplate = tmpl
+	}
+	if o.MaxDepth < 0 {
+		return fmt.Errorf("max depth must not be negative, got %d", o.MaxDepth)
+	}
+	if o.MaxNodes < 0 {
+		return fmt.Errorf("max nodes must not be negative, got %d", o.MaxNodes)
+	}
+	if o.MaxBytes != "" {
+		maxBytes, err := options.ParseSize(o.MaxBytes)
+		if err != nil {
+			return fmt.Errorf("max bytes: %w", err)
+		}
+		o.maxBytes = maxBytes
+	}
+	if !o.PullAll && (o.MaxDepth != 0 || o.MaxNodes != 0 || o.MaxBytes != "") {
+		return errors.New("--max-depth, --max-nodes and --max-bytes require --pull-all")
+	}
+	linkMode, err := file.ParseLinkMode(o.LinkMode)
+	if err != nil {
+		return err
+	}
+	o.linkMode = linkMode
 	if _, err := os.Stat(o.Output); err != nil {
 		if err := os.MkdirAll(o.Output, 0750); err != nil {
 			return err
@@ -116,6 +261,7 @@ func (o *PullOptions) Run(ctx context.Context) error {
 		orasclient.WithAuthConfigs(o.Configs),
 		orasclient.WithPlainHTTP(o.PlainHTTP),
 		orasclient.WithCache(cache),
+		orasclient.WithIngestDir(ingestDir(o.Common)),
 	}
 
 	if o.AttributeQuery != "" {
@@ -127,6 +273,25 @@ func (o *PullOptions) Run(ctx context.Context) error {
 		clientOpts = append(clientOpts, orasclient.WithPullableAttributes(matcher))
 	}
 
+	var variantMatchers []model.Matcher
+	if o.Platform != "" {
+		platform, err := descriptor.ParsePlatform(o.Platform)
+		if err != nil {
+			return err
+		}
+		variantMatchers = append(variantMatchers, descriptor.PlatformMatcher(platform))
+	}
+	if o.VariantQuery != "" {
+		query, err := config.ReadAttributeQuery(o.VariantQuery)
+		if err != nil {
+			return err
+		}
+		variantMatchers = append(variantMatchers, descriptor.JSONSubsetMatcher(query.Attributes))
+	}
+	if len(variantMatchers) != 0 {
+		clientOpts = append(clientOpts, orasclient.WithVariantMatchers(variantMatchers...))
+	}
+
 	if !o.NoVerify {
 		verificationFn := func(ctx context.Context, reference string) error {
 			o.Logger.Debugf("Checking signature of %s", reference)
@@ -139,6 +304,14 @@ func (o *PullOptions) Run(ctx context.Context) error {
 		clientOpts = append(clientOpts, orasclient.WithPrePullFunc(verificationFn))
 	}
 
+	transferOpts, err := transferOptions(o.Transfer)
+	if err != nil {
+		return err
+	}
+	clientOpts = append(clientOpts, transferOpts...)
+	progressOpts, finishProgress := progressOptions(o.Common, o.Progress)
+	clientOpts = append(clientOpts, progressOpts...)
+
 	client, err := orasclient.NewClient(clientOpts...)
 	if err != nil {
 		return fmt.Errorf("error configuring client: %v", err)
@@ -149,14 +322,24 @@ func (o *PullOptions) Run(ctx context.Context) error {
 		}
 	}()
 
-	manager := defaultmanager.New(cache, o.Logger)
+	var managerOpts []defaultmanager.Option
+	if o.Owner != "" {
+		uid, gid, err := parseOwner(o.Owner)
+		if err != nil {
+			return err
+		}
+		managerOpts = append(managerOpts, defaultmanager.WithOwner(uid, gid))
+	}
+	manager := defaultmanager.New(cache, o.Logger, managerOpts...)
 
 	var digests []string
 	if !o.PullAll {
-		digests, err = manager.Pull(ctx, o.Source, client, file.New(o.Output))
+		digests, err = manager.Pull(ctx, o.Source, client, o.outputStore(cache))
 	} else {
-		digests, err = manager.PullAll(ctx, o.Source, client, file.New(o.Output))
+		digests, err = manager.PullAll(ctx, o.Source, client, o.outputStore(cache), o.budget())
 	}
+	finishProgress()
+	failed, err := fileInfoFailures(err)
 	if err != nil {
 		return err
 	}
@@ -168,5 +351,98 @@ func (o *PullOptions) Run(ctx context.Context) error {
 
 	o.Logger.Infof("Copied collection(s) to %s", o.Output)
 
+	if err := o.pullReferrers(ctx, client); err != nil {
+		return err
+	}
+
+	if len(failed) != 0 {
+		fmt.Fprintln(o.IOStreams.Out, "File information could not be applied to:")
+		for _, file := range failed {
+			fmt.Fprintf(o.IOStreams.Out, "  %s\n", file)
+		}
+		return fmt.Errorf("file information could not be applied to %d file(s)", len(failed))
+	}
 	return nil
 }
+
+// fileInfoFailures returns the files that recorded file information could
+// not be applied to. Other errors from the pull are returned unchanged.
+func fileInfoFailures(err error) ([]string, error) {
+	var fileInfoErr *manager.ErrFileInfo
+	if errors.As(err, &fileInfoErr) {
+		return fileInfoErr.Files, nil
+	}
+	return nil, err
+}
+
+// pullReferrers pulls the referrer artifacts of the collection with the
+// requested artifact types. Each artifact is written to a directory named
+// after the artifact digest, so files from different artifacts do not collide.
+func (o *PullOptions) pullReferrers(ctx context.Context, client registryclient.Remote) error {
+	for _, artifactType := range o.ReferrerTypes {
+		referrers, err := client.Referrers(ctx, o.Source, artifactType)
+		if err != nil {
+			return err
+		}
+		if len(referrers) == 0 {
+			o.Logger.Infof("No referrers of type %s found for %s", artifactType, o.Source)
+			continue
+		}
+		for _, referrer := range referrers {
+			output := filepath.Join(o.Output, "referrers", referrer.Digest.Encoded())
+			if err := os.MkdirAll(output, 0750); err != nil {
+				return err
+			}
+			if _, err := client.PullReferrer(ctx, o.Source, referrer, file.New(output)); err != nil {
+				return fmt.Errorf("referrer %s: %w", referrer.Digest, err)
+			}
+			o.Logger.Infof("Copied referrer %s to %s", referrer.Digest, output)
+		}
+	}
+	return nil
+}
+
+// parseOwner parses a user and group ID in the form UID:GID.
+func parseOwner(owner string) (int, int, error) {
+	parts := strings.Split(owner, ":")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("owner %q: must be in the form UID:GID", owner)
+	}
+	uid, err := strconv.Atoi(parts[0])
+	if err != nil || uid < 0 {
+		return 0, 0, fmt.Errorf("owner %q: invalid user ID %q", owner, parts[0])
+	}
+	gid, err := strconv.Atoi(parts[1])
+	if err != nil || gid < 0 {
+		return 0, 0, fmt.Errorf("owner %q: invalid group ID %q", owner, parts[1])
+	}
+	return uid, gid, nil
+}
+
+// budget returns the traversal budget for pulling linked collections,
+// or nil if no limits are set.
+func (o *PullOptions) budget() *traversal.Budget {
+	if o.MaxDepth == 0 && o.MaxNodes == 0 && o.maxBytes == 0 {
+		return nil
+	}
+	// Unset byte and depth limits do not limit the traversal.
+	budget := &traversal.Budget{
+		NodeBudget: math.MaxInt64,
+		ByteBudget: o.maxBytes,
+		MaxDepth:   o.MaxDepth,
+	}
+	if o.MaxNodes != 0 {
+		budget.NodeBudget = o.MaxNodes
+	}
+	return budget
+}
+
+// outputStore returns the store that writes pulled files
+// to the output location, linking them to blobs in the cache.
+func (o *PullOptions) outputStore(cache content.BlobLocator) *file.Store {
+	links := file.WithLinks(cache, o.linkMode)
+	if o.outputTemplate != nil {
+		return file.NewWithTemplate(o.Output, o.outputTemplate, links)
+	}
+	return file.New(o.Output, links)
+}
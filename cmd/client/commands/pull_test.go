@@ -10,6 +10,9 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strings"
+	"syscall"
 	"testing"
 
 	empspec "github.com/emporous/collection-spec/specs-go/v1alpha1"
@@ -24,6 +27,7 @@ import (
 
 	"github.com/emporous/emporous-go/cmd/client/commands/options"
 	"github.com/emporous/emporous-go/log"
+	"github.com/emporous/emporous-go/model/traversal"
 	"github.com/emporous/emporous-go/nodes/descriptor"
 )
 
@@ -90,6 +94,103 @@ func TestPullValidate(t *testing.T) {
 				Output: filepath.Join(tmp, "fake"),
 			},
 		},
+		{
+			name: "Valid/Owner",
+			opts: &PullOptions{
+				Output: "testdata",
+				Owner:  "1000:1000",
+			},
+		},
+		{
+			name: "Invalid/OwnerFormat",
+			opts: &PullOptions{
+				Output: "testdata",
+				Owner:  "1000",
+			},
+			expError: "owner \"1000\": must be in the form UID:GID",
+		},
+		{
+			name: "Invalid/OwnerGroupID",
+			opts: &PullOptions{
+				Output: "testdata",
+				Owner:  "1000:staff",
+			},
+			expError: "owner \"1000:staff\": invalid group ID \"staff\"",
+		},
+		{
+			name: "Valid/OutputTemplate",
+			opts: &PullOptions{
+				Output:         "testdata",
+				OutputTemplate: "{{.animal}}/{{base .title}}",
+			},
+		},
+		{
+			name: "Invalid/OutputTemplateSyntax",
+			opts: &PullOptions{
+				Output:         "testdata",
+				OutputTemplate: "{{.animal",
+			},
+			expError: "output template: template: output:1: unclosed action",
+		},
+		{
+			name: "Invalid/OutputTemplateAbsolute",
+			opts: &PullOptions{
+				Output:         "testdata",
+				OutputTemplate: "/{{.title}}",
+			},
+			expError: "output template: template \"/{{.title}}\": path escapes the output directory",
+		},
+		{
+			name: "Valid/LinkMode",
+			opts: &PullOptions{
+				Output:   "testdata",
+				LinkMode: "hardlink",
+			},
+		},
+		{
+			name: "Invalid/LinkMode",
+			opts: &PullOptions{
+				Output:   "testdata",
+				LinkMode: "symlink",
+			},
+			expError: `invalid link mode "symlink": must be one of copy, hardlink, reflink or auto`,
+		},
+		{
+			name: "Valid/Limits",
+			opts: &PullOptions{
+				Output:   "testdata",
+				PullAll:  true,
+				MaxDepth: 2,
+				MaxNodes: 100,
+				MaxBytes: "10G",
+			},
+		},
+		{
+			name: "Invalid/LimitsWithoutPullAll",
+			opts: &PullOptions{
+				Output:   "testdata",
+				MaxDepth: 2,
+			},
+			expError: "--max-depth, --max-nodes and --max-bytes require --pull-all",
+		},
+		{
+			name: "Invalid/MaxBytes",
+			opts: &PullOptions{
+				Output:   "testdata",
+				PullAll:  true,
+				MaxBytes: "lots",
+			},
+			expError: `max bytes: invalid size "lots": must be a positive number of bytes with an optional K, M or G suffix`,
+		},
+		{
+			name: "Invalid/MaxDepth",
+			opts: &PullOptions{
+				Output:   "testdata",
+				PullAll:  true,
+				MaxDepth: -1,
+			},
+			expError: "max depth must not be negative, got -1",
+		},
 	}
 
 	for _, c := range cases {
@@ -119,7 +220,13 @@ func TestPullRun(t *testing.T) {
 		name       string
 		opts       *PullOptions
 		assertFunc func(string) bool
-		expError   string
+		// validate parses options that are
+		// only set by PullOptions.Validate.
+		validate bool
+		expError string
+		// assertErr asserts errors that
+		// cannot be matched exactly.
+		assertErr func(*testing.T, error)
 	}
 
 	cases := []spec{
@@ -180,6 +287,146 @@ func TestPullRun(t *testing.T) {
 				return err == nil
 			},
 		},
+		{
+			name: "Success/PullAllWithOutputTemplate",
+			opts: &PullOptions{
+				Common: &options.Common{
+					IOStreams: genericclioptions.IOStreams{
+						Out:    os.Stdout,
+						In:     os.Stdin,
+						ErrOut: os.Stderr,
+					},
+					Logger: testlogr,
+				},
+				Remote: options.Remote{
+					PlainHTTP: true,
+				},
+				Source:         fmt.Sprintf("%s/client-linked-template:latest", u.Host),
+				PullAll:        true,
+				NoVerify:       true,
+				OutputTemplate: "{{.collection}}/{{.title}}",
+			},
+			validate: true,
+			assertFunc: func(path string) bool {
+				for _, name := range []string{"hello.txt", "aggregate.txt", "aggregate2.txt"} {
+					actual := filepath.Join(path, u.Host, "client-linked-template", name)
+					if _, err := os.Stat(actual); err != nil {
+						return false
+					}
+				}
+				return true
+			},
+		},
+		{
+			name: "Failure/OutputTemplateCollision",
+			opts: &PullOptions{
+				Common: &options.Common{
+					IOStreams: genericclioptions.IOStreams{
+						Out:    os.Stdout,
+						In:     os.Stdin,
+						ErrOut: os.Stderr,
+					},
+					Logger: testlogr,
+				},
+				Remote: options.Remote{
+					PlainHTTP: true,
+				},
+				Source:         fmt.Sprintf("%s/client-linked-collision:latest", u.Host),
+				PullAll:        true,
+				NoVerify:       true,
+				OutputTemplate: "{{.collection}}",
+			},
+			validate: true,
+			// The colliding files depend on the order the files are pulled in.
+			assertErr: func(t *testing.T, err error) {
+				require.Error(t, err)
+				require.Regexp(t, `^files \S+ and \S+ both render to path `+regexp.QuoteMeta(u.Host+"/client-linked-collision")+`$`, err.Error())
+			},
+		},
+		{
+			name: "Success/PullAllWithLimits",
+			opts: &PullOptions{
+				Common: &options.Common{
+					IOStreams: genericclioptions.IOStreams{
+						Out:    os.Stdout,
+						In:     os.Stdin,
+						ErrOut: os.Stderr,
+					},
+					Logger: testlogr,
+				},
+				Remote: options.Remote{
+					PlainHTTP: true,
+				},
+				Source:   fmt.Sprintf("%s/client-linked-limits:latest", u.Host),
+				PullAll:  true,
+				NoVerify: true,
+				MaxDepth: 1,
+				MaxNodes: 100,
+				MaxBytes: "1M",
+			},
+			validate: true,
+			assertFunc: func(path string) bool {
+				for _, name := range []string{"hello.txt", "aggregate.txt", "aggregate2.txt"} {
+					if _, err := os.Stat(filepath.Join(path, name)); err != nil {
+						return false
+					}
+				}
+				return true
+			},
+		},
+		{
+			name: "Failure/PullAllNodeBudget",
+			opts: &PullOptions{
+				Common: &options.Common{
+					IOStreams: genericclioptions.IOStreams{
+						Out:    os.Stdout,
+						In:     os.Stdin,
+						ErrOut: os.Stderr,
+					},
+					Logger: testlogr,
+				},
+				Remote: options.Remote{
+					PlainHTTP: true,
+				},
+				Source:   fmt.Sprintf("%s/client-linked-nodes:latest", u.Host),
+				PullAll:  true,
+				NoVerify: true,
+				MaxNodes: 1,
+			},
+			validate: true,
+			assertErr: func(t *testing.T, err error) {
+				var budgetErr *traversal.ErrBudgetExceeded
+				require.ErrorAs(t, err, &budgetErr)
+				require.Zero(t, budgetErr.Bytes)
+			},
+		},
+		{
+			name: "Failure/PullAllByteBudget",
+			opts: &PullOptions{
+				Common: &options.Common{
+					IOStreams: genericclioptions.IOStreams{
+						Out:    os.Stdout,
+						In:     os.Stdin,
+						ErrOut: os.Stderr,
+					},
+					Logger: testlogr,
+				},
+				Remote: options.Remote{
+					PlainHTTP: true,
+				},
+				Source:   fmt.Sprintf("%s/client-linked-bytes:latest", u.Host),
+				PullAll:  true,
+				NoVerify: true,
+				MaxBytes: "1",
+			},
+			validate: true,
+			assertErr: func(t *testing.T, err error) {
+				var budgetErr *traversal.ErrBudgetExceeded
+				require.ErrorAs(t, err, &budgetErr)
+				require.Equal(t, int64(2002), budgetErr.Bytes)
+				require.True(t, strings.HasPrefix(err.Error(), fmt.Sprintf("collection %s/client-linked-bytes:latest: ", u.Host)))
+			},
+		},
 		{
 			name: "Success/PullAllWithAttributes",
 			opts: &PullOptions{
@@ -265,8 +512,13 @@ func TestPullRun(t *testing.T) {
 			require.NoError(t, os.MkdirAll(cache, 0750))
 			c.opts.CacheDir = cache
 
+			if c.validate {
+				require.NoError(t, c.opts.Validate())
+			}
 			err := c.opts.Run(context.TODO())
-			if c.expError != "" {
+			if c.assertErr != nil {
+				c.assertErr(t, err)
+			} else if c.expError != "" {
 				require.EqualError(t, err, c.expError)
 			} else {
 				require.NoError(t, err)
@@ -276,6 +528,267 @@ func TestPullRun(t *testing.T) {
 	}
 }
 
+func TestPullRunFileInfo(t *testing.T) {
+	testlogr, err := log.NewLogrusLogger(io.Discard, "debug")
+	require.NoError(t, err)
+
+	server := httptest.NewServer(registry.New())
+	t.Cleanup(server.Close)
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	cache := filepath.Join(t.TempDir(), "cache")
+	require.NoError(t, os.MkdirAll(cache, 0750))
+	reference := fmt.Sprintf("%s/client-fileinfo:latest", u.Host)
+
+	buildOpts := &BuildCollectionOptions{
+		BuildOptions: &BuildOptions{
+			Common: &options.Common{
+				Logger:   testlogr,
+				CacheDir: cache,
+			},
+			Destination: reference,
+		},
+		RootDir:  "./testdata/run-workspace",
+		DSConfig: "./testdata/configs/dataset-config-fileinfo.yaml",
+	}
+	require.NoError(t, buildOpts.Run(context.TODO()))
+	pushOpts := &PushOptions{
+		Common: &options.Common{
+			Logger:   testlogr,
+			CacheDir: cache,
+		},
+		Remote: options.Remote{
+			PlainHTTP: true,
+		},
+		Destination: reference,
+	}
+	require.NoError(t, pushOpts.Run(context.TODO()))
+
+	type spec struct {
+		name       string
+		owner      string
+		pullAll    bool
+		assertFunc func(t *testing.T, info os.FileInfo)
+	}
+
+	cases := []spec{
+		{
+			name: "Success/Permissions",
+			assertFunc: func(t *testing.T, info os.FileInfo) {
+				require.Equal(t, os.FileMode(0750), info.Mode().Perm())
+			},
+		},
+		{
+			name:    "Success/PermissionsPullAll",
+			pullAll: true,
+			assertFunc: func(t *testing.T, info os.FileInfo) {
+				require.Equal(t, os.FileMode(0750), info.Mode().Perm())
+			},
+		},
+		{
+			name:  "Success/MappedOwner",
+			owner: fmt.Sprintf("%d:%d", os.Getuid(), os.Getgid()),
+			assertFunc: func(t *testing.T, info os.FileInfo) {
+				require.Equal(t, os.FileMode(0750), info.Mode().Perm())
+				stat, ok := info.Sys().(*syscall.Stat_t)
+				require.True(t, ok)
+				require.Equal(t, uint32(os.Getuid()), stat.Uid)
+				require.Equal(t, uint32(os.Getgid()), stat.Gid)
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			tmp := t.TempDir()
+			pullCache := filepath.Join(t.TempDir(), "cache")
+			require.NoError(t, os.MkdirAll(pullCache, 0750))
+			opts := &PullOptions{
+				Common: &options.Common{
+					IOStreams: genericclioptions.IOStreams{
+						Out:    os.Stdout,
+						In:     os.Stdin,
+						ErrOut: os.Stderr,
+					},
+					Logger:   testlogr,
+					CacheDir: pullCache,
+				},
+				Remote: options.Remote{
+					PlainHTTP: true,
+				},
+				Source:   reference,
+				Output:   tmp,
+				PullAll:  c.pullAll,
+				Owner:    c.owner,
+				NoVerify: true,
+			}
+			require.NoError(t, opts.Validate())
+			require.NoError(t, opts.Run(context.TODO()))
+
+			info, err := os.Stat(filepath.Join(tmp, "bin", "hello.sh"))
+			require.NoError(t, err)
+			c.assertFunc(t, info)
+		})
+	}
+}
+
+func TestPullRunLinkMode(t *testing.T) {
+	testlogr, err := log.NewLogrusLogger(io.Discard, "debug")
+	require.NoError(t, err)
+
+	server := httptest.NewServer(registry.New())
+	t.Cleanup(server.Close)
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	reference := fmt.Sprintf("%s/client-link:latest", u.Host)
+	prepTestArtifact(t, reference)
+	cache := filepath.Join(t.TempDir(), "cache")
+	require.NoError(t, os.MkdirAll(cache, 0750))
+
+	pull := func(output string) {
+		opts := &PullOptions{
+			Common: &options.Common{
+				IOStreams: genericclioptions.IOStreams{
+					Out:    os.Stdout,
+					In:     os.Stdin,
+					ErrOut: os.Stderr,
+				},
+				Logger:   testlogr,
+				CacheDir: cache,
+			},
+			Remote: options.Remote{
+				PlainHTTP: true,
+			},
+			Source:   reference,
+			Output:   output,
+			LinkMode: "hardlink",
+			NoVerify: true,
+		}
+		require.NoError(t, opts.Validate())
+		require.NoError(t, opts.Run(context.TODO()))
+	}
+
+	// The first pull copies the files while caching them, and
+	// later pulls link the files to the same cached blobs.
+	first := t.TempDir()
+	pull(first)
+	second := t.TempDir()
+	pull(second)
+
+	firstInfo, err := os.Stat(filepath.Join(first, "hello.txt"))
+	require.NoError(t, err)
+	secondInfo, err := os.Stat(filepath.Join(second, "hello.txt"))
+	require.NoError(t, err)
+	require.False(t, os.SameFile(firstInfo, secondInfo))
+
+	third := t.TempDir()
+	pull(third)
+	thirdInfo, err := os.Stat(filepath.Join(third, "hello.txt"))
+	require.NoError(t, err)
+	require.True(t, os.SameFile(secondInfo, thirdInfo))
+	require.Zero(t, thirdInfo.Mode().Perm()&0222)
+}
+
+func TestPullRunMaxDepth(t *testing.T) {
+	testlogr, err := log.NewLogrusLogger(io.Discard, "debug")
+	require.NoError(t, err)
+
+	server := httptest.NewServer(registry.New())
+	t.Cleanup(server.Close)
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	// Publish a chain of links: root.txt -> mid.txt -> leaf.txt.
+	reference := fmt.Sprintf("%s/client-depth:latest", u.Host)
+	r, err := orasregistry.ParseReference(reference)
+	require.NoError(t, err)
+	linkJSON, err := json.Marshal(descriptor.Properties{
+		Link: &empspec.LinkAttributes{
+			RegistryHint:  r.Registry,
+			NamespaceHint: r.Repository,
+			Transitive:    true,
+		},
+	})
+	require.NoError(t, err)
+	publishLinked := func(fileName, ref string, links ...ocispec.Descriptor) ocispec.Descriptor {
+		annotations := map[string]string{}
+		// Linked descriptors carry the link attributes so
+		// that they are loaded as links to other collections.
+		for i := range links {
+			links[i].Annotations = map[string]string{empspec.AnnotationEmporousAttributes: string(linkJSON)}
+		}
+		if len(links) != 0 {
+			linksJSON, err := json.Marshal(links)
+			require.NoError(t, err)
+			annotations[empspec.AnnotationLink] = string(linksJSON)
+		}
+		desc, err := publishFunc(fileName, ref, []byte(fileName), nil, annotations)
+		require.NoError(t, err)
+		return desc
+	}
+	leaf := publishLinked("leaf.txt", reference+"-leaf")
+	mid := publishLinked("mid.txt", reference+"-mid", leaf)
+	publishLinked("root.txt", reference, mid)
+
+	type spec struct {
+		name     string
+		maxDepth int
+		expFiles []string
+		expNone  []string
+	}
+
+	cases := []spec{
+		{
+			name:     "Success/Unlimited",
+			expFiles: []string{"root.txt", "mid.txt", "leaf.txt"},
+		},
+		{
+			name:     "Success/OneLink",
+			maxDepth: 1,
+			expFiles: []string{"root.txt", "mid.txt"},
+			expNone:  []string{"leaf.txt"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			tmp := t.TempDir()
+			cache := filepath.Join(t.TempDir(), "cache")
+			require.NoError(t, os.MkdirAll(cache, 0750))
+			opts := &PullOptions{
+				Common: &options.Common{
+					IOStreams: genericclioptions.IOStreams{
+						Out:    os.Stdout,
+						In:     os.Stdin,
+						ErrOut: os.Stderr,
+					},
+					Logger:   testlogr,
+					CacheDir: cache,
+				},
+				Remote: options.Remote{
+					PlainHTTP: true,
+				},
+				Source:   reference,
+				Output:   tmp,
+				PullAll:  true,
+				MaxDepth: c.maxDepth,
+				NoVerify: true,
+			}
+			require.NoError(t, opts.Validate())
+			require.NoError(t, opts.Run(context.TODO()))
+
+			for _, name := range c.expFiles {
+				require.FileExists(t, filepath.Join(tmp, name))
+			}
+			for _, name := range c.expNone {
+				require.NoFileExists(t, filepath.Join(tmp, name))
+			}
+		})
+	}
+}
+
 // prepTestArtifact will push a hello.txt artifact into the
 // registry for retrieval. Uses methods from oras-go.
 func prepTestArtifact(t *testing.T, ref string) {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -12,8 +13,11 @@ import (
 	"oras.land/oras-go/v2/registry"
 
 	"github.com/emporous/emporous-go/cmd/client/commands/options"
+	"github.com/emporous/emporous-go/content"
 	"github.com/emporous/emporous-go/content/layout"
+	"github.com/emporous/emporous-go/manager"
 	"github.com/emporous/emporous-go/manager/defaultmanager"
+	"github.com/emporous/emporous-go/registryclient"
 	"github.com/emporous/emporous-go/registryclient/orasclient"
 	"github.com/emporous/emporous-go/util/examples"
 )
@@ -24,14 +28,26 @@ type PushOptions struct {
 	*options.Common
 	options.Remote
 	options.RemoteAuth
-	Destination string
-	Sign        bool
+	options.Progress
+	options.Transfer
+	Destination   string
+	Tags          []string
+	RehydrateFrom string
+	Sign          bool
+	Resumable     bool
 }
 
-var clientPushExamples = examples.Example{
-	RootCommand:   filepath.Base(os.Args[0]),
-	Descriptions:  []string{"Push artifacts."},
-	CommandString: "push localhost:5000/myartifacts:latest",
+var clientPushExamples = []examples.Example{
+	{
+		RootCommand:   filepath.Base(os.Args[0]),
+		Descriptions:  []string{"Push artifacts."},
+		CommandString: "push localhost:5000/myartifacts:latest",
+	},
+	{
+		RootCommand:   filepath.Base(os.Args[0]),
+		Descriptions:  []string{"Push artifacts once and tag them with additional tags."},
+		CommandString: "push localhost:5000/myartifacts:1.4.2 --tag 1.4 --tag latest",
+	},
 }
 
 // NewPushCmd creates a new cobra.Command for the push subcommand.
@@ -41,7 +57,7 @@ func NewPushCmd(common *options.Common) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:           "push DST",
 		Short:         "Push a Emporous collection into a registry",
-		Example:       examples.FormatExamples(clientPushExamples),
+		Example:       examples.FormatExamples(clientPushExamples...),
 		SilenceErrors: false,
 		SilenceUsage:  false,
 		Args:          cobra.ExactArgs(1),
@@ -54,7 +70,12 @@ func NewPushCmd(common *options.Common) *cobra.Command {
 
 	o.Remote.BindFlags(cmd.Flags())
 	o.RemoteAuth.BindFlags(cmd.Flags())
+	o.Progress.BindFlags(cmd.Flags())
+	o.Transfer.BindFlags(cmd.Flags())
 
+	cmd.Flags().StringArrayVarP(&o.Tags, "tag", "t", o.Tags, "additional tags to apply in the destination repository after the content is uploaded")
+	cmd.Flags().StringVar(&o.RehydrateFrom, "rehydrate-from", o.RehydrateFrom, "remote reference to fetch content missing from the cached collection before pushing (e.g. after a filtered pull)")
+	cmd.Flags().BoolVar(&o.Resumable, "resumable", o.Resumable, "upload blobs of 8 MiB or more in chunks so an interrupted push resumes from the last chunk received")
 	cmd.Flags().BoolVarP(&o.Sign, "sign", "s", o.Sign, "keyless OIDC signing of emporous Collections with Sigstore")
 
 	return cmd
@@ -69,6 +90,25 @@ func (o *PushOptions) Complete(args []string) error {
 }
 
 func (o *PushOptions) Validate() error {
+	if err := o.Progress.Validate(); err != nil {
+		return err
+	}
+	if err := o.Transfer.Validate(); err != nil {
+		return err
+	}
+	if len(o.Tags) == 0 {
+		return nil
+	}
+	reference, err := registry.ParseReference(o.Destination)
+	if err != nil {
+		return err
+	}
+	for _, tag := range o.Tags {
+		reference.Reference = tag
+		if err := reference.ValidateReferenceAsTag(); err != nil {
+			return fmt.Errorf("tag %q: %w", tag, err)
+		}
+	}
 	return nil
 }
 
@@ -78,12 +118,22 @@ func (o *PushOptions) Run(ctx context.Context) error {
 		return err
 	}
 
-	client, err := orasclient.NewClient(
+	clientOpts := []orasclient.ClientOption{
 		orasclient.SkipTLSVerify(o.Insecure),
 		orasclient.WithAuthConfigs(o.Configs),
 		orasclient.WithPlainHTTP(o.PlainHTTP),
-	)
+		orasclient.WithIngestDir(ingestDir(o.Common)),
+		orasclient.WithResumableUploads(o.Resumable),
+	}
+	transferOpts, err := transferOptions(o.Transfer)
+	if err != nil {
+		return err
+	}
+	clientOpts = append(clientOpts, transferOpts...)
+	progressOpts, finishProgress := progressOptions(o.Common, o.Progress)
+	clientOpts = append(clientOpts, progressOpts...)
 
+	client, err := orasclient.NewClient(clientOpts...)
 	if err != nil {
 		return fmt.Errorf("error configuring client: %v", err)
 	}
@@ -93,8 +143,16 @@ func (o *PushOptions) Run(ctx context.Context) error {
 		}
 	}()
 
+	if err := o.rehydrate(ctx, cache, client); err != nil {
+		return err
+	}
+
 	manager := defaultmanager.New(cache, o.Logger)
-	digest, err := manager.Push(ctx, o.Destination, client)
+	digest, tagResults, err := manager.Push(ctx, o.Destination, client, o.Tags...)
+	finishProgress()
+	// The push error summarizes any tags that failed, so the
+	// results are written before it is returned.
+	writeTagResults(o.IOStreams.Out, tagResults)
 	if err != nil {
 		return err
 	}
@@ -118,3 +176,42 @@ func (o *PushOptions) Run(ctx context.Context) error {
 
 	return nil
 }
+
+// rehydrate fetches content missing from the cached collection when a rehydration source
+// is set, or fails listing the missing digests. Filtered pulls leave sparse manifests in the
+// cache and some registries reject manifests with missing blobs.
+func (o *PushOptions) rehydrate(ctx context.Context, cache *layout.Layout, client registryclient.Remote) error {
+	// References that are not stored are reported when pushing.
+	if _, err := cache.Resolve(ctx, o.Destination); err != nil {
+		return nil
+	}
+
+	if o.RehydrateFrom != "" {
+		fetched, err := rehydrate(ctx, cache, o.Destination, client, o.RehydrateFrom)
+		if err != nil {
+			return err
+		}
+		if len(fetched) != 0 {
+			o.Logger.Infof("Fetched %d missing blob(s) from %s", len(fetched), o.RehydrateFrom)
+		}
+		return nil
+	}
+
+	err := checkMissing(ctx, cache, o.Destination)
+	var missingErr *content.ErrMissingContent
+	if errors.As(err, &missingErr) {
+		return fmt.Errorf("%w: run \"rehydrate\" or set --rehydrate-from to fetch the missing content", err)
+	}
+	return err
+}
+
+// writeTagResults writes the result of tagging each reference.
+func writeTagResults(w io.Writer, results []manager.TagResult) {
+	for _, result := range results {
+		if result.Err != nil {
+			fmt.Fprintf(w, "Failed to tag %s: %v\n", result.Reference, result.Err)
+			continue
+		}
+		fmt.Fprintf(w, "Tagged %s\n", result.Reference)
+	}
+}
@@ -1,7 +1,9 @@
 package commands
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http/httptest"
@@ -13,9 +15,12 @@ import (
 	"github.com/google/go-containerregistry/pkg/registry"
 	"github.com/stretchr/testify/require"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
+	orasregistry "oras.land/oras-go/v2/registry"
+	"oras.land/oras-go/v2/registry/remote"
 
 	"github.com/emporous/emporous-go/cmd/client/commands/options"
 	"github.com/emporous/emporous-go/log"
+	"github.com/emporous/emporous-go/manager"
 )
 
 func TestPushComplete(t *testing.T) {
@@ -58,6 +63,15 @@ func TestPushComplete(t *testing.T) {
 	}
 }
 
+func TestWriteTagResults(t *testing.T) {
+	out := new(bytes.Buffer)
+	writeTagResults(out, []manager.TagResult{
+		{Reference: "localhost:5001/test:1.4"},
+		{Reference: "localhost:5001/test:latest", Err: errors.New("unauthorized")},
+	})
+	require.Equal(t, "Tagged localhost:5001/test:1.4\nFailed to tag localhost:5001/test:latest: unauthorized\n", out.String())
+}
+
 func TestPushRun(t *testing.T) {
 	testlogr, err := log.NewLogrusLogger(ioutil.Discard, "debug")
 	require.NoError(t, err)
@@ -68,9 +82,11 @@ func TestPushRun(t *testing.T) {
 	require.NoError(t, err)
 
 	type spec struct {
-		name     string
-		opts     *PushOptions
-		expError string
+		name      string
+		opts      *PushOptions
+		expTags   []string
+		expOutput string
+		expError  string
 	}
 
 	cases := []spec{
@@ -91,6 +107,45 @@ func TestPushRun(t *testing.T) {
 				Destination: fmt.Sprintf("%s/success:latest", u.Host),
 			},
 		},
+		{
+			name: "Success/WithTags",
+			opts: &PushOptions{
+				Common: &options.Common{
+					IOStreams: genericclioptions.IOStreams{
+						Out:    os.Stdout,
+						In:     os.Stdin,
+						ErrOut: os.Stderr,
+					},
+					Logger: testlogr,
+				},
+				Remote: options.Remote{
+					PlainHTTP: true,
+				},
+				Destination: fmt.Sprintf("%s/success:1.4.2", u.Host),
+				Tags:        []string{"1.4", "latest", "1.4"},
+			},
+			expTags:   []string{"1.4.2", "1.4", "latest"},
+			expOutput: fmt.Sprintf("Tagged %s/success:1.4\nTagged %s/success:latest\n", u.Host, u.Host),
+		},
+		{
+			name: "Failure/InvalidTag",
+			opts: &PushOptions{
+				Common: &options.Common{
+					IOStreams: genericclioptions.IOStreams{
+						Out:    os.Stdout,
+						In:     os.Stdin,
+						ErrOut: os.Stderr,
+					},
+					Logger: testlogr,
+				},
+				Remote: options.Remote{
+					PlainHTTP: true,
+				},
+				Destination: fmt.Sprintf("%s/invalid:latest", u.Host),
+				Tags:        []string{"not/a/tag"},
+			},
+			expError: `tag "not/a/tag": invalid reference: invalid tag`,
+		},
 		{
 			name: "Failure/NotStored",
 			opts: &PushOptions{
@@ -124,12 +179,31 @@ func TestPushRun(t *testing.T) {
 				require.NoError(t, err)
 			}
 
+			out := new(bytes.Buffer)
+			c.opts.IOStreams.Out = out
 			err := c.opts.Run(context.TODO())
 			if c.expError != "" {
 				require.EqualError(t, err, c.expError)
 			} else {
 				require.NoError(t, err)
 			}
+			require.Equal(t, c.expOutput, out.String())
+
+			var digests []string
+			for _, tag := range c.expTags {
+				ref, err := orasregistry.ParseReference(c.opts.Destination)
+				require.NoError(t, err)
+				ref.Reference = tag
+				repo, err := remote.NewRepository(ref.String())
+				require.NoError(t, err)
+				repo.PlainHTTP = true
+				desc, err := repo.Resolve(context.TODO(), ref.String())
+				require.NoError(t, err)
+				digests = append(digests, desc.Digest.String())
+			}
+			for _, d := range digests {
+				require.Equal(t, digests[0], d)
+			}
 		})
 	}
 }
@@ -0,0 +1,113 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/spf13/cobra"
+
+	"github.com/emporous/emporous-go/cmd/client/commands/options"
+	"github.com/emporous/emporous-go/registryclient/orasclient"
+	"github.com/emporous/emporous-go/util/examples"
+)
+
+// ReferrersOptions describe configuration options that can
+// be set using the referrers subcommand.
+type ReferrersOptions struct {
+	*options.Common
+	options.Remote
+	options.RemoteAuth
+	Reference    string
+	ArtifactType string
+}
+
+var clientReferrersExamples = []examples.Example{
+	{
+		RootCommand:   filepath.Base(os.Args[0]),
+		CommandString: "referrers localhost:5001/test:latest",
+		Descriptions: []string{
+			"List the artifacts attached to a collection.",
+		},
+	},
+	{
+		RootCommand:   filepath.Base(os.Args[0]),
+		CommandString: "referrers localhost:5001/test:latest --artifact-type application/spdx+json",
+		Descriptions: []string{
+			"List the artifacts of a type attached to a collection.",
+		},
+	},
+}
+
+// NewReferrersCmd creates a new cobra.Command for the referrers subcommand.
+func NewReferrersCmd(common *options.Common) *cobra.Command {
+	o := ReferrersOptions{Common: common}
+
+	cmd := &cobra.Command{
+		Use:           "referrers REF",
+		Short:         "List the artifacts attached to a Emporous collection",
+		Example:       examples.FormatExamples(clientReferrersExamples...),
+		SilenceErrors: false,
+		SilenceUsage:  false,
+		Args:          cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			cobra.CheckErr(o.Complete(args))
+			cobra.CheckErr(o.Run(cmd.Context()))
+		},
+	}
+
+	o.Remote.BindFlags(cmd.Flags())
+	o.RemoteAuth.BindFlags(cmd.Flags())
+
+	cmd.Flags().StringVar(&o.ArtifactType, "artifact-type", o.ArtifactType, "only list artifacts of this artifact type")
+
+	return cmd
+}
+
+func (o *ReferrersOptions) Complete(args []string) error {
+	if len(args) < 1 {
+		return errors.New("bug: expecting one argument")
+	}
+	o.Reference = args[0]
+	return nil
+}
+
+func (o *ReferrersOptions) Run(ctx context.Context) error {
+	client, err := orasclient.NewClient(
+		orasclient.SkipTLSVerify(o.Insecure),
+		orasclient.WithAuthConfigs(o.Configs),
+		orasclient.WithPlainHTTP(o.PlainHTTP),
+	)
+	if err != nil {
+		return fmt.Errorf("error configuring client: %v", err)
+	}
+	defer func() {
+		if err := client.Destroy(); err != nil {
+			o.Logger.Errorf(err.Error())
+		}
+	}()
+
+	referrers, err := client.Referrers(ctx, o.Reference, o.ArtifactType)
+	if err != nil {
+		return err
+	}
+	return formatReferrers(o.IOStreams.Out, referrers)
+}
+
+func formatReferrers(w io.Writer, referrers []ocispec.Descriptor) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	if _, err := fmt.Fprintln(tw, "Digest\tArtifactType\tSize\tMediaType"); err != nil {
+		return err
+	}
+	for _, desc := range referrers {
+		if _, err := fmt.Fprintf(tw, "%s\t%s\t%d\t%s\n", desc.Digest, desc.ArtifactType, desc.Size, desc.MediaType); err != nil {
+			return err
+		}
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,150 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/spf13/cobra"
+
+	"github.com/emporous/emporous-go/cmd/client/commands/options"
+	"github.com/emporous/emporous-go/content"
+	"github.com/emporous/emporous-go/content/layout"
+	"github.com/emporous/emporous-go/registryclient"
+	"github.com/emporous/emporous-go/registryclient/orasclient"
+	"github.com/emporous/emporous-go/util/examples"
+)
+
+// RehydrateOptions describe configuration options that can
+// be set using the rehydrate subcommand.
+type RehydrateOptions struct {
+	*options.Common
+	options.Remote
+	options.RemoteAuth
+	Reference string
+	From      string
+}
+
+var clientRehydrateExamples = []examples.Example{
+	{
+		RootCommand:   filepath.Base(os.Args[0]),
+		CommandString: "rehydrate localhost:5001/test:latest",
+		Descriptions: []string{
+			"Fetch the blobs missing from a collection in the cache after a filtered pull.",
+		},
+	},
+	{
+		RootCommand:   filepath.Base(os.Args[0]),
+		CommandString: "rehydrate localhost:5001/copy:latest --from localhost:5001/test:latest",
+		Descriptions: []string{
+			"Fetch the missing blobs from the collection the cached content was originally pulled from.",
+		},
+	},
+}
+
+// NewRehydrateCmd creates a new cobra.Command for the rehydrate subcommand.
+func NewRehydrateCmd(common *options.Common) *cobra.Command {
+	o := RehydrateOptions{Common: common}
+
+	cmd := &cobra.Command{
+		Use:           "rehydrate REF",
+		Short:         "Fetch content missing from a sparse Emporous collection in the cache",
+		Example:       examples.FormatExamples(clientRehydrateExamples...),
+		SilenceErrors: false,
+		SilenceUsage:  false,
+		Args:          cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			cobra.CheckErr(o.Complete(args))
+			cobra.CheckErr(o.Validate())
+			cobra.CheckErr(o.Run(cmd.Context()))
+		},
+	}
+
+	o.Remote.BindFlags(cmd.Flags())
+	o.RemoteAuth.BindFlags(cmd.Flags())
+
+	cmd.Flags().StringVar(&o.From, "from", o.From, "remote reference to fetch missing content from. Defaults to the collection reference")
+
+	return cmd
+}
+
+func (o *RehydrateOptions) Complete(args []string) error {
+	if len(args) < 1 {
+		return errors.New("bug: expecting one argument")
+	}
+	o.Reference = args[0]
+	if o.From == "" {
+		o.From = o.Reference
+	}
+	return nil
+}
+
+func (o *RehydrateOptions) Validate() error {
+	return nil
+}
+
+func (o *RehydrateOptions) Run(ctx context.Context) error {
+	cache, err := layout.NewWithContext(ctx, o.CacheDir)
+	if err != nil {
+		return err
+	}
+
+	client, err := orasclient.NewClient(
+		orasclient.SkipTLSVerify(o.Insecure),
+		orasclient.WithAuthConfigs(o.Configs),
+		orasclient.WithPlainHTTP(o.PlainHTTP),
+	)
+	if err != nil {
+		return fmt.Errorf("error configuring client: %v", err)
+	}
+	defer func() {
+		if err := client.Destroy(); err != nil {
+			o.Logger.Errorf(err.Error())
+		}
+	}()
+
+	fetched, err := rehydrate(ctx, cache, o.Reference, client, o.From)
+	if err != nil {
+		return err
+	}
+	o.Logger.Infof("Fetched %d blob(s) for %s from %s", len(fetched), o.Reference, o.From)
+	return nil
+}
+
+// rehydrate fetches the content missing from the collection stored under
+// the reference in the cache from the source reference.
+func rehydrate(ctx context.Context, cache *layout.Layout, reference string, client registryclient.Remote, source string) ([]ocispec.Descriptor, error) {
+	return cache.Rehydrate(ctx, reference, remoteFetcher{client: client, reference: source})
+}
+
+// checkMissing returns an error listing the digests of the content
+// missing from the collection stored under the reference in the cache.
+func checkMissing(ctx context.Context, cache *layout.Layout, reference string) error {
+	missing, err := cache.Missing(ctx, reference)
+	if err != nil {
+		return err
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	missingErr := &content.ErrMissingContent{Reference: reference}
+	for _, desc := range missing {
+		missingErr.Digests = append(missingErr.Digests, desc.Digest.String())
+	}
+	return missingErr
+}
+
+// remoteFetcher fetches content for descriptors from a remote reference.
+type remoteFetcher struct {
+	client    registryclient.Remote
+	reference string
+}
+
+// Fetch fetches the content identified by the descriptor.
+func (f remoteFetcher) Fetch(ctx context.Context, desc ocispec.Descriptor) (io.ReadCloser, error) {
+	return f.client.FetchContent(ctx, f.reference, desc)
+}
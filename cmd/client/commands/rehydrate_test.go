@@ -0,0 +1,137 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/stretchr/testify/require"
+
+	"github.com/emporous/emporous-go/cmd/client/commands/options"
+	"github.com/emporous/emporous-go/content/layout"
+	"github.com/emporous/emporous-go/log"
+)
+
+func TestRehydrateRun(t *testing.T) {
+	testlogr, err := log.NewLogrusLogger(ioutil.Discard, "debug")
+	require.NoError(t, err)
+
+	server := httptest.NewServer(registry.New())
+	t.Cleanup(server.Close)
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	source := fmt.Sprintf("%s/client-sparse:latest", u.Host)
+	buildCache := filepath.Join(t.TempDir(), "cache")
+	require.NoError(t, os.MkdirAll(buildCache, 0750))
+	buildOpts := &BuildCollectionOptions{
+		BuildOptions: &BuildOptions{
+			Common: &options.Common{
+				Logger:   testlogr,
+				CacheDir: buildCache,
+			},
+			Destination: source,
+		},
+		RootDir:  "./testdata/multi-level-workspace",
+		DSConfig: "./testdata/configs/dataset-config-sparse.yaml",
+	}
+	require.NoError(t, buildOpts.Run(context.TODO()))
+	buildPushOpts := &PushOptions{
+		Common: &options.Common{
+			Logger:   testlogr,
+			CacheDir: buildCache,
+		},
+		Remote: options.Remote{
+			PlainHTTP: true,
+		},
+		Destination: source,
+	}
+	require.NoError(t, buildPushOpts.Run(context.TODO()))
+
+	// sparsePull pulls the collection into a new cache with an
+	// attribute query that only matches some of the files.
+	sparsePull := func(t *testing.T) string {
+		cache := filepath.Join(t.TempDir(), "cache")
+		require.NoError(t, os.MkdirAll(cache, 0750))
+		pullOpts := &PullOptions{
+			Common: &options.Common{
+				Logger:   testlogr,
+				CacheDir: cache,
+			},
+			Remote: options.Remote{
+				PlainHTTP: true,
+			},
+			Source:         source,
+			Output:         t.TempDir(),
+			AttributeQuery: "testdata/configs/sparse.yaml",
+			NoVerify:       true,
+		}
+		require.NoError(t, pullOpts.Run(context.TODO()))
+		return cache
+	}
+
+	t.Run("Failure/PushSparseManifest", func(t *testing.T) {
+		cache := sparsePull(t)
+		pushOpts := &PushOptions{
+			Common: &options.Common{
+				Logger:   testlogr,
+				CacheDir: cache,
+			},
+			Remote: options.Remote{
+				PlainHTTP: true,
+			},
+			Destination: source,
+		}
+		err := pushOpts.Run(context.TODO())
+		require.ErrorContains(t, err, fmt.Sprintf("reference %s is missing ", source))
+		require.ErrorContains(t, err, "--rehydrate-from")
+	})
+
+	t.Run("Success/PushRehydrateFrom", func(t *testing.T) {
+		cache := sparsePull(t)
+		pushOpts := &PushOptions{
+			Common: &options.Common{
+				Logger:   testlogr,
+				CacheDir: cache,
+			},
+			Remote: options.Remote{
+				PlainHTTP: true,
+			},
+			Destination:   source,
+			RehydrateFrom: source,
+		}
+		require.NoError(t, pushOpts.Run(context.TODO()))
+		assertNotSparse(t, cache, source)
+	})
+
+	t.Run("Success/Rehydrate", func(t *testing.T) {
+		cache := sparsePull(t)
+		opts := &RehydrateOptions{
+			Common: &options.Common{
+				Logger:   testlogr,
+				CacheDir: cache,
+			},
+			Remote: options.Remote{
+				PlainHTTP: true,
+			},
+		}
+		require.NoError(t, opts.Complete([]string{source}))
+		require.Equal(t, source, opts.From)
+		require.NoError(t, opts.Run(context.TODO()))
+		assertNotSparse(t, cache, source)
+	})
+}
+
+func assertNotSparse(t *testing.T, cache, reference string) {
+	l, err := layout.NewWithContext(context.TODO(), cache)
+	require.NoError(t, err)
+	missing, err := l.Missing(context.TODO(), reference)
+	require.NoError(t, err)
+	require.Empty(t, missing)
+}
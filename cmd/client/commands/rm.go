@@ -0,0 +1,73 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/emporous/emporous-go/cmd/client/commands/options"
+	"github.com/emporous/emporous-go/content/layout"
+	"github.com/emporous/emporous-go/util/examples"
+)
+
+// RemoveOptions describe configuration options that can
+// be set using the rm subcommand.
+type RemoveOptions struct {
+	*options.Common
+	Reference string
+}
+
+var clientRemoveExamples = examples.Example{
+	RootCommand:   filepath.Base(os.Args[0]),
+	Descriptions:  []string{"Remove a collection from the cache. Content shared with other references is kept."},
+	CommandString: "rm localhost:5001/test:latest",
+}
+
+// NewRemoveCmd creates a new cobra.Command for the rm subcommand.
+func NewRemoveCmd(common *options.Common) *cobra.Command {
+	o := RemoveOptions{Common: common}
+
+	cmd := &cobra.Command{
+		Use:           "rm REF",
+		Short:         "Remove a Emporous collection from the cache",
+		Example:       examples.FormatExamples(clientRemoveExamples),
+		SilenceErrors: false,
+		SilenceUsage:  false,
+		Args:          cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			cobra.CheckErr(o.Complete(args))
+			cobra.CheckErr(o.Validate())
+			cobra.CheckErr(o.Run(cmd.Context()))
+		},
+	}
+
+	return cmd
+}
+
+func (o *RemoveOptions) Complete(args []string) error {
+	if len(args) < 1 {
+		return errors.New("bug: expecting one argument")
+	}
+	o.Reference = args[0]
+	return nil
+}
+
+func (o *RemoveOptions) Validate() error {
+	return nil
+}
+
+func (o *RemoveOptions) Run(ctx context.Context) error {
+	cache, err := layout.NewWithContext(ctx, o.CacheDir)
+	if err != nil {
+		return err
+	}
+	deleted, err := cache.Delete(ctx, o.Reference)
+	if err != nil {
+		return err
+	}
+	o.Logger.Infof("Removed %s and %d unreferenced blob(s)", o.Reference, len(deleted))
+	return nil
+}
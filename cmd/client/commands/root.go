@@ -60,10 +60,21 @@ func NewRootCmd() *cobra.Command {
 	o.BindFlags(cmd.PersistentFlags())
 
 	cmd.AddCommand(NewInspectCmd(&o))
+	cmd.AddCommand(NewAttachCmd(&o))
 	cmd.AddCommand(NewBuildCmd(&o))
+	cmd.AddCommand(NewConfigCmd(&o))
+	cmd.AddCommand(NewCopyCmd(&o))
+	cmd.AddCommand(NewExportCmd(&o))
+	cmd.AddCommand(NewImportCmd(&o))
 	cmd.AddCommand(NewPushCmd(&o))
 	cmd.AddCommand(NewPullCmd(&o))
+	cmd.AddCommand(NewReferrersCmd(&o))
+	cmd.AddCommand(NewRehydrateCmd(&o))
+	cmd.AddCommand(NewRemoveCmd(&o))
+	cmd.AddCommand(NewRunCmd(&o))
+	cmd.AddCommand(NewSBOMCmd(&o))
 	cmd.AddCommand(NewServeCmd(&o))
+	cmd.AddCommand(NewTagCmd(&o))
 	cmd.AddCommand(NewVersionCmd(&o))
 
 	return cmd
@@ -0,0 +1,338 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	empspec "github.com/emporous/collection-spec/specs-go/v1alpha1"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/spf13/cobra"
+
+	"github.com/emporous/emporous-go/cmd/client/commands/options"
+	"github.com/emporous/emporous-go/content/file"
+	"github.com/emporous/emporous-go/content/layout"
+	"github.com/emporous/emporous-go/manager/defaultmanager"
+	"github.com/emporous/emporous-go/nodes/collection"
+	"github.com/emporous/emporous-go/nodes/descriptor"
+	"github.com/emporous/emporous-go/nodes/descriptor/v2"
+	"github.com/emporous/emporous-go/registryclient"
+	"github.com/emporous/emporous-go/registryclient/orasclient"
+	"github.com/emporous/emporous-go/util/examples"
+)
+
+// RunOptions describe configuration options that can
+// be set using the run subcommand.
+type RunOptions struct {
+	*options.Common
+	options.Remote
+	options.RemoteAuth
+	Source   string
+	Args     []string
+	Dir      string
+	NoVerify bool
+}
+
+// ExitError is returned when the collection
+// entrypoint exits with a non-zero exit code.
+type ExitError struct {
+	Code int
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("entrypoint exited with code %d", e.Code)
+}
+
+var clientRunExamples = []examples.Example{
+	{
+		RootCommand:   filepath.Base(os.Args[0]),
+		CommandString: "run localhost:5001/test:latest",
+		Descriptions: []string{
+			"Run a collection using the entrypoint and command from its runtime configuration.",
+		},
+	},
+	{
+		RootCommand:   filepath.Base(os.Args[0]),
+		CommandString: "run localhost:5001/test:latest -- --verbose",
+		Descriptions: []string{
+			"Run a collection and replace the command from its runtime configuration.",
+		},
+	},
+	{
+		RootCommand:   filepath.Base(os.Args[0]),
+		CommandString: "run localhost:5001/test:latest --dir my-bundle",
+		Descriptions: []string{
+			"Run a collection from a directory that is kept after the run.",
+		},
+	},
+}
+
+// NewRunCmd creates a new cobra.Command for the run subcommand.
+func NewRunCmd(common *options.Common) *cobra.Command {
+	o := RunOptions{Common: common}
+
+	cmd := &cobra.Command{
+		Use:           "run REF [-- ARG...]",
+		Short:         "Run a Emporous collection using its runtime configuration",
+		Example:       examples.FormatExamples(clientRunExamples...),
+		SilenceErrors: false,
+		SilenceUsage:  false,
+		Args:          cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			cobra.CheckErr(o.Complete(args))
+			cobra.CheckErr(o.Validate())
+			err := o.Run(cmd.Context())
+			var exitErr *ExitError
+			if errors.As(err, &exitErr) {
+				os.Exit(exitErr.Code)
+			}
+			cobra.CheckErr(err)
+		},
+	}
+
+	o.Remote.BindFlags(cmd.Flags())
+	o.RemoteAuth.BindFlags(cmd.Flags())
+
+	cmd.Flags().StringVar(&o.Dir, "dir", o.Dir, "Directory to pull the collection into. Defaults to a private temporary directory that is removed after the run")
+	cmd.Flags().BoolVar(&o.NoVerify, "no-verify", o.NoVerify, "Skip collection signature verification")
+
+	return cmd
+}
+
+func (o *RunOptions) Complete(args []string) error {
+	if len(args) < 1 {
+		return errors.New("bug: expecting at least one argument")
+	}
+	o.Source = args[0]
+	o.Args = args[1:]
+	return nil
+}
+
+func (o *RunOptions) Validate() error {
+	if o.Dir == "" {
+		return nil
+	}
+	if err := os.MkdirAll(o.Dir, 0700); err != nil {
+		return err
+	}
+	entries, err := ioutil.ReadDir(o.Dir)
+	if err != nil {
+		return err
+	}
+	if len(entries) != 0 {
+		return fmt.Errorf("run directory %q: must be empty", o.Dir)
+	}
+	return nil
+}
+
+func (o *RunOptions) Run(ctx context.Context) error {
+	dir := o.Dir
+	if dir == "" {
+		tmp, err := ioutil.TempDir("", "emporous-run-")
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if err := os.RemoveAll(tmp); err != nil {
+				o.Logger.Errorf(err.Error())
+			}
+		}()
+		dir = tmp
+	}
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+
+	cache, err := layout.NewWithContext(ctx, o.CacheDir)
+	if err != nil {
+		return err
+	}
+
+	var clientOpts = []orasclient.ClientOption{
+		orasclient.SkipTLSVerify(o.Insecure),
+		orasclient.WithAuthConfigs(o.Configs),
+		orasclient.WithPlainHTTP(o.PlainHTTP),
+		orasclient.WithCache(cache),
+	}
+
+	if !o.NoVerify {
+		verificationFn := func(ctx context.Context, reference string) error {
+			o.Logger.Debugf("Checking signature of %s", reference)
+			err = verifyCollection(ctx, reference, o.RemoteAuth.Configs, o.Remote)
+			if err != nil {
+				return fmt.Errorf("collection %q: %v", reference, err)
+			}
+			return nil
+		}
+		clientOpts = append(clientOpts, orasclient.WithPrePullFunc(verificationFn))
+	}
+
+	client, err := orasclient.NewClient(clientOpts...)
+	if err != nil {
+		return fmt.Errorf("error configuring client: %v", err)
+	}
+	defer func() {
+		if err := client.Destroy(); err != nil {
+			o.Logger.Errorf(err.Error())
+		}
+	}()
+
+	manager := defaultmanager.New(cache, o.Logger)
+	digests, err := manager.Pull(ctx, o.Source, client, file.New(dir))
+	if err != nil {
+		return err
+	}
+	if len(digests) == 0 {
+		return fmt.Errorf("collection %s: no content pulled", o.Source)
+	}
+	pulled := map[string]struct{}{}
+	for _, d := range digests {
+		pulled[d] = struct{}{}
+	}
+
+	graph, err := client.LoadCollection(ctx, o.Source)
+	if err != nil {
+		return err
+	}
+	runtime, err := runtimeConfig(ctx, o.Source, client, graph, pulled)
+	if err != nil {
+		return err
+	}
+
+	cmd, err := o.command(ctx, dir, runtime)
+	if err != nil {
+		return err
+	}
+	o.Logger.Debugf("Running %s in %s", strings.Join(cmd.Args, " "), cmd.Dir)
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return &ExitError{Code: exitErr.ExitCode()}
+		}
+		return err
+	}
+	return nil
+}
+
+// command creates the command for the runtime configuration. Absolute paths
+// in the entrypoint and working directory are resolved from the run directory.
+func (o *RunOptions) command(ctx context.Context, dir string, runtime *ocispec.ImageConfig) (*exec.Cmd, error) {
+	args := append([]string{}, runtime.Entrypoint...)
+	if len(o.Args) != 0 {
+		args = append(args, o.Args...)
+	} else {
+		args = append(args, runtime.Cmd...)
+	}
+	if len(args) == 0 {
+		return nil, fmt.Errorf("collection %s: no entrypoint or command set in the runtime configuration", o.Source)
+	}
+
+	workDir := dir
+	if runtime.WorkingDir != "" {
+		workDir = filepath.Join(dir, filepath.FromSlash(runtime.WorkingDir))
+		if err := os.MkdirAll(workDir, 0700); err != nil {
+			return nil, err
+		}
+	}
+
+	path, err := resolveExecutable(args[0], dir, workDir)
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := exec.CommandContext(ctx, path, args[1:]...)
+	cmd.Args[0] = args[0]
+	cmd.Dir = workDir
+	cmd.Env = append(os.Environ(), runtime.Env...)
+	cmd.Stdin = o.IOStreams.In
+	cmd.Stdout = o.IOStreams.Out
+	cmd.Stderr = o.IOStreams.ErrOut
+	return cmd, nil
+}
+
+// resolveExecutable finds the executable to run in the run directory. Absolute paths are
+// resolved from the run directory and relative paths from the working directory. Names
+// without a path separator are looked up in the working directory, the run directory and
+// then PATH.
+func resolveExecutable(name, dir, workDir string) (string, error) {
+	switch {
+	case filepath.IsAbs(name):
+		inBundle := filepath.Join(dir, name)
+		if _, err := os.Stat(inBundle); err != nil {
+			return "", fmt.Errorf("executable %q not found in the collection", name)
+		}
+		return inBundle, nil
+	case strings.ContainsRune(name, filepath.Separator) || strings.ContainsRune(name, '/'):
+		return filepath.Join(workDir, filepath.FromSlash(name)), nil
+	default:
+		for _, bundleDir := range []string{workDir, dir} {
+			inBundle := filepath.Join(bundleDir, name)
+			if info, err := os.Stat(inBundle); err == nil && !info.IsDir() {
+				return inBundle, nil
+			}
+		}
+		if path, err := exec.LookPath(name); err == nil {
+			return path, nil
+		}
+		return "", fmt.Errorf("executable %q not found in the collection or PATH", name)
+	}
+}
+
+// runtimeConfig returns the core-runtime configuration of the collection. The root
+// manifest is checked first followed by the pulled manifests, such as a selected variant.
+func runtimeConfig(ctx context.Context, reference string, client registryclient.Remote, graph collection.Collection, pulled map[string]struct{}) (*ocispec.ImageConfig, error) {
+	root, err := graph.Root()
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := []*v2.Node{root.(*v2.Node)}
+	var ids []string
+	for id := range pulled {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		node, ok := graph.NodeByID(id).(*v2.Node)
+		if !ok || id == root.ID() || len(graph.From(id)) == 0 {
+			continue
+		}
+		candidates = append(candidates, node)
+	}
+
+	for _, candidate := range candidates {
+		manifest, err := client.GetContent(ctx, reference, candidate.Descriptor())
+		if err != nil {
+			return nil, err
+		}
+		var m struct {
+			Annotations map[string]string `json:"annotations"`
+		}
+		if err := json.Unmarshal(manifest, &m); err != nil {
+			return nil, err
+		}
+		if _, ok := m.Annotations[empspec.AnnotationEmporousAttributes]; !ok {
+			continue
+		}
+		attrs, err := descriptor.AnnotationsToAttributes(m.Annotations)
+		if err != nil {
+			return nil, err
+		}
+		props, err := descriptor.Parse(attrs)
+		if err != nil {
+			return nil, err
+		}
+		if props.HasRuntimeInfo() {
+			return props.Runtime, nil
+		}
+	}
+	return nil, fmt.Errorf("collection %s: no runtime configuration found", reference)
+}
@@ -0,0 +1,251 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/stretchr/testify/require"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/emporous/emporous-go/cmd/client/commands/options"
+	"github.com/emporous/emporous-go/log"
+)
+
+func TestRunValidate(t *testing.T) {
+	type spec struct {
+		name     string
+		opts     *RunOptions
+		prepFunc func(t *testing.T, dir string)
+		expError string
+	}
+
+	cases := []spec{
+		{
+			name: "Valid/EmptyDir",
+			opts: &RunOptions{},
+		},
+		{
+			name: "Invalid/NonEmptyDir",
+			opts: &RunOptions{},
+			prepFunc: func(t *testing.T, dir string) {
+				require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "file"), []byte("test"), 0600))
+			},
+			expError: "must be empty",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			c.opts.Dir = t.TempDir()
+			if c.prepFunc != nil {
+				c.prepFunc(t, c.opts.Dir)
+			}
+			err := c.opts.Validate()
+			if c.expError != "" {
+				require.ErrorContains(t, err, c.expError)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestRunRun(t *testing.T) {
+	testlogr, err := log.NewLogrusLogger(ioutil.Discard, "debug")
+	require.NoError(t, err)
+
+	server := httptest.NewServer(registry.New())
+	t.Cleanup(server.Close)
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	cache := filepath.Join(t.TempDir(), "cache")
+	require.NoError(t, os.MkdirAll(cache, 0750))
+	reference := fmt.Sprintf("%s/client-run:latest", u.Host)
+
+	buildOpts := &BuildCollectionOptions{
+		BuildOptions: &BuildOptions{
+			Common: &options.Common{
+				Logger:   testlogr,
+				CacheDir: cache,
+			},
+			Destination: reference,
+		},
+		RootDir:  "./testdata/run-workspace",
+		DSConfig: "./testdata/configs/dataset-config-run.yaml",
+	}
+	require.NoError(t, buildOpts.Run(context.TODO()))
+	pushOpts := &PushOptions{
+		Common: &options.Common{
+			Logger:   testlogr,
+			CacheDir: cache,
+		},
+		Remote: options.Remote{
+			PlainHTTP: true,
+		},
+		Destination: reference,
+	}
+	require.NoError(t, pushOpts.Run(context.TODO()))
+
+	type spec struct {
+		name      string
+		source    string
+		args      []string
+		keepDir   bool
+		expOut    string
+		expCode   int
+		expError  string
+		assertDir func(t *testing.T, dir string)
+	}
+
+	cases := []spec{
+		{
+			name:    "Success/RuntimeCommand",
+			source:  reference,
+			expOut:  "hello world from data\n",
+			expCode: 3,
+		},
+		{
+			name:    "Success/ArgsReplaceCommand",
+			source:  reference,
+			args:    []string{"emporous"},
+			keepDir: true,
+			expOut:  "hello emporous from data\n",
+			expCode: 3,
+			assertDir: func(t *testing.T, dir string) {
+				info, err := os.Stat(filepath.Join(dir, "bin", "hello.sh"))
+				require.NoError(t, err)
+				require.Equal(t, os.FileMode(0755), info.Mode().Perm())
+			},
+		},
+		{
+			name:     "Failure/NotFound",
+			source:   fmt.Sprintf("%s/client-run:missing", u.Host),
+			expError: "not found",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			out := new(bytes.Buffer)
+			runCache := filepath.Join(t.TempDir(), "cache")
+			require.NoError(t, os.MkdirAll(runCache, 0750))
+			opts := &RunOptions{
+				Common: &options.Common{
+					IOStreams: genericclioptions.IOStreams{
+						Out:    out,
+						In:     os.Stdin,
+						ErrOut: os.Stderr,
+					},
+					Logger:   testlogr,
+					CacheDir: runCache,
+				},
+				Remote: options.Remote{
+					PlainHTTP: true,
+				},
+				NoVerify: true,
+			}
+			require.NoError(t, opts.Complete(append([]string{c.source}, c.args...)))
+			if c.keepDir {
+				opts.Dir = filepath.Join(t.TempDir(), "bundle")
+			}
+			require.NoError(t, opts.Validate())
+
+			err := opts.Run(context.TODO())
+			switch {
+			case c.expError != "":
+				require.ErrorContains(t, err, c.expError)
+				return
+			case c.expCode != 0:
+				var exitErr *ExitError
+				require.ErrorAs(t, err, &exitErr)
+				require.Equal(t, c.expCode, exitErr.Code)
+			default:
+				require.NoError(t, err)
+			}
+			require.Equal(t, c.expOut, out.String())
+			if c.assertDir != nil {
+				c.assertDir(t, opts.Dir)
+			}
+		})
+	}
+}
+
+func TestResolveExecutable(t *testing.T) {
+	dir := t.TempDir()
+	workDir := filepath.Join(dir, "work")
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "bin"), 0750))
+	require.NoError(t, os.MkdirAll(workDir, 0750))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "bin", "hello.sh"), []byte("#!/bin/sh\n"), 0700))
+	// The bundled executable shadows the executable in PATH.
+	require.NoError(t, os.WriteFile(filepath.Join(workDir, "sh"), []byte("#!/bin/sh\n"), 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "tool"), []byte("#!/bin/sh\n"), 0700))
+	lsPath, err := exec.LookPath("ls")
+	require.NoError(t, err)
+
+	type spec struct {
+		name     string
+		exec     string
+		exp      string
+		expError string
+	}
+
+	cases := []spec{
+		{
+			name: "Success/AbsoluteInBundle",
+			exec: "/bin/hello.sh",
+			exp:  filepath.Join(dir, "bin", "hello.sh"),
+		},
+		{
+			name: "Success/Relative",
+			exec: "../bin/hello.sh",
+			exp:  filepath.Join(dir, "bin", "hello.sh"),
+		},
+		{
+			name: "Success/NameInWorkingDirectory",
+			exec: "sh",
+			exp:  filepath.Join(workDir, "sh"),
+		},
+		{
+			name: "Success/NameInBundle",
+			exec: "tool",
+			exp:  filepath.Join(dir, "tool"),
+		},
+		{
+			name: "Success/NameInPATH",
+			exec: "ls",
+			exp:  lsPath,
+		},
+		{
+			name:     "Failure/AbsoluteNotInBundle",
+			exec:     "/bin/ls",
+			expError: `executable "/bin/ls" not found in the collection`,
+		},
+		{
+			name:     "Failure/NameNotFound",
+			exec:     "emporous-missing-executable",
+			expError: `executable "emporous-missing-executable" not found in the collection or PATH`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			path, err := resolveExecutable(c.exec, dir, workDir)
+			if c.expError != "" {
+				require.EqualError(t, err, c.expError)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, c.exp, path)
+		})
+	}
+}
@@ -0,0 +1,240 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/spf13/cobra"
+	orascontent "oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/registry"
+
+	"github.com/emporous/emporous-go/cmd/client/commands/options"
+	"github.com/emporous/emporous-go/content"
+	"github.com/emporous/emporous-go/content/layout"
+	"github.com/emporous/emporous-go/nodes/collection"
+	"github.com/emporous/emporous-go/nodes/collection/loader"
+	"github.com/emporous/emporous-go/registryclient"
+	"github.com/emporous/emporous-go/registryclient/orasclient"
+	"github.com/emporous/emporous-go/sbom"
+	"github.com/emporous/emporous-go/util/examples"
+	"github.com/emporous/emporous-go/version"
+)
+
+// SBOMOptions describe configuration options that can
+// be set using the sbom subcommand.
+type SBOMOptions struct {
+	*options.Common
+	options.Remote
+	options.RemoteAuth
+	Source string
+	Format string
+	Output string
+}
+
+var clientSBOMExamples = []examples.Example{
+	{
+		RootCommand:   filepath.Base(os.Args[0]),
+		CommandString: "sbom localhost:5001/test:latest",
+		Descriptions: []string{
+			"Print an SPDX SBOM for a collection and its linked collections.",
+		},
+	},
+	{
+		RootCommand:   filepath.Base(os.Args[0]),
+		CommandString: "sbom localhost:5001/test:latest --format cyclonedx-json -o sbom.json",
+		Descriptions: []string{
+			"Write a CycloneDX SBOM for a collection to a file.",
+		},
+	},
+}
+
+// NewSBOMCmd creates a new cobra.Command for the sbom subcommand.
+func NewSBOMCmd(common *options.Common) *cobra.Command {
+	o := SBOMOptions{Common: common}
+
+	cmd := &cobra.Command{
+		Use:           "sbom REF",
+		Short:         "Generate a software bill of materials for a Emporous collection",
+		Example:       examples.FormatExamples(clientSBOMExamples...),
+		SilenceErrors: false,
+		SilenceUsage:  false,
+		Args:          cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			cobra.CheckErr(o.Complete(args))
+			cobra.CheckErr(o.Validate())
+			cobra.CheckErr(o.Run(cmd.Context()))
+		},
+	}
+
+	o.Remote.BindFlags(cmd.Flags())
+	o.RemoteAuth.BindFlags(cmd.Flags())
+
+	cmd.Flags().StringVar(&o.Format, "format", string(sbom.FormatSPDXJSON), "SBOM format (spdx-json, cyclonedx-json)")
+	cmd.Flags().StringVarP(&o.Output, "output", "o", o.Output, "Output file for the SBOM. Defaults to stdout")
+
+	return cmd
+}
+
+func (o *SBOMOptions) Complete(args []string) error {
+	if len(args) < 1 {
+		return errors.New("bug: expecting one argument")
+	}
+	o.Source = args[0]
+	if o.Format == "" {
+		o.Format = string(sbom.FormatSPDXJSON)
+	}
+	return nil
+}
+
+func (o *SBOMOptions) Validate() error {
+	_, err := sbom.ParseFormat(o.Format)
+	return err
+}
+
+func (o *SBOMOptions) Run(ctx context.Context) error {
+	format, err := sbom.ParseFormat(o.Format)
+	if err != nil {
+		return err
+	}
+
+	created := time.Now()
+	epoch, err := sourceDateEpoch()
+	if err != nil {
+		return err
+	}
+	if epoch != nil {
+		created = *epoch
+	}
+
+	cache, err := layout.NewWithContext(ctx, o.CacheDir)
+	if err != nil {
+		return err
+	}
+
+	client, err := orasclient.NewClient(
+		orasclient.SkipTLSVerify(o.Insecure),
+		orasclient.WithAuthConfigs(o.Configs),
+		orasclient.WithPlainHTTP(o.PlainHTTP),
+		orasclient.WithCache(cache),
+	)
+	if err != nil {
+		return fmt.Errorf("error configuring client: %v", err)
+	}
+	defer func() {
+		if err := client.Destroy(); err != nil {
+			o.Logger.Errorf(err.Error())
+		}
+	}()
+
+	inventory, err := sbom.NewInventory(ctx, o.Source, o.collectionLoader(cache, client))
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = o.IOStreams.Out
+	if o.Output != "" {
+		f, err := os.Create(o.Output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	encodeOpts := sbom.EncodeOptions{
+		Created:     created,
+		ToolVersion: version.GetVersion(),
+	}
+	if err := sbom.Encode(w, inventory, format, encodeOpts); err != nil {
+		return err
+	}
+
+	if o.Output != "" {
+		o.Logger.Infof("SBOM for %s written to %s", o.Source, o.Output)
+	}
+	return nil
+}
+
+// collectionLoader returns a function that loads collections from the
+// cache and falls back to the remote location if the collection is not cached.
+func (o *SBOMOptions) collectionLoader(cache *layout.Layout, client registryclient.Remote) sbom.LoaderFunc {
+	return func(ctx context.Context, reference string) (collection.Collection, error) {
+		desc, err := resolveCached(ctx, cache, reference)
+		var notStored *content.ErrNotStored
+		switch {
+		case err == nil:
+			o.Logger.Debugf("Loading collection %s from cache", reference)
+			fetcherFn := func(ctx context.Context, desc ocispec.Descriptor) ([]byte, error) {
+				return orascontent.FetchAll(ctx, cache, desc)
+			}
+			co := collection.New(reference)
+			if err := loader.LoadFromManifest(ctx, co, fetcherFn, desc); err != nil {
+				return collection.Collection{}, err
+			}
+			co.Location = reference
+			return *co, nil
+		case errors.As(err, &notStored):
+			o.Logger.Debugf("Loading collection %s from remote", reference)
+			return client.LoadCollection(ctx, reference)
+		default:
+			return collection.Collection{}, err
+		}
+	}
+}
+
+// resolveCached resolves the reference to a root manifest descriptor in the cache. References
+// by digest are resolved if the manifest is stored in the cache. The descriptor annotations are
+// set from the manifest.
+func resolveCached(ctx context.Context, cache *layout.Layout, reference string) (ocispec.Descriptor, error) {
+	desc, err := cache.Resolve(ctx, reference)
+	if err != nil {
+		ref, parseErr := registry.ParseReference(reference)
+		if parseErr != nil {
+			return ocispec.Descriptor{}, err
+		}
+		dgst, digestErr := digest.Parse(ref.Reference)
+		if digestErr != nil {
+			return ocispec.Descriptor{}, err
+		}
+		desc = ocispec.Descriptor{Digest: dgst}
+		exists, existsErr := cache.Exists(ctx, desc)
+		if existsErr != nil {
+			return ocispec.Descriptor{}, existsErr
+		}
+		if !exists {
+			return ocispec.Descriptor{}, err
+		}
+	}
+
+	rc, err := cache.Fetch(ctx, desc)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	defer rc.Close()
+	manifestBytes, err := ioutil.ReadAll(rc)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	var manifest struct {
+		MediaType   string            `json:"mediaType"`
+		Annotations map[string]string `json:"annotations"`
+	}
+	if err := json.Unmarshal(manifestBytes, &manifest); err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	if desc.MediaType == "" {
+		desc.MediaType = manifest.MediaType
+		desc.Size = int64(len(manifestBytes))
+	}
+	desc.Annotations = manifest.Annotations
+	return desc, nil
+}
@@ -0,0 +1,221 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"text/template"
+
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/stretchr/testify/require"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/emporous/emporous-go/cmd/client/commands/options"
+	"github.com/emporous/emporous-go/log"
+)
+
+func TestSBOMValidate(t *testing.T) {
+	type spec struct {
+		name     string
+		opts     *SBOMOptions
+		expError string
+	}
+
+	cases := []spec{
+		{
+			name: "Valid/SPDX",
+			opts: &SBOMOptions{Format: "spdx-json"},
+		},
+		{
+			name: "Valid/CycloneDX",
+			opts: &SBOMOptions{Format: "cyclonedx-json"},
+		},
+		{
+			name:     "Invalid/UnsupportedFormat",
+			opts:     &SBOMOptions{Format: "spdx-tag-value"},
+			expError: "unsupported SBOM format \"spdx-tag-value\": must be one of spdx-json, cyclonedx-json",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := c.opts.Validate()
+			if c.expError != "" {
+				require.EqualError(t, err, c.expError)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestSBOMRun(t *testing.T) {
+	testlogr, err := log.NewLogrusLogger(ioutil.Discard, "debug")
+	require.NoError(t, err)
+
+	server := httptest.NewServer(registry.New())
+	t.Cleanup(server.Close)
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	// Build a collection into the cache that links
+	// to a collection only stored in the registry.
+	cache := filepath.Join(t.TempDir(), "cache")
+	require.NoError(t, os.MkdirAll(cache, 0750))
+	reference := fmt.Sprintf("%s/client-sbom:latest", u.Host)
+
+	templateValues := prepCollectionArtifacts(t, u.Host)
+	initialConfig, err := ioutil.ReadFile("./testdata/configs/dataset-config-sbom.yaml")
+	require.NoError(t, err)
+	tpl, err := template.New("sbom").Parse(string(initialConfig))
+	require.NoError(t, err)
+	configPath := filepath.Join(t.TempDir(), "test.yaml")
+	configFile, err := os.Create(configPath)
+	require.NoError(t, err)
+	require.NoError(t, tpl.Execute(configFile, templateValues))
+	require.NoError(t, configFile.Close())
+
+	buildOpts := &BuildCollectionOptions{
+		BuildOptions: &BuildOptions{
+			Common: &options.Common{
+				Logger:   testlogr,
+				CacheDir: cache,
+			},
+			Destination: reference,
+		},
+		RootDir:  "./testdata/multi-level-workspace",
+		DSConfig: configPath,
+		Remote: options.Remote{
+			PlainHTTP: true,
+		},
+	}
+	require.NoError(t, buildOpts.Run(context.TODO()))
+
+	type spec struct {
+		name       string
+		format     string
+		source     string
+		assertFunc func(t *testing.T, out []byte)
+		expError   string
+	}
+
+	cases := []spec{
+		{
+			name:   "Success/SPDX",
+			format: "spdx-json",
+			source: reference,
+			assertFunc: func(t *testing.T, out []byte) {
+				var doc struct {
+					SPDXVersion string `json:"spdxVersion"`
+					Packages    []struct {
+						Name            string `json:"name"`
+						LicenseDeclared string `json:"licenseDeclared"`
+					} `json:"packages"`
+					Files []struct {
+						FileName string `json:"fileName"`
+					} `json:"files"`
+					Relationships []struct {
+						RelationshipType string `json:"relationshipType"`
+					} `json:"relationships"`
+				}
+				require.NoError(t, json.Unmarshal(out, &doc))
+				require.Equal(t, "SPDX-2.3", doc.SPDXVersion)
+				require.Len(t, doc.Packages, 2)
+				require.Equal(t, "test-collection", doc.Packages[0].Name)
+				require.Equal(t, "Apache-2.0", doc.Packages[0].LicenseDeclared)
+				var files []string
+				for _, f := range doc.Files {
+					files = append(files, f.FileName)
+				}
+				require.Equal(t, []string{
+					"./images/fish.jpg",
+					"./info.json",
+					"./supplementary/about.json",
+					"./test.json",
+					"./hello.txt",
+				}, files)
+				var dependsOn int
+				for _, r := range doc.Relationships {
+					if r.RelationshipType == "DEPENDS_ON" {
+						dependsOn++
+					}
+				}
+				require.Equal(t, 1, dependsOn)
+			},
+		},
+		{
+			name:   "Success/CycloneDX",
+			format: "cyclonedx-json",
+			source: reference,
+			assertFunc: func(t *testing.T, out []byte) {
+				var doc struct {
+					BOMFormat string `json:"bomFormat"`
+					Metadata  struct {
+						Component struct {
+							Name       string `json:"name"`
+							PURL       string `json:"purl"`
+							Components []struct {
+								Name string `json:"name"`
+							} `json:"components"`
+						} `json:"component"`
+					} `json:"metadata"`
+					Components []struct {
+						Name string `json:"name"`
+					} `json:"components"`
+					Dependencies []struct {
+						DependsOn []string `json:"dependsOn"`
+					} `json:"dependencies"`
+				}
+				require.NoError(t, json.Unmarshal(out, &doc))
+				require.Equal(t, "CycloneDX", doc.BOMFormat)
+				require.Equal(t, "test-collection", doc.Metadata.Component.Name)
+				require.Equal(t, "pkg:oci/test-collection@v0.1.0", doc.Metadata.Component.PURL)
+				require.Len(t, doc.Metadata.Component.Components, 4)
+				require.Len(t, doc.Components, 1)
+				require.Len(t, doc.Dependencies[0].DependsOn, 1)
+			},
+		},
+		{
+			name:     "Failure/NotFound",
+			format:   "spdx-json",
+			source:   fmt.Sprintf("%s/client-sbom:missing", u.Host),
+			expError: fmt.Sprintf("collection %s/client-sbom:missing: %s/client-sbom:missing: not found", u.Host, u.Host),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			out := new(bytes.Buffer)
+			opts := &SBOMOptions{
+				Common: &options.Common{
+					IOStreams: genericclioptions.IOStreams{
+						Out:    out,
+						In:     os.Stdin,
+						ErrOut: os.Stderr,
+					},
+					Logger:   testlogr,
+					CacheDir: cache,
+				},
+				Remote: options.Remote{
+					PlainHTTP: true,
+				},
+				Source: c.source,
+				Format: c.format,
+			}
+			err := opts.Run(context.TODO())
+			if c.expError != "" {
+				require.EqualError(t, err, c.expError)
+				return
+			}
+			require.NoError(t, err)
+			c.assertFunc(t, out.Bytes())
+		})
+	}
+}
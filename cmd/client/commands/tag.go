@@ -0,0 +1,78 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/emporous/emporous-go/cmd/client/commands/options"
+	"github.com/emporous/emporous-go/content/layout"
+	"github.com/emporous/emporous-go/util/examples"
+)
+
+// TagOptions describe configuration options that can
+// be set using the tag subcommand.
+type TagOptions struct {
+	*options.Common
+	Source      string
+	Destination string
+}
+
+var clientTagExamples = examples.Example{
+	RootCommand:   filepath.Base(os.Args[0]),
+	Descriptions:  []string{"Tag a cached collection with a new reference."},
+	CommandString: "tag localhost:5001/test:latest localhost:5001/test:v1",
+}
+
+// NewTagCmd creates a new cobra.Command for the tag subcommand.
+func NewTagCmd(common *options.Common) *cobra.Command {
+	o := TagOptions{Common: common}
+
+	cmd := &cobra.Command{
+		Use:           "tag SRC DST",
+		Short:         "Tag a Emporous collection in the cache with a new reference",
+		Example:       examples.FormatExamples(clientTagExamples),
+		SilenceErrors: false,
+		SilenceUsage:  false,
+		Args:          cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			cobra.CheckErr(o.Complete(args))
+			cobra.CheckErr(o.Validate())
+			cobra.CheckErr(o.Run(cmd.Context()))
+		},
+	}
+
+	return cmd
+}
+
+func (o *TagOptions) Complete(args []string) error {
+	if len(args) < 2 {
+		return errors.New("bug: expecting two arguments")
+	}
+	o.Source = args[0]
+	o.Destination = args[1]
+	return nil
+}
+
+func (o *TagOptions) Validate() error {
+	return nil
+}
+
+func (o *TagOptions) Run(ctx context.Context) error {
+	cache, err := layout.NewWithContext(ctx, o.CacheDir)
+	if err != nil {
+		return err
+	}
+	desc, err := cache.Resolve(ctx, o.Source)
+	if err != nil {
+		return err
+	}
+	if err := cache.Tag(ctx, desc, o.Destination); err != nil {
+		return err
+	}
+	o.Logger.Infof("Artifact %s tagged as %s", desc.Digest, o.Destination)
+	return nil
+}
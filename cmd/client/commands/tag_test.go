@@ -0,0 +1,123 @@
+package commands
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/emporous/emporous-go/cmd/client/commands/options"
+	"github.com/emporous/emporous-go/content"
+	"github.com/emporous/emporous-go/content/layout"
+	"github.com/emporous/emporous-go/log"
+)
+
+func TestTagAndRemoveRun(t *testing.T) {
+	testlogr, err := log.NewLogrusLogger(ioutil.Discard, "debug")
+	require.NoError(t, err)
+
+	ctx := context.TODO()
+	cache := filepath.Join(t.TempDir(), "cache")
+	require.NoError(t, os.MkdirAll(cache, 0750))
+	source := "localhost:5001/client-tag:latest"
+	destination := "localhost:5001/client-tag:v1"
+	require.NoError(t, prepCache(source, cache, nil))
+
+	common := &options.Common{
+		Logger:   testlogr,
+		CacheDir: cache,
+	}
+
+	type spec struct {
+		name       string
+		run        func() error
+		expError   string
+		assertFunc func(t *testing.T, l *layout.Layout)
+	}
+
+	cases := []spec{
+		{
+			name: "Success/Tag",
+			run: func() error {
+				o := &TagOptions{Common: common}
+				require.NoError(t, o.Complete([]string{source, destination}))
+				return o.Run(ctx)
+			},
+			assertFunc: func(t *testing.T, l *layout.Layout) {
+				src, err := l.Resolve(ctx, source)
+				require.NoError(t, err)
+				dst, err := l.Resolve(ctx, destination)
+				require.NoError(t, err)
+				require.Equal(t, src.Digest, dst.Digest)
+			},
+		},
+		{
+			name: "Failure/TagNotStored",
+			run: func() error {
+				o := &TagOptions{Common: common}
+				require.NoError(t, o.Complete([]string{"localhost:5001/client-tag:missing", destination}))
+				return o.Run(ctx)
+			},
+			expError: "descriptor for reference localhost:5001/client-tag:missing is not stored",
+		},
+		{
+			name: "Success/RemoveSharedContentKept",
+			run: func() error {
+				o := &RemoveOptions{Common: common}
+				require.NoError(t, o.Complete([]string{source}))
+				return o.Run(ctx)
+			},
+			assertFunc: func(t *testing.T, l *layout.Layout) {
+				var notStored *content.ErrNotStored
+				_, err := l.Resolve(ctx, source)
+				require.ErrorAs(t, err, &notStored)
+				descs, err := l.ResolveAll(ctx, destination)
+				require.NoError(t, err)
+				require.Len(t, descs, 3)
+			},
+		},
+		{
+			name: "Success/RemoveLastReference",
+			run: func() error {
+				o := &RemoveOptions{Common: common}
+				require.NoError(t, o.Complete([]string{destination}))
+				return o.Run(ctx)
+			},
+			assertFunc: func(t *testing.T, l *layout.Layout) {
+				index, err := l.Index()
+				require.NoError(t, err)
+				require.Empty(t, index.Manifests)
+				blobs, err := filepath.Glob(filepath.Join(cache, "blobs", "sha256", "*"))
+				require.NoError(t, err)
+				require.Empty(t, blobs)
+			},
+		},
+		{
+			name: "Failure/RemoveNotStored",
+			run: func() error {
+				o := &RemoveOptions{Common: common}
+				require.NoError(t, o.Complete([]string{destination}))
+				return o.Run(ctx)
+			},
+			expError: "descriptor for reference localhost:5001/client-tag:v1 is not stored",
+		},
+	}
+
+	// The cases run in order against the same cache.
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := c.run()
+			if c.expError != "" {
+				require.EqualError(t, err, c.expError)
+				return
+			}
+			require.NoError(t, err)
+			l, err := layout.NewWithContext(ctx, cache)
+			require.NoError(t, err)
+			c.assertFunc(t, l)
+		})
+	}
+}
@@ -0,0 +1,3 @@
+#!/bin/sh
+echo "$GREETING $1 from $(basename "$PWD")"
+exit 3
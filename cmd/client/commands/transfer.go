@@ -0,0 +1,48 @@
+package commands
+
+import (
+	"path/filepath"
+
+	"github.com/emporous/emporous-go/cmd/client/commands/options"
+	"github.com/emporous/emporous-go/registryclient/orasclient"
+)
+
+// transferOptions returns the client options that configure
+// transfer concurrency and bandwidth limits.
+func transferOptions(opts options.Transfer) ([]orasclient.ClientOption, error) {
+	var clientOpts []orasclient.ClientOption
+	if opts.Concurrency > 0 {
+		clientOpts = append(clientOpts, orasclient.WithConcurrency(opts.Concurrency))
+	}
+
+	rateLimit, err := opts.RateLimit()
+	if err != nil {
+		return nil, err
+	}
+	if rateLimit > 0 {
+		clientOpts = append(clientOpts, orasclient.WithRateLimit(rateLimit))
+	}
+
+	concurrencies, err := opts.RegistryConcurrencies()
+	if err != nil {
+		return nil, err
+	}
+	for registry, n := range concurrencies {
+		clientOpts = append(clientOpts, orasclient.WithRegistryConcurrency(registry, n))
+	}
+
+	rateLimits, err := opts.RegistryRateLimits()
+	if err != nil {
+		return nil, err
+	}
+	for registry, bytesPerSecond := range rateLimits {
+		clientOpts = append(clientOpts, orasclient.WithRegistryRateLimit(registry, bytesPerSecond))
+	}
+	return clientOpts, nil
+}
+
+// ingestDir returns the directory in the cache where partially
+// transferred blobs are persisted.
+func ingestDir(common *options.Common) string {
+	return filepath.Join(common.CacheDir, "ingest")
+}
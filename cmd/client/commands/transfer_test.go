@@ -0,0 +1,86 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/emporous/emporous-go/cmd/client/commands/options"
+)
+
+func TestTransferValidate(t *testing.T) {
+	type spec struct {
+		name                  string
+		opts                  options.Transfer
+		expRateLimit          int64
+		expRegistryRateLimits map[string]int64
+		expConcurrencies      map[string]int64
+		expError              string
+	}
+
+	cases := []spec{
+		{
+			name: "Success/NoLimits",
+		},
+		{
+			name: "Success/Limits",
+			opts: options.Transfer{
+				Concurrency:         4,
+				LimitRate:           "10M",
+				RegistryConcurrency: []string{"localhost:5000=8"},
+				RegistryLimitRate:   []string{"localhost:5000=512K", "localhost:5001=1.5k", "localhost:5002=100"},
+			},
+			expRateLimit: 10 << 20,
+			expRegistryRateLimits: map[string]int64{
+				"localhost:5000": 512 << 10,
+				"localhost:5001": 1536,
+				"localhost:5002": 100,
+			},
+			expConcurrencies: map[string]int64{"localhost:5000": 8},
+		},
+		{
+			name:     "Failure/InvalidConcurrency",
+			opts:     options.Transfer{Concurrency: -1},
+			expError: "concurrency must be at least 1, got -1",
+		},
+		{
+			name:     "Failure/InvalidRate",
+			opts:     options.Transfer{LimitRate: "fast"},
+			expError: "limit rate: invalid rate \"fast\": must be a positive number of bytes with an optional K, M or G suffix",
+		},
+		{
+			name:     "Failure/InvalidRegistryRate",
+			opts:     options.Transfer{RegistryLimitRate: []string{"localhost:5000"}},
+			expError: "registry rate limit \"localhost:5000\": must be in the form HOST=VALUE",
+		},
+		{
+			name:     "Failure/InvalidRegistryConcurrency",
+			opts:     options.Transfer{RegistryConcurrency: []string{"localhost:5000=0"}},
+			expError: "registry concurrency for localhost:5000: \"0\" is not a positive integer",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := c.opts.Validate()
+			if c.expError != "" {
+				require.EqualError(t, err, c.expError)
+				return
+			}
+			require.NoError(t, err)
+
+			rateLimit, err := c.opts.RateLimit()
+			require.NoError(t, err)
+			require.Equal(t, c.expRateLimit, rateLimit)
+			rateLimits, err := c.opts.RegistryRateLimits()
+			require.NoError(t, err)
+			require.Equal(t, c.expRegistryRateLimits, rateLimits)
+			concurrencies, err := c.opts.RegistryConcurrencies()
+			require.NoError(t, err)
+			require.Equal(t, c.expConcurrencies, concurrencies)
+
+			_, err = transferOptions(c.opts)
+			require.NoError(t, err)
+		})
+	}
+}
@@ -13,13 +13,21 @@ import (
 )
 
 // ReadDataSetConfig reads the specified config into a DataSetConfiguration type.
+// A relative SBOM path is resolved against the directory of the config.
 func ReadDataSetConfig(configPath string) (v1alpha1.DataSetConfiguration, error) {
 	data, err := ioutil.ReadFile(filepath.Clean(configPath))
 	if err != nil {
 		return v1alpha1.DataSetConfiguration{}, err
 	}
 
-	return LoadDataSetConfig(data)
+	configuration, err := LoadDataSetConfig(data)
+	if err != nil {
+		return configuration, err
+	}
+	if sbomPath := configuration.Collection.SBOM; sbomPath != "" && !filepath.IsAbs(sbomPath) {
+		configuration.Collection.SBOM = filepath.Join(filepath.Dir(configPath), sbomPath)
+	}
+	return configuration, nil
 }
 
 // LoadDataSetConfig loads a DataSetConfigurationType from input.
@@ -68,6 +76,52 @@ func LoadSchemaConfig(data []byte) (configuration v1alpha1.SchemaConfiguration,
 	return configuration, err
 }
 
+// LoadDirectoryAttributes loads a DirectoryAttributes type from input.
+func LoadDirectoryAttributes(data []byte) (configuration v1alpha1.DirectoryAttributes, err error) {
+	if data, err = yaml.YAMLToJSON(data); err != nil {
+		return configuration, err
+	}
+
+	if err = checkMeta(data, v1alpha1.DirectoryAttributesKind); err != nil {
+		return configuration, err
+	}
+
+	dec := json.NewDecoder(bytes.NewBuffer(data))
+	dec.DisallowUnknownFields()
+	if err = dec.Decode(&configuration); err != nil {
+		return configuration, err
+	}
+	return configuration, err
+}
+
+// ReadLockFile reads the specified lock file into a LockFile type.
+func ReadLockFile(lockPath string) (v1alpha1.LockFile, error) {
+	data, err := ioutil.ReadFile(filepath.Clean(lockPath))
+	if err != nil {
+		return v1alpha1.LockFile{}, err
+	}
+
+	return LoadLockFile(data)
+}
+
+// LoadLockFile loads a LockFile type from input.
+func LoadLockFile(data []byte) (configuration v1alpha1.LockFile, err error) {
+	if data, err = yaml.YAMLToJSON(data); err != nil {
+		return configuration, err
+	}
+
+	if err = checkMeta(data, v1alpha1.LockFileKind); err != nil {
+		return configuration, err
+	}
+
+	dec := json.NewDecoder(bytes.NewBuffer(data))
+	dec.DisallowUnknownFields()
+	if err = dec.Decode(&configuration); err != nil {
+		return configuration, err
+	}
+	return configuration, err
+}
+
 // ReadAttributeQuery reads the specified config into a AttributeQuery type.
 func ReadAttributeQuery(configPath string) (v1alpha1.AttributeQuery, error) {
 	data, err := ioutil.ReadFile(filepath.Clean(configPath))
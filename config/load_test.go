@@ -84,6 +84,19 @@ func TestReadDataSetConfig(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "Success/RelativeSBOM",
+			path: "testdata/valid-ds-sbom.yaml",
+			exp: v1alpha1.DataSetConfiguration{
+				TypeMeta: v1alpha1.TypeMeta{
+					Kind:       v1alpha1.DataSetConfigurationKind,
+					APIVersion: v1alpha1.GroupVersion,
+				},
+				Collection: v1alpha1.DataSetConfigurationSpec{
+					SBOM: "testdata/sbom/spdx.json",
+				},
+			},
+		},
 		{
 			name:     "Failure/InvalidConfig",
 			path:     "testdata/valid-attr.yaml",
@@ -104,6 +117,29 @@ func TestReadDataSetConfig(t *testing.T) {
 	}
 }
 
+func TestReadDataSetConfigFileInfo(t *testing.T) {
+	cfg, err := ReadDataSetConfig("testdata/valid-ds-fileinfo.yaml")
+	require.NoError(t, err)
+	require.Len(t, cfg.Collection.Files, 3)
+
+	attributesOnly := cfg.Collection.Files[0]
+	require.False(t, attributesOnly.HasFileInfo())
+	require.Equal(t, empspec.File{UID: -1, GID: -1}, attributesOnly.FileInfo)
+
+	permissions := cfg.Collection.Files[1]
+	require.True(t, permissions.HasFileInfo())
+	require.Equal(t, empspec.File{Permissions: 384, UID: -1, GID: -1}, permissions.FileInfo)
+
+	// Ownership set to root is distinguished from unset file information.
+	rootOwner := cfg.Collection.Files[2]
+	require.True(t, rootOwner.HasFileInfo())
+	require.Equal(t, empspec.File{}, rootOwner.FileInfo)
+
+	// Zero value file information in a literal is not set.
+	require.False(t, v1alpha1.File{File: "*.txt"}.HasFileInfo())
+	require.True(t, v1alpha1.File{File: "*.txt", FileInfo: empspec.File{UID: 1000, GID: -1}}.HasFileInfo())
+}
+
 func TestReadSchemaConfiguration(t *testing.T) {
 	type spec struct {
 		name     string
@@ -147,3 +183,51 @@ func TestReadSchemaConfiguration(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadDirectoryAttributes(t *testing.T) {
+	type spec struct {
+		name     string
+		data     string
+		exp      v1alpha1.DirectoryAttributes
+		expError string
+	}
+
+	cases := []spec{
+		{
+			name: "Success/ValidConfig",
+			data: "kind: DirectoryAttributes\napiVersion: client.emporous.io/v1alpha1\nattributes:\n  animal: fish\n  size: 2\n",
+			exp: v1alpha1.DirectoryAttributes{
+				TypeMeta: v1alpha1.TypeMeta{
+					Kind:       v1alpha1.DirectoryAttributesKind,
+					APIVersion: v1alpha1.GroupVersion,
+				},
+				Attributes: v1alpha1.Attributes{
+					"animal": "fish",
+					"size":   2.0,
+				},
+			},
+		},
+		{
+			name:     "Failure/UnknownField",
+			data:     "kind: DirectoryAttributes\napiVersion: client.emporous.io/v1alpha1\nfiles: []\n",
+			expError: "json: unknown field \"files\"",
+		},
+		{
+			name:     "Failure/InvalidKind",
+			data:     "kind: DataSetConfiguration\napiVersion: client.emporous.io/v1alpha1\n",
+			expError: "config kind DataSetConfiguration, does not match expected DirectoryAttributes",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			cfg, err := LoadDirectoryAttributes([]byte(c.data))
+			if c.expError != "" {
+				require.EqualError(t, err, c.expError)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, c.exp, cfg)
+			}
+		})
+	}
+}
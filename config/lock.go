@@ -0,0 +1,77 @@
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/emporous/emporous-go/api/client/v1alpha1"
+)
+
+// lockFileSuffix replaces the extension of a dataset
+// configuration path to form the default lock file path.
+const lockFileSuffix = ".lock.yaml"
+
+// LockFilePath returns the default lock file path for
+// the dataset configuration at configPath.
+func LockFilePath(configPath string) string {
+	return strings.TrimSuffix(configPath, filepath.Ext(configPath)) + lockFileSuffix
+}
+
+// WriteLockFile writes the lock file to the specified path.
+func WriteLockFile(lockPath string, lock v1alpha1.LockFile) error {
+	lock.Kind = v1alpha1.LockFileKind
+	lock.APIVersion = v1alpha1.GroupVersion
+	data, err := yaml.Marshal(lock)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Clean(lockPath), data, 0600)
+}
+
+// DiffLockFile returns the differences between the references pinned in the locked
+// lock file and the references resolved from the dataset configuration.
+func DiffLockFile(locked, resolved v1alpha1.LockFile) []string {
+	var diffs []string
+	switch {
+	case resolved.Schema != nil && locked.Schema == nil:
+		diffs = append(diffs, fmt.Sprintf("schema %s: not in lock file", resolved.Schema.Reference))
+	case resolved.Schema == nil && locked.Schema != nil:
+		diffs = append(diffs, fmt.Sprintf("schema %s: not in dataset configuration", locked.Schema.Reference))
+	case resolved.Schema != nil:
+		diffs = append(diffs, diffReference("schema", *locked.Schema, *resolved.Schema)...)
+	}
+
+	lockedLinks := map[string]v1alpha1.LockedReference{}
+	for _, link := range locked.Links {
+		lockedLinks[link.Reference] = link
+	}
+	for _, link := range resolved.Links {
+		lockedLink, ok := lockedLinks[link.Reference]
+		if !ok {
+			diffs = append(diffs, fmt.Sprintf("link %s: not in lock file", link.Reference))
+			continue
+		}
+		delete(lockedLinks, link.Reference)
+		diffs = append(diffs, diffReference("link", lockedLink, link)...)
+	}
+	for _, link := range locked.Links {
+		if _, ok := lockedLinks[link.Reference]; ok {
+			diffs = append(diffs, fmt.Sprintf("link %s: not in dataset configuration", link.Reference))
+		}
+	}
+	return diffs
+}
+
+func diffReference(kind string, locked, resolved v1alpha1.LockedReference) []string {
+	if locked.Reference != resolved.Reference {
+		return []string{fmt.Sprintf("%s %s: locked to %s", kind, resolved.Reference, locked.Reference)}
+	}
+	if locked.Digest != resolved.Digest {
+		return []string{fmt.Sprintf("%s %s: resolved to %s, locked to %s", kind, resolved.Reference, resolved.Digest, locked.Digest)}
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/emporous/emporous-go/api/client/v1alpha1"
+)
+
+func TestLockFilePath(t *testing.T) {
+	require.Equal(t, "configs/dataset-config.lock.yaml", LockFilePath("configs/dataset-config.yaml"))
+	require.Equal(t, "dataset-config.lock.yaml", LockFilePath("dataset-config"))
+}
+
+func TestWriteLockFile(t *testing.T) {
+	lock := v1alpha1.LockFile{
+		Schema: &v1alpha1.LockedReference{
+			Reference: "localhost:5000/schema:latest",
+			Digest:    "sha256:2e30f6131ce2164ed5ef017845130727291417d60a1be6fad669bdc4473289cd",
+		},
+		Links: []v1alpha1.LockedReference{
+			{
+				Reference: "localhost:5000/link:latest",
+				Digest:    "sha256:5c29ebcf4a3e7ac6dca6dcea98b4fa98de57c4aca65fa0b49989fbeab1dfdf84",
+			},
+		},
+	}
+	lockPath := filepath.Join(t.TempDir(), "dataset-config.lock.yaml")
+	require.NoError(t, WriteLockFile(lockPath, lock))
+
+	actual, err := ReadLockFile(lockPath)
+	require.NoError(t, err)
+	lock.Kind = v1alpha1.LockFileKind
+	lock.APIVersion = v1alpha1.GroupVersion
+	require.Equal(t, lock, actual)
+}
+
+func TestDiffLockFile(t *testing.T) {
+	schema := v1alpha1.LockedReference{Reference: "localhost:5000/schema:latest", Digest: "sha256:aaaa"}
+	link := v1alpha1.LockedReference{Reference: "localhost:5000/link:latest", Digest: "sha256:bbbb"}
+
+	type spec struct {
+		name     string
+		locked   v1alpha1.LockFile
+		resolved v1alpha1.LockFile
+		exp      []string
+	}
+
+	cases := []spec{
+		{
+			name:     "Success/Matching",
+			locked:   v1alpha1.LockFile{Schema: &schema, Links: []v1alpha1.LockedReference{link}},
+			resolved: v1alpha1.LockFile{Schema: &schema, Links: []v1alpha1.LockedReference{link}},
+		},
+		{
+			name:   "Failure/DigestChanged",
+			locked: v1alpha1.LockFile{Schema: &schema, Links: []v1alpha1.LockedReference{link}},
+			resolved: v1alpha1.LockFile{
+				Schema: &v1alpha1.LockedReference{Reference: schema.Reference, Digest: "sha256:cccc"},
+				Links:  []v1alpha1.LockedReference{{Reference: link.Reference, Digest: "sha256:dddd"}},
+			},
+			exp: []string{
+				"schema localhost:5000/schema:latest: resolved to sha256:cccc, locked to sha256:aaaa",
+				"link localhost:5000/link:latest: resolved to sha256:dddd, locked to sha256:bbbb",
+			},
+		},
+		{
+			name:     "Failure/NotLocked",
+			resolved: v1alpha1.LockFile{Schema: &schema, Links: []v1alpha1.LockedReference{link}},
+			exp: []string{
+				"schema localhost:5000/schema:latest: not in lock file",
+				"link localhost:5000/link:latest: not in lock file",
+			},
+		},
+		{
+			name:   "Failure/NotConfigured",
+			locked: v1alpha1.LockFile{Schema: &schema, Links: []v1alpha1.LockedReference{link}},
+			exp: []string{
+				"schema localhost:5000/schema:latest: not in dataset configuration",
+				"link localhost:5000/link:latest: not in dataset configuration",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			require.Equal(t, c.exp, DiffLockFile(c.locked, c.resolved))
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// RegexPatternPrefix marks a file pattern as a regular expression.
+const RegexPatternPrefix = "regex:"
+
+// Pattern matches slash separated file paths relative to a workspace.
+//
+// Patterns are globs by default. A "*" matches any sequence of characters within
+// a path segment, "?" matches a single character within a path segment, and "[...]"
+// matches a character class. A "**" path segment matches zero or more directories.
+// Globs without a "/" are matched against the file name at any depth and
+// globs with a leading "/" are matched from the workspace root.
+//
+// Patterns prefixed with "regex:" are regular expressions matched against
+// the full path.
+type Pattern struct {
+	pattern    string
+	expression *regexp.Regexp
+	baseName   bool
+}
+
+// CompilePattern parses a file pattern from the dataset configuration.
+func CompilePattern(pattern string) (*Pattern, error) {
+	if pattern == "" {
+		return nil, errors.New("pattern must be set")
+	}
+
+	if strings.HasPrefix(pattern, RegexPatternPrefix) {
+		expression, err := regexp.Compile("^(?:" + strings.TrimPrefix(pattern, RegexPatternPrefix) + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("pattern %q: %w", pattern, err)
+		}
+		return &Pattern{pattern: pattern, expression: expression}, nil
+	}
+
+	glob := strings.TrimPrefix(pattern, "./")
+	baseName := !strings.Contains(glob, "/")
+	glob = strings.TrimPrefix(glob, "/")
+	expression, err := globToRegexp(glob)
+	if err != nil {
+		return nil, fmt.Errorf("pattern %q: %w", pattern, err)
+	}
+	compiled, err := regexp.Compile(expression)
+	if err != nil {
+		return nil, fmt.Errorf("pattern %q: %w", pattern, err)
+	}
+	return &Pattern{pattern: pattern, expression: compiled, baseName: baseName}, nil
+}
+
+// Match returns whether the path matches the pattern.
+func (p *Pattern) Match(location string) bool {
+	location = strings.TrimPrefix(filepath.ToSlash(location), "./")
+	if p.baseName {
+		location = path.Base(location)
+	}
+	return p.expression.MatchString(location)
+}
+
+// String returns the pattern as written
+// in the dataset configuration.
+func (p *Pattern) String() string {
+	return p.pattern
+}
+
+// globToRegexp converts a glob into an anchored regular expression.
+func globToRegexp(glob string) (string, error) {
+	var b strings.Builder
+	b.WriteString("^")
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch c {
+		case '*':
+			if i+1 >= len(glob) || glob[i+1] != '*' {
+				b.WriteString("[^/]*")
+				continue
+			}
+			// A double star must be a complete path segment.
+			if (i > 0 && glob[i-1] != '/') || (i+2 < len(glob) && glob[i+2] != '/') {
+				return "", errors.New("** must be a complete path segment")
+			}
+			if i+2 == len(glob) {
+				b.WriteString(".*")
+				i++
+			} else {
+				b.WriteString("(?:.*/)?")
+				i += 2
+			}
+		case '?':
+			b.WriteString("[^/]")
+		case '[':
+			end := strings.IndexByte(glob[i+1:], ']')
+			if end < 0 {
+				return "", errors.New("unterminated character class")
+			}
+			class := glob[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^/" + class[1:]
+			}
+			b.WriteString("[" + class + "]")
+			i += end + 1
+		case '\\':
+			if i+1 >= len(glob) {
+				return "", errors.New("trailing escape character")
+			}
+			i++
+			b.WriteString(regexp.QuoteMeta(string(glob[i])))
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("$")
+	return b.String(), nil
+}
@@ -0,0 +1,106 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPatternMatch(t *testing.T) {
+	type spec struct {
+		name     string
+		pattern  string
+		matches  []string
+		misses   []string
+		expError string
+	}
+
+	cases := []spec{
+		{
+			name:    "Success/BaseNameGlob",
+			pattern: "*.jpg",
+			matches: []string{"fish.jpg", "images/fish.jpg", "a/b/c.jpg"},
+			misses:  []string{"a/b.jpgx", "fishxjpg", "fish.jpg/info.txt"},
+		},
+		{
+			name:    "Success/ExactName",
+			pattern: "fish.jpg",
+			matches: []string{"fish.jpg", "images/fish.jpg"},
+			misses:  []string{"fishxjpg", "fish.jpg.bak"},
+		},
+		{
+			name:    "Success/RootAnchored",
+			pattern: "/fish.jpg",
+			matches: []string{"fish.jpg"},
+			misses:  []string{"images/fish.jpg"},
+		},
+		{
+			name:    "Success/PathSegments",
+			pattern: "images/*.jpg",
+			matches: []string{"images/fish.jpg"},
+			misses:  []string{"images/sub/fish.jpg", "other/images/fish.jpg"},
+		},
+		{
+			name:    "Success/DoubleStarPrefix",
+			pattern: "**/data/*.json",
+			matches: []string{"data/a.json", "x/data/a.json", "x/y/data/a.json"},
+			misses:  []string{"data/sub/a.json", "xdata/a.json"},
+		},
+		{
+			name:    "Success/DoubleStarMiddle",
+			pattern: "a/**/b.txt",
+			matches: []string{"a/b.txt", "a/x/b.txt", "a/x/y/b.txt"},
+			misses:  []string{"ab.txt", "b/a/b.txt"},
+		},
+		{
+			name:    "Success/DoubleStarSuffix",
+			pattern: "a/**",
+			matches: []string{"a/b.txt", "a/x/y/b.txt"},
+			misses:  []string{"b/a/c.txt"},
+		},
+		{
+			name:    "Success/SingleCharacterAndClass",
+			pattern: "file?.[!a]xt",
+			matches: []string{"file1.txt", "dir/fileA.txt"},
+			misses:  []string{"file.txt", "file1.axt", "file12.txt"},
+		},
+		{
+			name:    "Success/Regex",
+			pattern: "regex:images/.*\\.(jpg|png)",
+			matches: []string{"images/fish.jpg", "images/sub/fish.png"},
+			misses:  []string{"other/images/fish.jpg", "images/fish.jpgx"},
+		},
+		{
+			name:     "Failure/InvalidDoubleStar",
+			pattern:  "a**/b",
+			expError: "pattern \"a**/b\": ** must be a complete path segment",
+		},
+		{
+			name:     "Failure/InvalidRegex",
+			pattern:  "regex:(",
+			expError: "pattern \"regex:(\": error parsing regexp: missing closing ): `^(?:()$`",
+		},
+		{
+			name:     "Failure/UnterminatedClass",
+			pattern:  "file[a",
+			expError: "pattern \"file[a\": unterminated character class",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			pattern, err := CompilePattern(c.pattern)
+			if c.expError != "" {
+				require.EqualError(t, err, c.expError)
+				return
+			}
+			require.NoError(t, err)
+			for _, m := range c.matches {
+				require.True(t, pattern.Match(m), "expected %q to match %q", c.pattern, m)
+			}
+			for _, m := range c.misses {
+				require.False(t, pattern.Match(m), "expected %q to not match %q", c.pattern, m)
+			}
+		})
+	}
+}
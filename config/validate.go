@@ -0,0 +1,87 @@
+package config
+
+import (
+	"sort"
+
+	"github.com/emporous/emporous-go/api/client/v1alpha1"
+)
+
+// ValidationReport describes issues found when applying a
+// DataSetConfiguration to the files in a workspace.
+type ValidationReport struct {
+	// UnmatchedPatterns are the file and extractor plugin
+	// patterns that do not match any files.
+	UnmatchedPatterns []string
+	// FileInfoConflicts are files matched by more than one
+	// file pattern with file information set.
+	FileInfoConflicts []FileInfoConflict
+}
+
+// FileInfoConflict describes a file matched by
+// conflicting file information entries.
+type FileInfoConflict struct {
+	// File is the path of the file in the workspace.
+	File string
+	// Patterns are the file patterns of the conflicting entries.
+	Patterns []string
+}
+
+// HasIssues returns whether the report contains any issues.
+func (r ValidationReport) HasIssues() bool {
+	return len(r.UnmatchedPatterns) != 0 || len(r.FileInfoConflicts) != 0
+}
+
+// ValidateDataSetConfig checks the file patterns in the DataSetConfiguration against
+// the workspace files. An error is returned if a pattern cannot be compiled.
+func ValidateDataSetConfig(configuration v1alpha1.DataSetConfiguration, files []string) (ValidationReport, error) {
+	var report ValidationReport
+
+	type entry struct {
+		pattern  *Pattern
+		fileInfo bool
+		matched  bool
+	}
+	var entries []*entry
+	for _, file := range configuration.Collection.Files {
+		pattern, err := CompilePattern(file.File)
+		if err != nil {
+			return report, err
+		}
+		entries = append(entries, &entry{pattern: pattern, fileInfo: file.HasFileInfo()})
+	}
+	for _, plugin := range configuration.Collection.Extractors.Plugins {
+		for _, file := range plugin.Files {
+			pattern, err := CompilePattern(file)
+			if err != nil {
+				return report, err
+			}
+			entries = append(entries, &entry{pattern: pattern})
+		}
+	}
+
+	sorted := append([]string{}, files...)
+	sort.Strings(sorted)
+	for _, file := range sorted {
+		var fileInfoPatterns []string
+		for _, e := range entries {
+			if !e.pattern.Match(file) {
+				continue
+			}
+			e.matched = true
+			if e.fileInfo {
+				fileInfoPatterns = append(fileInfoPatterns, e.pattern.String())
+			}
+		}
+		if len(fileInfoPatterns) > 1 {
+			report.FileInfoConflicts = append(report.FileInfoConflicts, FileInfoConflict{File: file, Patterns: fileInfoPatterns})
+		}
+	}
+
+	for _, e := range entries {
+		if !e.matched {
+			report.UnmatchedPatterns = append(report.UnmatchedPatterns, e.pattern.String())
+		}
+	}
+
+	return report, nil
+}
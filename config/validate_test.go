@@ -0,0 +1,110 @@
+package config
+
+import (
+	"testing"
+
+	empspec "github.com/emporous/collection-spec/specs-go/v1alpha1"
+	"github.com/stretchr/testify/require"
+
+	"github.com/emporous/emporous-go/api/client/v1alpha1"
+)
+
+func TestValidateDataSetConfig(t *testing.T) {
+	type spec struct {
+		name     string
+		config   v1alpha1.DataSetConfiguration
+		files    []string
+		exp      ValidationReport
+		expError string
+	}
+
+	unsetFileInfo := empspec.File{UID: -1, GID: -1}
+	cases := []spec{
+		{
+			name: "Success/NoIssues",
+			config: v1alpha1.DataSetConfiguration{
+				Collection: v1alpha1.DataSetConfigurationSpec{
+					Files: []v1alpha1.File{
+						{File: "*.json", FileInfo: unsetFileInfo},
+						{File: "images/**", FileInfo: empspec.File{Permissions: 0600, UID: -1, GID: -1}},
+					},
+				},
+			},
+			files: []string{"info.json", "images/fish.jpg"},
+		},
+		{
+			name: "Success/UnmatchedPatterns",
+			config: v1alpha1.DataSetConfiguration{
+				Collection: v1alpha1.DataSetConfigurationSpec{
+					Files: []v1alpha1.File{
+						{File: "*.json", FileInfo: unsetFileInfo},
+						{File: "*.txt", FileInfo: unsetFileInfo},
+					},
+					Extractors: v1alpha1.ExtractorSpec{
+						Plugins: []v1alpha1.ExtractorPlugin{
+							{Name: "test", Command: "test", Files: []string{"regex:.*\\.csv"}},
+						},
+					},
+				},
+			},
+			files: []string{"info.json"},
+			exp: ValidationReport{
+				UnmatchedPatterns: []string{"*.txt", "regex:.*\\.csv"},
+			},
+		},
+		{
+			name: "Success/FileInfoConflicts",
+			config: v1alpha1.DataSetConfiguration{
+				Collection: v1alpha1.DataSetConfigurationSpec{
+					Files: []v1alpha1.File{
+						{File: "*.json", FileInfo: empspec.File{UID: 1000, GID: -1}},
+						{File: "data/**", FileInfo: empspec.File{Permissions: 0600, UID: -1, GID: -1}},
+						{File: "data/*", FileInfo: unsetFileInfo},
+					},
+				},
+			},
+			files: []string{"data/info.json", "data/fish.jpg"},
+			exp: ValidationReport{
+				FileInfoConflicts: []FileInfoConflict{
+					{File: "data/info.json", Patterns: []string{"*.json", "data/**"}},
+				},
+			},
+		},
+		{
+			name: "Success/AttributesOnlyPatterns",
+			config: v1alpha1.DataSetConfiguration{
+				Collection: v1alpha1.DataSetConfigurationSpec{
+					Files: []v1alpha1.File{
+						{File: "*.json", Attributes: v1alpha1.Attributes{"fiction": true}},
+						{File: "data/*", Attributes: v1alpha1.Attributes{"size": "small"}},
+					},
+				},
+			},
+			files: []string{"data/info.json"},
+		},
+		{
+			name: "Failure/InvalidPattern",
+			config: v1alpha1.DataSetConfiguration{
+				Collection: v1alpha1.DataSetConfigurationSpec{
+					Files: []v1alpha1.File{
+						{File: "regex:(", FileInfo: unsetFileInfo},
+					},
+				},
+			},
+			expError: "pattern \"regex:(\": error parsing regexp: missing closing ): `^(?:()$`",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			report, err := ValidateDataSetConfig(c.config, c.files)
+			if c.expError != "" {
+				require.EqualError(t, err, c.expError)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, c.exp, report)
+				require.Equal(t, len(c.exp.UnmatchedPatterns)+len(c.exp.FileInfoConflicts) != 0, report.HasIssues())
+			}
+		})
+	}
+}
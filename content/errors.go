@@ -1,6 +1,9 @@
 package content
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 // ErrNotStored denotes that a reference is not stored on the content store.
 type ErrNotStored struct {
@@ -10,3 +13,15 @@ type ErrNotStored struct {
 func (e *ErrNotStored) Error() string {
 	return fmt.Sprintf("descriptor for reference %s is not stored", e.Reference)
 }
+
+// ErrMissingContent denotes that content referenced by the manifests
+// stored under a reference is not stored on the content store
+// (e.g. a sparse manifest written by a filtered pull).
+type ErrMissingContent struct {
+	Reference string
+	Digests   []string
+}
+
+func (e *ErrMissingContent) Error() string {
+	return fmt.Sprintf("reference %s is missing %d blob(s): %s", e.Reference, len(e.Digests), strings.Join(e.Digests, ", "))
+}
@@ -0,0 +1,211 @@
+package file
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/content/file"
+
+	"github.com/emporous/emporous-go/content"
+)
+
+var _ content.FileStore = &Store{}
+
+// Store wraps the oras file store to write titled content
+// to a local directory and record the directory location.
+type Store struct {
+	*file.Store
+	dir      string
+	template *Template
+	blobs    content.BlobLocator
+	linkMode LinkMode
+
+	mu sync.Mutex
+	// paths maps rendered paths to the descriptor written to them.
+	paths map[string]ocispec.Descriptor
+	// locations maps the digests of descriptors to their rendered paths.
+	locations map[string]string
+	// shared records the digests of descriptors written as hard links.
+	shared map[string]bool
+}
+
+// Option configures a Store.
+type Option func(*Store)
+
+// WithLinks configures the Store to create titled files from the blobs already
+// stored in blobs using the link mode instead of copying the pushed content. Files
+// that cannot be linked, such as blobs that are not stored or are on a different
+// filesystem, are copied.
+func WithLinks(blobs content.BlobLocator, mode LinkMode) Option {
+	return func(s *Store) {
+		s.blobs = blobs
+		s.linkMode = mode
+	}
+}
+
+// New creates a Store that writes files to dir.
+func New(dir string, opts ...Option) *Store {
+	s := &Store{
+		Store:  file.New(dir),
+		dir:    dir,
+		shared: map[string]bool{},
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// NewWithTemplate creates a Store that writes files to the path rendered by the
+// template under dir. The source collection of each file is read from the context
+// set with content.WithSource. Pushing a file rendered to the path of a different
+// file or outside of dir fails.
+func NewWithTemplate(dir string, tmpl *Template, opts ...Option) *Store {
+	s := New(dir, opts...)
+	s.template = tmpl
+	s.paths = map[string]ocispec.Descriptor{}
+	s.locations = map[string]string{}
+	return s
+}
+
+// Dir returns the directory files are written to.
+func (s *Store) Dir() string {
+	return s.dir
+}
+
+// Push pushes the content, matching the expected descriptor. Titled
+// content is written to the path rendered by the template, if set,
+// and linked to stored blobs when links are configured.
+func (s *Store) Push(ctx context.Context, expected ocispec.Descriptor, r io.Reader) error {
+	title, ok := expected.Annotations[ocispec.AnnotationTitle]
+	if !ok {
+		return s.Store.Push(ctx, expected, r)
+	}
+	if s.template == nil {
+		if s.link(expected, title) {
+			return nil
+		}
+		s.unlink(title)
+		return s.Store.Push(ctx, expected, r)
+	}
+
+	source, _ := content.SourceFromContext(ctx)
+	location, err := s.template.Render(expected, source)
+	if err != nil {
+		return fmt.Errorf("file %s: %w", title, err)
+	}
+	if err := s.reserve(location, expected); err != nil {
+		return err
+	}
+	if s.link(expected, location) {
+		return nil
+	}
+	s.unlink(location)
+
+	desc := expected
+	desc.Annotations = make(map[string]string, len(expected.Annotations))
+	for key, value := range expected.Annotations {
+		desc.Annotations[key] = value
+	}
+	desc.Annotations[ocispec.AnnotationTitle] = location
+	return s.Store.Push(ctx, desc, r)
+}
+
+// Path returns the path relative to Dir that the titled content
+// described by desc is written to.
+func (s *Store) Path(desc ocispec.Descriptor) (string, bool) {
+	if s.template == nil {
+		title, ok := desc.Annotations[ocispec.AnnotationTitle]
+		return title, ok
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	location, ok := s.locations[desc.Digest.String()]
+	return location, ok
+}
+
+// Shared returns whether the file for the descriptor is
+// a hard link to a blob and must not be modified in place.
+func (s *Store) Shared(desc ocispec.Descriptor) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.shared[desc.Digest.String()]
+}
+
+// link creates the file at the slash-separated location under
+// dir from the stored blob described by desc. It returns false if
+// links are not configured, the content is a directory to unpack,
+// or the file could not be linked.
+func (s *Store) link(desc ocispec.Descriptor, location string) bool {
+	if s.blobs == nil || s.linkMode == "" || s.linkMode == LinkCopy {
+		return false
+	}
+	if desc.Annotations[file.AnnotationUnpack] == "true" {
+		return false
+	}
+	blob, err := s.blobs.BlobPath(desc)
+	if err != nil {
+		return false
+	}
+	path, ok := s.filePath(location)
+	if !ok {
+		// The path is rejected when the content is pushed.
+		return false
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return false
+	}
+	shared, err := linkBlob(s.linkMode, blob, path)
+	if err != nil {
+		return false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.shared[desc.Digest.String()] = shared
+	return true
+}
+
+// unlink removes an existing regular file at the slash-separated location
+// under dir so that it is replaced instead of truncated. Truncating a file
+// that is a hard link to a blob would modify the blob.
+func (s *Store) unlink(location string) {
+	path, ok := s.filePath(location)
+	if !ok {
+		return
+	}
+	if info, err := os.Lstat(path); err == nil && info.Mode().IsRegular() {
+		_ = os.Remove(path)
+	}
+}
+
+// filePath returns the path of the slash-separated location
+// under dir. It returns false if the path is outside of dir.
+func (s *Store) filePath(location string) (string, bool) {
+	path := filepath.Join(s.dir, filepath.FromSlash(location))
+	rel, err := filepath.Rel(s.dir, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return path, true
+}
+
+// reserve records the rendered path of the descriptor. An error is
+// returned if a different descriptor was rendered to the same path.
+func (s *Store) reserve(location string, desc ocispec.Descriptor) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if existing, ok := s.paths[location]; ok && existing.Digest != desc.Digest {
+		return fmt.Errorf("files %s and %s both render to path %s",
+			existing.Annotations[ocispec.AnnotationTitle], desc.Annotations[ocispec.AnnotationTitle], location)
+	}
+	s.paths[location] = desc
+	s.locations[desc.Digest.String()] = location
+	return nil
+}
@@ -0,0 +1,59 @@
+package file
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	empspec "github.com/emporous/collection-spec/specs-go/v1alpha1"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+
+	"github.com/emporous/emporous-go/content"
+)
+
+func TestStorePushWithTemplate(t *testing.T) {
+	ctx := content.WithSource(context.TODO(), "localhost:5001/test:latest")
+	dir := t.TempDir()
+	tmpl, err := ParseTemplate("{{.collection}}/{{.animal}}/{{base .title}}")
+	require.NoError(t, err)
+	store := NewWithTemplate(dir, tmpl)
+	defer store.Close()
+
+	fish := fileDescriptor("images/fish.jpg", `{"unknown":{"animal":"fish"}}`, []byte("fish"))
+	require.NoError(t, store.Push(ctx, fish, bytes.NewReader([]byte("fish"))))
+	data, err := ioutil.ReadFile(filepath.Join(dir, "localhost:5001", "test", "fish", "fish.jpg"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("fish"), data)
+	location, ok := store.Path(fish)
+	require.True(t, ok)
+	require.Equal(t, "localhost:5001/test/fish/fish.jpg", location)
+
+	// A different file rendered to the same path is rejected.
+	other := fileDescriptor("backup/fish.jpg", `{"unknown":{"animal":"fish"}}`, []byte("other"))
+	err = store.Push(ctx, other, bytes.NewReader([]byte("other")))
+	require.EqualError(t, err, "files images/fish.jpg and backup/fish.jpg both render to path localhost:5001/test/fish/fish.jpg")
+	_, ok = store.Path(other)
+	require.False(t, ok)
+
+	// Content without a title is not rendered.
+	untitled := ocispec.Descriptor{MediaType: "text/plain", Digest: digest.FromBytes([]byte("config")), Size: 6}
+	require.NoError(t, store.Push(ctx, untitled, bytes.NewReader([]byte("config"))))
+	_, ok = store.Path(untitled)
+	require.False(t, ok)
+}
+
+func fileDescriptor(title, attributes string, data []byte) ocispec.Descriptor {
+	return ocispec.Descriptor{
+		MediaType: "image/jpeg",
+		Digest:    digest.FromBytes(data),
+		Size:      int64(len(data)),
+		Annotations: map[string]string{
+			ocispec.AnnotationTitle:              title,
+			empspec.AnnotationEmporousAttributes: attributes,
+		},
+	}
+}
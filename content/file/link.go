@@ -0,0 +1,132 @@
+package file
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// LinkMode describes how files are materialized from blobs
+// that are already stored in a content.BlobLocator.
+type LinkMode string
+
+const (
+	// LinkCopy copies the blob content to the file.
+	LinkCopy LinkMode = "copy"
+	// LinkHardlink creates the file as a hard link to the blob. Hard linked
+	// files share their data with the blob and are made read-only.
+	LinkHardlink LinkMode = "hardlink"
+	// LinkReflink clones the blob to the file with a copy-on-write
+	// reflink. Reflinked files can be modified without affecting the blob.
+	LinkReflink LinkMode = "reflink"
+	// LinkAuto creates the file with a reflink if the filesystem
+	// supports it, and a hard link otherwise.
+	LinkAuto LinkMode = "auto"
+)
+
+// errReflinkUnsupported denotes that reflinks are not supported on the platform.
+var errReflinkUnsupported = errors.New("reflinks are not supported on this platform")
+
+// ParseLinkMode parses a link mode. An empty string is parsed as LinkCopy.
+func ParseLinkMode(mode string) (LinkMode, error) {
+	switch LinkMode(mode) {
+	case "":
+		return LinkCopy, nil
+	case LinkCopy, LinkHardlink, LinkReflink, LinkAuto:
+		return LinkMode(mode), nil
+	}
+	return "", fmt.Errorf("invalid link mode %q: must be one of %s, %s, %s or %s", mode, LinkCopy, LinkHardlink, LinkReflink, LinkAuto)
+}
+
+// linkBlob creates the file at location from the blob file using the link mode.
+// It returns whether the file is a hard link sharing its data with the blob.
+// An existing file at location is replaced.
+func linkBlob(mode LinkMode, blob, location string) (bool, error) {
+	switch mode {
+	case LinkHardlink:
+		return true, hardlink(blob, location)
+	case LinkReflink:
+		return false, reflink(blob, location)
+	case LinkAuto:
+		if err := reflink(blob, location); err == nil {
+			return false, nil
+		}
+		return true, hardlink(blob, location)
+	}
+	return false, fmt.Errorf("link mode %q does not link files", mode)
+}
+
+// hardlink creates the file at location as a hard link to the blob. Write
+// permissions are removed from the shared data so that in-place edits of
+// the file cannot modify the blob.
+func hardlink(blob, location string) error {
+	info, err := os.Stat(blob)
+	if err != nil {
+		return err
+	}
+	if err := os.Chmod(blob, info.Mode().Perm()&^0222); err != nil {
+		return err
+	}
+	tmp, err := tempName(location)
+	if err != nil {
+		return err
+	}
+	if err := os.Link(blob, tmp); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, location); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+// reflink creates the file at location as a copy-on-write clone of the blob.
+func reflink(blob, location string) error {
+	src, err := os.Open(blob)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := ioutil.TempFile(filepath.Dir(location), "."+filepath.Base(location)+".")
+	if err != nil {
+		return err
+	}
+	tmp := dst.Name()
+	if err := cloneFile(dst, src); err != nil {
+		_ = dst.Close()
+		_ = os.Remove(tmp)
+		return err
+	}
+	if err := dst.Chmod(0644); err != nil {
+		_ = dst.Close()
+		_ = os.Remove(tmp)
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, location); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+// tempName returns an unused path in the directory of location
+// that the file can be created at before it is renamed.
+func tempName(location string) (string, error) {
+	f, err := ioutil.TempFile(filepath.Dir(location), "."+filepath.Base(location)+".")
+	if err != nil {
+		return "", err
+	}
+	name := f.Name()
+	if err := f.Close(); err != nil {
+		return "", err
+	}
+	return name, os.Remove(name)
+}
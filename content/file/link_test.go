@@ -0,0 +1,142 @@
+package file
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+	"oras.land/oras-go/v2/errdef"
+)
+
+func TestStorePushWithLinks(t *testing.T) {
+	data := []byte("fish")
+	stored := fileDescriptor("images/fish.jpg", `{"unknown":{"animal":"fish"}}`, data)
+	missing := fileDescriptor("images/cat.jpg", `{"unknown":{"animal":"cat"}}`, []byte("cat"))
+
+	type spec struct {
+		name      string
+		mode      LinkMode
+		desc      ocispec.Descriptor
+		data      []byte
+		expLinked bool
+		expShared bool
+	}
+
+	cases := []spec{
+		{
+			name:      "Success/Hardlink",
+			mode:      LinkHardlink,
+			desc:      stored,
+			data:      data,
+			expLinked: true,
+			expShared: true,
+		},
+		{
+			name: "Success/Copy",
+			mode: LinkCopy,
+			desc: stored,
+			data: data,
+		},
+		{
+			name: "Success/BlobNotStored",
+			mode: LinkHardlink,
+			desc: missing,
+			data: []byte("cat"),
+		},
+		{
+			// Reflinks are not supported by all filesystems,
+			// so the file may be cloned or copied.
+			name: "Success/Reflink",
+			mode: LinkReflink,
+			desc: stored,
+			data: data,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			blobs := newTestBlobs(t, data)
+			dir := t.TempDir()
+			store := New(dir, WithLinks(blobs, c.mode))
+			defer store.Close()
+
+			require.NoError(t, store.Push(context.TODO(), c.desc, bytes.NewReader(c.data)))
+			location := filepath.Join(dir, "images", filepath.Base(c.desc.Annotations[ocispec.AnnotationTitle]))
+			actual, err := ioutil.ReadFile(location)
+			require.NoError(t, err)
+			require.Equal(t, c.data, actual)
+			require.Equal(t, c.expShared, store.Shared(c.desc))
+
+			fileInfo, err := os.Stat(location)
+			require.NoError(t, err)
+			blobInfo, err := os.Stat(blobs.path)
+			require.NoError(t, err)
+			require.Equal(t, c.expLinked, os.SameFile(fileInfo, blobInfo))
+			if c.expShared {
+				require.Zero(t, fileInfo.Mode().Perm()&0222)
+			}
+		})
+	}
+}
+
+func TestStorePushReplacesLinks(t *testing.T) {
+	data := []byte("fish")
+	desc := fileDescriptor("fish.jpg", `{"unknown":{"animal":"fish"}}`, data)
+	blobs := newTestBlobs(t, data)
+	dir := t.TempDir()
+
+	linked := New(dir, WithLinks(blobs, LinkHardlink))
+	require.NoError(t, linked.Push(context.TODO(), desc, bytes.NewReader(data)))
+	require.NoError(t, linked.Close())
+
+	// Copying different content to the path of a hard link
+	// replaces the file instead of writing to the blob.
+	updated := fileDescriptor("fish.jpg", `{"unknown":{"animal":"fish"}}`, []byte("shark"))
+	copied := New(dir)
+	defer copied.Close()
+	require.NoError(t, copied.Push(context.TODO(), updated, bytes.NewReader([]byte("shark"))))
+
+	actual, err := ioutil.ReadFile(filepath.Join(dir, "fish.jpg"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("shark"), actual)
+	blob, err := ioutil.ReadFile(blobs.path)
+	require.NoError(t, err)
+	require.Equal(t, data, blob)
+}
+
+func TestParseLinkMode(t *testing.T) {
+	mode, err := ParseLinkMode("")
+	require.NoError(t, err)
+	require.Equal(t, LinkCopy, mode)
+	mode, err = ParseLinkMode("auto")
+	require.NoError(t, err)
+	require.Equal(t, LinkAuto, mode)
+	_, err = ParseLinkMode("symlink")
+	require.EqualError(t, err, `invalid link mode "symlink": must be one of copy, hardlink, reflink or auto`)
+}
+
+// testBlobs is a content.BlobLocator storing a single blob.
+type testBlobs struct {
+	path string
+	desc ocispec.Descriptor
+}
+
+func newTestBlobs(t *testing.T, data []byte) *testBlobs {
+	desc := fileDescriptor("", "", data)
+	path := filepath.Join(t.TempDir(), desc.Digest.Encoded())
+	require.NoError(t, ioutil.WriteFile(path, data, 0600))
+	return &testBlobs{path: path, desc: desc}
+}
+
+func (b *testBlobs) BlobPath(desc ocispec.Descriptor) (string, error) {
+	if desc.Digest != b.desc.Digest {
+		return "", fmt.Errorf("%s: %w", desc.Digest, errdef.ErrNotFound)
+	}
+	return b.path, nil
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+
+package file
+
+import "os"
+
+// cloneFile reports that reflinks are not supported.
+func cloneFile(_, _ *os.File) error {
+	return errReflinkUnsupported
+}
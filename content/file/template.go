@@ -0,0 +1,116 @@
+package file
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	empspec "github.com/emporous/collection-spec/specs-go/v1alpha1"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/registry"
+)
+
+// ErrEscapesDir denotes that a rendered path is outside of the output directory.
+var ErrEscapesDir = errors.New("path escapes the output directory")
+
+// Template renders the path of pulled files relative to the output directory.
+// Attributes of the file are available by key (e.g. {{.animal}}) and attributes
+// with keys that are not identifiers with the index function. The following keys
+// describe the file and take precedence over attributes with the same key:
+//
+//   - title: the path the file was added to the collection with
+//   - digest: the digest of the file
+//   - mediaType: the media type of the file
+//   - collection: the repository of the collection the file was pulled from
+//
+// The base, dir and ext functions operate on slash-separated paths.
+type Template struct {
+	tmpl *template.Template
+}
+
+// ParseTemplate parses the text as a path template.
+func ParseTemplate(text string) (*Template, error) {
+	if strings.HasPrefix(text, "/") || filepath.IsAbs(text) {
+		return nil, fmt.Errorf("template %q: %w", text, ErrEscapesDir)
+	}
+	tmpl, err := template.New("output").
+		Option("missingkey=error").
+		Funcs(template.FuncMap{
+			"base": path.Base,
+			"dir":  path.Dir,
+			"ext":  path.Ext,
+		}).
+		Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	return &Template{tmpl: tmpl}, nil
+}
+
+// Render returns the slash-separated path of the file described by desc
+// pulled from the source collection reference. An error is returned if
+// the path is empty or outside of the output directory.
+func (t *Template) Render(desc ocispec.Descriptor, source string) (string, error) {
+	data, err := templateData(desc, source)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := t.tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+
+	rendered := filepath.ToSlash(buf.String())
+	if strings.HasPrefix(rendered, "/") || filepath.IsAbs(buf.String()) {
+		return "", fmt.Errorf("path %q: %w", rendered, ErrEscapesDir)
+	}
+	cleaned := path.Clean(rendered)
+	if cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return "", fmt.Errorf("path %q: %w", rendered, ErrEscapesDir)
+	}
+	if strings.TrimSpace(cleaned) == "" || cleaned == "." {
+		return "", errors.New("template rendered an empty path")
+	}
+	return cleaned, nil
+}
+
+// templateData returns the attributes and file
+// information available to the template.
+func templateData(desc ocispec.Descriptor, source string) (map[string]interface{}, error) {
+	data := map[string]interface{}{}
+	if value, ok := desc.Annotations[empspec.AnnotationEmporousAttributes]; ok {
+		var sets map[string]json.RawMessage
+		if err := json.Unmarshal([]byte(value), &sets); err != nil {
+			return nil, fmt.Errorf("error reading attributes: %w", err)
+		}
+		for id, set := range sets {
+			// Core attributes describe the descriptor
+			// and are not user defined.
+			if strings.HasPrefix(id, "core-") {
+				continue
+			}
+			var attrs map[string]interface{}
+			if err := json.Unmarshal(set, &attrs); err != nil {
+				continue
+			}
+			for key, value := range attrs {
+				data[key] = value
+			}
+		}
+	}
+
+	data["title"] = desc.Annotations[ocispec.AnnotationTitle]
+	data["digest"] = desc.Digest.String()
+	data["mediaType"] = desc.MediaType
+	collection := source
+	if ref, err := registry.ParseReference(source); err == nil {
+		collection = fmt.Sprintf("%s/%s", ref.Registry, ref.Repository)
+	}
+	data["collection"] = collection
+	return data, nil
+}
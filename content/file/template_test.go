@@ -0,0 +1,94 @@
+package file
+
+import (
+	"testing"
+
+	empspec "github.com/emporous/collection-spec/specs-go/v1alpha1"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplateRender(t *testing.T) {
+	desc := ocispec.Descriptor{
+		MediaType: "image/jpeg",
+		Digest:    "sha256:2e30f6131ce2164ed5ef017845130727291417d60a1be6fad669bdc4473289cd",
+		Size:      5,
+		Annotations: map[string]string{
+			ocispec.AnnotationTitle:              "images/fish.jpg",
+			empspec.AnnotationEmporousAttributes: `{"core-descriptor":{"id":"fish"},"unknown":{"animal":"fish","size":2,"my-key":"value"}}`,
+		},
+	}
+
+	type spec struct {
+		name     string
+		template string
+		source   string
+		exp      string
+		expError string
+	}
+
+	cases := []spec{
+		{
+			name:     "Success/Attributes",
+			template: "{{.animal}}/{{.size}}/{{base .title}}",
+			exp:      "fish/2/fish.jpg",
+		},
+		{
+			name:     "Success/Flatten",
+			template: "{{base .title}}",
+			exp:      "fish.jpg",
+		},
+		{
+			name:     "Success/Collection",
+			template: "{{.collection}}/{{.title}}",
+			source:   "localhost:5001/test@sha256:2e30f6131ce2164ed5ef017845130727291417d60a1be6fad669bdc4473289cd",
+			exp:      "localhost:5001/test/images/fish.jpg",
+		},
+		{
+			name:     "Success/IndexAndFunctions",
+			template: `{{index . "my-key"}}/{{dir .title}}/{{.digest}}{{ext .title}}`,
+			exp:      "value/images/sha256:2e30f6131ce2164ed5ef017845130727291417d60a1be6fad669bdc4473289cd.jpg",
+		},
+		{
+			name:     "Success/CleanedPath",
+			template: "./{{.animal}}//{{.title}}",
+			exp:      "fish/images/fish.jpg",
+		},
+		{
+			name:     "Failure/MissingAttribute",
+			template: "{{.color}}/{{.title}}",
+			expError: `template: output:1:2: executing "output" at <.color>: map has no entry for key "color"`,
+		},
+		{
+			name:     "Failure/EscapesDir",
+			template: "{{.animal}}/../../{{.title}}",
+			expError: `path "fish/../../images/fish.jpg": path escapes the output directory`,
+		},
+		{
+			name:     "Failure/AbsoluteTemplate",
+			template: "/tmp/{{.title}}",
+			expError: `template "/tmp/{{.title}}": path escapes the output directory`,
+		},
+		{
+			name:     "Failure/EmptyPath",
+			template: `{{if eq .animal "cat"}}{{.title}}{{end}}`,
+			expError: "template rendered an empty path",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			tmpl, err := ParseTemplate(c.template)
+			if err == nil {
+				var rendered string
+				rendered, err = tmpl.Render(desc, c.source)
+				if c.expError == "" {
+					require.NoError(t, err)
+					require.Equal(t, c.exp, rendered)
+					return
+				}
+			}
+			require.EqualError(t, err, c.expError)
+		})
+	}
+}
@@ -0,0 +1,420 @@
+package layout
+
+import (
+	"archive/tar"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"time"
+
+	empspec "github.com/emporous/collection-spec/specs-go/v1alpha1"
+	"github.com/opencontainers/go-digest"
+	"github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2"
+	orascontent "oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/content/oci"
+	"oras.land/oras-go/v2/errdef"
+
+	clientapi "github.com/emporous/emporous-go/api/client/v1alpha1"
+	"github.com/emporous/emporous-go/content"
+	"github.com/emporous/emporous-go/nodes/descriptor/v2"
+)
+
+// ExportOptions configure the content written by Export.
+type ExportOptions struct {
+	// Links includes the collections linked from the
+	// exported collections. Linked collections must be stored
+	// in the layout (e.g. by pulling with links) and are recorded
+	// under the registry/namespace@digest reference of the link.
+	Links bool
+	// Schemas includes the schemas the exported collections
+	// were built against. Schemas must be stored in the layout
+	// under their schema address.
+	Schemas bool
+}
+
+// Export writes the collections stored under the references to w as a self-contained
+// OCI image layout tar archive. The references are recorded in the archive index, so
+// Import can restore them. If successful, the manifests in the archive index are returned.
+func (l *Layout) Export(ctx context.Context, w io.Writer, references []string, opts ExportOptions) ([]ocispec.Descriptor, error) {
+	e := &exporter{
+		layout: l,
+		opts:   opts,
+		blobs:  map[digest.Digest]ocispec.Descriptor{},
+		seen:   map[string]struct{}{},
+	}
+	for _, reference := range references {
+		desc, err := l.Resolve(ctx, reference)
+		if err != nil {
+			return nil, err
+		}
+		if err := e.addManifest(ctx, desc, reference, reference); err != nil {
+			return nil, err
+		}
+	}
+	if err := e.write(ctx, w); err != nil {
+		return nil, err
+	}
+	return e.manifests, nil
+}
+
+// Import reads an OCI image layout tar archive, such as one written by Export, and stores its
+// content in the layout. References recorded in the archive index are tagged in the layout.
+// If successful, the tagged references are returned.
+func (l *Layout) Import(ctx context.Context, r io.Reader) ([]string, error) {
+	dir, err := ioutil.TempDir("", "import-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	index, err := extractArchive(r, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	// The layout verifies the digest and size of
+	// the content as it is stored.
+	src := oci.NewStorage(dir)
+	for _, desc := range index.Manifests {
+		if err := oras.CopyGraph(ctx, src, l, manifestDescriptor(desc), oras.DefaultCopyGraphOptions); err != nil {
+			return nil, fmt.Errorf("manifest %s: %w", desc.Digest, err)
+		}
+	}
+
+	var references []string
+	for _, desc := range index.Manifests {
+		reference, ok := desc.Annotations[ocispec.AnnotationRefName]
+		if !ok {
+			continue
+		}
+		if err := l.Tag(ctx, manifestDescriptor(desc), reference); err != nil {
+			return references, err
+		}
+		references = append(references, reference)
+	}
+	return references, nil
+}
+
+// exporter gathers the manifests and blobs written to an archive.
+type exporter struct {
+	layout *Layout
+	opts   ExportOptions
+	// manifests are the descriptors written to the archive index.
+	manifests []ocispec.Descriptor
+	blobs     map[digest.Digest]ocispec.Descriptor
+	// seen stores the references and untagged
+	// digests that have been added to the index.
+	seen map[string]struct{}
+}
+
+// addManifest adds the content reachable from the manifest described by desc. The reference
+// is recorded in the index if set. The name is used to describe the manifest in errors.
+func (e *exporter) addManifest(ctx context.Context, desc ocispec.Descriptor, reference, name string) error {
+	key := reference
+	if key == "" {
+		key = desc.Digest.String()
+	}
+	if _, ok := e.seen[key]; ok {
+		return nil
+	}
+	e.seen[key] = struct{}{}
+
+	root := manifestDescriptor(desc)
+	if reference != "" {
+		root.Annotations = map[string]string{ocispec.AnnotationRefName: reference}
+	}
+	e.manifests = append(e.manifests, root)
+
+	var missing []string
+	var follow []func() error
+	stack := []ocispec.Descriptor{desc}
+	for len(stack) != 0 {
+		current := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if _, ok := e.blobs[current.Digest]; ok {
+			continue
+		}
+
+		exists, err := e.layout.Exists(ctx, current)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			missing = append(missing, current.Digest.String())
+			continue
+		}
+		e.blobs[current.Digest] = current
+
+		switch current.MediaType {
+		case ocispec.MediaTypeImageManifest:
+			if e.opts.Links {
+				links, err := e.links(ctx, current)
+				if err != nil {
+					return err
+				}
+				follow = append(follow, links...)
+			}
+		case empspec.MediaTypeConfiguration:
+			if e.opts.Schemas {
+				schema, err := e.schema(ctx, current)
+				if err != nil {
+					return err
+				}
+				if schema != nil {
+					follow = append(follow, schema)
+				}
+			}
+		}
+
+		successors, err := orascontent.Successors(ctx, e.layout, current)
+		if err != nil {
+			return err
+		}
+		stack = append(stack, successors...)
+	}
+	if len(missing) != 0 {
+		sort.Strings(missing)
+		return &content.ErrMissingContent{Reference: name, Digests: missing}
+	}
+
+	for _, fn := range follow {
+		if err := fn(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// links returns functions that add the collections linked from the manifest.
+func (e *exporter) links(ctx context.Context, desc ocispec.Descriptor) ([]func() error, error) {
+	data, err := orascontent.FetchAll(ctx, e.layout, desc)
+	if err != nil {
+		return nil, err
+	}
+	var manifest ocispec.Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, err
+	}
+	linksJSON, ok := manifest.Annotations[empspec.AnnotationLink]
+	if !ok {
+		return nil, nil
+	}
+	var links []ocispec.Descriptor
+	if err := json.Unmarshal([]byte(linksJSON), &links); err != nil {
+		return nil, err
+	}
+
+	var result []func() error
+	for _, link := range links {
+		node, err := v2.NewNode(link.Digest.String(), link)
+		if err != nil {
+			return nil, err
+		}
+		if node.Properties == nil || !node.Properties.IsALink() {
+			continue
+		}
+		link := link
+		name := fmt.Sprintf("%s/%s@%s", node.Properties.Link.RegistryHint, node.Properties.Link.NamespaceHint, link.Digest)
+		result = append(result, func() error {
+			exists, err := e.layout.Exists(ctx, link)
+			if err != nil {
+				return err
+			}
+			if !exists {
+				return &content.ErrNotStored{Reference: name}
+			}
+			return e.addManifest(ctx, link, name, name)
+		})
+	}
+	return result, nil
+}
+
+// schema returns a function that adds the schema recorded in the
+// dataset configuration described by desc. If no schema is recorded, nil is returned.
+func (e *exporter) schema(ctx context.Context, desc ocispec.Descriptor) (func() error, error) {
+	data, err := orascontent.FetchAll(ctx, e.layout, desc)
+	if err != nil {
+		return nil, err
+	}
+	var config clientapi.DataSetConfiguration
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, err
+	}
+	address := config.Collection.SchemaAddress
+	if address == "" {
+		return nil, nil
+	}
+	return func() error {
+		schemaDesc, err := e.layout.Resolve(ctx, address)
+		if err != nil {
+			return err
+		}
+		return e.addManifest(ctx, schemaDesc, address, address)
+	}, nil
+}
+
+// write writes the gathered content as an OCI image layout tar archive.
+// Entries are written in a stable order with fixed timestamps, so
+// exporting the same content produces the same archive.
+func (e *exporter) write(ctx context.Context, w io.Writer) error {
+	tw := tar.NewWriter(w)
+
+	layoutJSON, err := json.Marshal(ocispec.ImageLayout{Version: ocispec.ImageLayoutVersion})
+	if err != nil {
+		return err
+	}
+	if err := writeTarFile(tw, ocispec.ImageLayoutFile, layoutJSON); err != nil {
+		return err
+	}
+	index := ocispec.Index{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		MediaType: ocispec.MediaTypeImageIndex,
+		Manifests: e.manifests,
+	}
+	indexJSON, err := json.Marshal(index)
+	if err != nil {
+		return err
+	}
+	if err := writeTarFile(tw, indexFile, indexJSON); err != nil {
+		return err
+	}
+
+	blobs := make([]ocispec.Descriptor, 0, len(e.blobs))
+	for _, desc := range e.blobs {
+		blobs = append(blobs, desc)
+	}
+	sort.Slice(blobs, func(i, j int) bool {
+		return blobs[i].Digest < blobs[j].Digest
+	})
+	for _, desc := range blobs {
+		if err := e.writeBlob(ctx, tw, desc); err != nil {
+			return err
+		}
+	}
+	return tw.Close()
+}
+
+// writeBlob writes the content described by desc to the archive.
+func (e *exporter) writeBlob(ctx context.Context, tw *tar.Writer, desc ocispec.Descriptor) error {
+	rc, err := e.layout.Fetch(ctx, desc)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	header := tarHeader(path.Join("blobs", desc.Digest.Algorithm().String(), desc.Digest.Encoded()), desc.Size)
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+	if _, err := io.Copy(tw, orascontent.NewVerifyReader(rc, desc)); err != nil {
+		return fmt.Errorf("blob %s: %w", desc.Digest, err)
+	}
+	return nil
+}
+
+// writeTarFile writes a file with the data to the archive.
+func writeTarFile(tw *tar.Writer, name string, data []byte) error {
+	if err := tw.WriteHeader(tarHeader(name, int64(len(data)))); err != nil {
+		return err
+	}
+	_, err := tw.Write(data)
+	return err
+}
+
+func tarHeader(name string, size int64) *tar.Header {
+	return &tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Size:     size,
+		Mode:     0644,
+		ModTime:  time.Unix(0, 0),
+	}
+}
+
+// extractArchive extracts the index and blobs of an OCI image layout
+// tar archive to dir. Other entries are skipped.
+func extractArchive(r io.Reader, dir string) (ocispec.Index, error) {
+	var index *ocispec.Index
+	var hasLayoutFile bool
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return ocispec.Index{}, fmt.Errorf("error reading archive: %w", err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		name := path.Clean(header.Name)
+		switch {
+		case name == ocispec.ImageLayoutFile:
+			var layout ocispec.ImageLayout
+			if err := json.NewDecoder(tr).Decode(&layout); err != nil {
+				return ocispec.Index{}, fmt.Errorf("failed to decode OCI layout file: %w", err)
+			}
+			if layout.Version != ocispec.ImageLayoutVersion {
+				return ocispec.Index{}, errdef.ErrUnsupportedVersion
+			}
+			hasLayoutFile = true
+		case name == indexFile:
+			if err := json.NewDecoder(tr).Decode(&index); err != nil {
+				return ocispec.Index{}, fmt.Errorf("failed to decode %s: %w", indexFile, err)
+			}
+		case path.Dir(path.Dir(name)) == "blobs":
+			dgst := digest.NewDigestFromEncoded(digest.Algorithm(path.Base(path.Dir(name))), path.Base(name))
+			if err := dgst.Validate(); err != nil {
+				return ocispec.Index{}, fmt.Errorf("archive entry %q: %w", header.Name, err)
+			}
+			if err := extractBlob(tr, filepath.Join(dir, "blobs", dgst.Algorithm().String(), dgst.Encoded())); err != nil {
+				return ocispec.Index{}, err
+			}
+		}
+	}
+
+	if !hasLayoutFile {
+		return ocispec.Index{}, fmt.Errorf("archive is not an OCI image layout: missing %s", ocispec.ImageLayoutFile)
+	}
+	if index == nil {
+		return ocispec.Index{}, fmt.Errorf("archive is not an OCI image layout: missing %s", indexFile)
+	}
+	return *index, nil
+}
+
+// extractBlob writes the blob content to the path.
+func extractBlob(r io.Reader, path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// manifestDescriptor returns the fields of the
+// descriptor that identify the manifest content.
+func manifestDescriptor(desc ocispec.Descriptor) ocispec.Descriptor {
+	return ocispec.Descriptor{
+		MediaType:    desc.MediaType,
+		Digest:       desc.Digest,
+		Size:         desc.Size,
+		ArtifactType: desc.ArtifactType,
+	}
+}
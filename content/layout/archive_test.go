@@ -0,0 +1,153 @@
+package layout
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	empspec "github.com/emporous/collection-spec/specs-go/v1alpha1"
+	"github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+
+	"github.com/emporous/emporous-go/content"
+	"github.com/emporous/emporous-go/nodes/descriptor"
+)
+
+func TestExportImport(t *testing.T) {
+	ctx := context.TODO()
+	l, err := NewWithContext(ctx, t.TempDir())
+	require.NoError(t, err)
+
+	exported := pushManifest(t, l, []byte("{}"), []byte("exported"))
+	other := pushManifest(t, l, []byte("{}"), []byte("other"))
+	require.NoError(t, l.Tag(ctx, exported, "localhost:5001/exported:latest"))
+	require.NoError(t, l.Tag(ctx, other, "localhost:5001/other:latest"))
+
+	archive := new(bytes.Buffer)
+	manifests, err := l.Export(ctx, archive, []string{"localhost:5001/exported:latest"}, ExportOptions{})
+	require.NoError(t, err)
+	require.Len(t, manifests, 1)
+	require.Equal(t, exported.Digest, manifests[0].Digest)
+
+	// Exporting the same content produces the same archive.
+	again := new(bytes.Buffer)
+	_, err = l.Export(ctx, again, []string{"localhost:5001/exported:latest"}, ExportOptions{})
+	require.NoError(t, err)
+	require.Equal(t, archive.Bytes(), again.Bytes())
+
+	dir := t.TempDir()
+	imported, err := NewWithContext(ctx, dir)
+	require.NoError(t, err)
+	references, err := imported.Import(ctx, archive)
+	require.NoError(t, err)
+	require.Equal(t, []string{"localhost:5001/exported:latest"}, references)
+
+	// The imported references are consistent after reloading the layout.
+	imported, err = NewWithContext(ctx, dir)
+	require.NoError(t, err)
+	desc, err := imported.Resolve(ctx, "localhost:5001/exported:latest")
+	require.NoError(t, err)
+	require.Equal(t, exported.Digest, desc.Digest)
+	missing, err := imported.Missing(ctx, "localhost:5001/exported:latest")
+	require.NoError(t, err)
+	require.Empty(t, missing)
+	_, err = imported.Resolve(ctx, "localhost:5001/other:latest")
+	var notStored *content.ErrNotStored
+	require.ErrorAs(t, err, &notStored)
+}
+
+func TestExportImportLinks(t *testing.T) {
+	ctx := context.TODO()
+	l, err := NewWithContext(ctx, t.TempDir())
+	require.NoError(t, err)
+
+	linked := pushManifest(t, l, []byte("{}"), []byte("linked"))
+	linkedRef := "localhost:5001/linked@" + linked.Digest.String()
+	require.NoError(t, l.Tag(ctx, linked, linkedRef))
+
+	linkJSON, err := json.Marshal(descriptor.Properties{
+		Link: &empspec.LinkAttributes{
+			RegistryHint:  "localhost:5001",
+			NamespaceHint: "linked",
+		},
+	})
+	require.NoError(t, err)
+	link := linked
+	link.Annotations = map[string]string{empspec.AnnotationEmporousAttributes: string(linkJSON)}
+	linksJSON, err := json.Marshal([]ocispec.Descriptor{link})
+	require.NoError(t, err)
+	manifest := ocispec.Manifest{
+		Versioned:   specs.Versioned{SchemaVersion: 2},
+		MediaType:   ocispec.MediaTypeImageManifest,
+		Config:      descriptorFor(ocispec.MediaTypeImageConfig, []byte("{}")),
+		Layers:      []ocispec.Descriptor{pushBlob(t, l, "text/plain", []byte("root"))},
+		Annotations: map[string]string{empspec.AnnotationLink: string(linksJSON)},
+	}
+	manifestJSON, err := json.Marshal(manifest)
+	require.NoError(t, err)
+	root := pushBlob(t, l, ocispec.MediaTypeImageManifest, manifestJSON)
+	require.NoError(t, l.Tag(ctx, root, "localhost:5001/root:latest"))
+
+	archive := new(bytes.Buffer)
+	manifests, err := l.Export(ctx, archive, []string{"localhost:5001/root:latest"}, ExportOptions{Links: true})
+	require.NoError(t, err)
+	require.Len(t, manifests, 2)
+	require.Equal(t, linkedRef, manifests[1].Annotations[ocispec.AnnotationRefName])
+
+	dir := t.TempDir()
+	imported, err := NewWithContext(ctx, dir)
+	require.NoError(t, err)
+	references, err := imported.Import(ctx, archive)
+	require.NoError(t, err)
+	require.Equal(t, []string{"localhost:5001/root:latest", linkedRef}, references)
+
+	// The linked collection is tagged after reloading
+	// the layout, so it can be resolved and pushed.
+	imported, err = NewWithContext(ctx, dir)
+	require.NoError(t, err)
+	desc, err := imported.Resolve(ctx, linkedRef)
+	require.NoError(t, err)
+	require.Equal(t, linked.Digest, desc.Digest)
+	missing, err := imported.Missing(ctx, linkedRef)
+	require.NoError(t, err)
+	require.Empty(t, missing)
+}
+
+func TestExportMissingContent(t *testing.T) {
+	ctx := context.TODO()
+	l, err := NewWithContext(ctx, t.TempDir())
+	require.NoError(t, err)
+
+	manifest := ocispec.Manifest{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		MediaType: ocispec.MediaTypeImageManifest,
+		Config:    pushBlob(t, l, ocispec.MediaTypeImageConfig, []byte("{}")),
+		Layers:    []ocispec.Descriptor{descriptorFor("text/plain", []byte("missing"))},
+	}
+	manifestJSON, err := json.Marshal(manifest)
+	require.NoError(t, err)
+	desc := pushBlob(t, l, ocispec.MediaTypeImageManifest, manifestJSON)
+	require.NoError(t, l.Tag(ctx, desc, "localhost:5001/sparse:latest"))
+
+	_, err = l.Export(ctx, new(bytes.Buffer), []string{"localhost:5001/sparse:latest"}, ExportOptions{})
+	var missingContent *content.ErrMissingContent
+	require.ErrorAs(t, err, &missingContent)
+	require.Equal(t, []string{manifest.Layers[0].Digest.String()}, missingContent.Digests)
+}
+
+func TestImportInvalidArchive(t *testing.T) {
+	ctx := context.TODO()
+	l, err := NewWithContext(ctx, t.TempDir())
+	require.NoError(t, err)
+
+	archive := new(bytes.Buffer)
+	tw := tar.NewWriter(archive)
+	require.NoError(t, writeTarFile(tw, indexFile, []byte(`{"schemaVersion":2}`)))
+	require.NoError(t, tw.Close())
+
+	_, err = l.Import(ctx, archive)
+	require.EqualError(t, err, "archive is not an OCI image layout: missing oci-layout")
+}
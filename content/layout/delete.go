@@ -0,0 +1,95 @@
+package layout
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+	"github.com/emporous/emporous-go/content"
+	"github.com/emporous/emporous-go/nodes/descriptor/v2"
+)
+
+// Untag removes the reference from the layout. The content
+// the reference resolved to is kept.
+func (l *Layout) Untag(_ context.Context, reference string) error {
+	if _, ok := l.resolver.LoadAndDelete(reference); !ok {
+		return &content.ErrNotStored{Reference: reference}
+	}
+	return l.SaveIndex()
+}
+
+// Delete removes the reference from the layout along with any content
+// that is no longer reachable from the remaining references. Content shared
+// with other references is kept. If successful, the deleted descriptors are returned.
+func (l *Layout) Delete(ctx context.Context, reference string) ([]ocispec.Descriptor, error) {
+	desc, err := l.Resolve(ctx, reference)
+	if err != nil {
+		return nil, err
+	}
+	if err := l.Untag(ctx, reference); err != nil {
+		return nil, err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	keep := map[string]struct{}{}
+	l.resolver.Range(func(_, value interface{}) bool {
+		l.reachable(value.(ocispec.Descriptor).Digest.String(), keep)
+		return true
+	})
+	candidates := map[string]struct{}{}
+	l.reachable(desc.Digest.String(), candidates)
+
+	var ids []string
+	for id := range candidates {
+		if _, ok := keep[id]; !ok {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	var deleted []ocispec.Descriptor
+	for _, id := range ids {
+		candidate := ocispec.Descriptor{Digest: digest.Digest(id)}
+		if node, ok := l.graph.NodeByID(id).(*v2.Node); ok {
+			candidate = node.Descriptor()
+		}
+		if err := l.deleteBlob(candidate); err != nil {
+			return deleted, err
+		}
+		l.graph.RemoveNode(id)
+		deleted = append(deleted, candidate)
+	}
+	return deleted, nil
+}
+
+// reachable adds the IDs of the nodes reachable from
+// the node with the ID in the graph to the seen set.
+func (l *Layout) reachable(id string, seen map[string]struct{}) {
+	if _, ok := seen[id]; ok {
+		return
+	}
+	seen[id] = struct{}{}
+	for _, node := range l.graph.From(id) {
+		l.reachable(node.ID(), seen)
+	}
+}
+
+// deleteBlob removes the blob for the descriptor from the layout. Blobs
+// that do not exist, such as blobs omitted from a sparse manifest, are skipped.
+func (l *Layout) deleteBlob(desc ocispec.Descriptor) error {
+	if err := desc.Digest.Validate(); err != nil {
+		return fmt.Errorf("descriptor %s: %w", desc.Digest, err)
+	}
+	path := filepath.Join(l.rootPath, "blobs", desc.Digest.Algorithm().String(), desc.Digest.Encoded())
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package layout
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+
+	"github.com/emporous/emporous-go/content"
+)
+
+func TestUntag(t *testing.T) {
+	ctx := context.TODO()
+	dir := t.TempDir()
+	l, err := NewWithContext(ctx, dir)
+	require.NoError(t, err)
+
+	manifest := pushManifest(t, l, []byte("{}"), []byte("layer"))
+	require.NoError(t, l.Tag(ctx, manifest, "localhost:5001/test:latest"))
+	require.NoError(t, l.Tag(ctx, manifest, "localhost:5001/test:v1"))
+
+	require.NoError(t, l.Untag(ctx, "localhost:5001/test:latest"))
+	var notStored *content.ErrNotStored
+	require.ErrorAs(t, l.Untag(ctx, "localhost:5001/test:latest"), &notStored)
+
+	// The index and content are consistent after reloading the layout.
+	l, err = NewWithContext(ctx, dir)
+	require.NoError(t, err)
+	_, err = l.Resolve(ctx, "localhost:5001/test:latest")
+	require.ErrorAs(t, err, &notStored)
+	desc, err := l.Resolve(ctx, "localhost:5001/test:v1")
+	require.NoError(t, err)
+	require.Equal(t, manifest.Digest, desc.Digest)
+	index, err := l.Index()
+	require.NoError(t, err)
+	require.Len(t, index.Manifests, 1)
+	require.Equal(t, "localhost:5001/test:v1", index.Manifests[0].Annotations[ocispec.AnnotationRefName])
+	exists, err := l.Exists(ctx, manifest)
+	require.NoError(t, err)
+	require.True(t, exists)
+}
+
+func TestDelete(t *testing.T) {
+	ctx := context.TODO()
+	dir := t.TempDir()
+	l, err := NewWithContext(ctx, dir)
+	require.NoError(t, err)
+
+	// Both manifests share the config blob.
+	deleted := pushManifest(t, l, []byte("{}"), []byte("deleted"))
+	kept := pushManifest(t, l, []byte("{}"), []byte("kept"))
+	require.NoError(t, l.Tag(ctx, deleted, "localhost:5001/deleted:latest"))
+	require.NoError(t, l.Tag(ctx, kept, "localhost:5001/kept:latest"))
+
+	descs, err := l.Delete(ctx, "localhost:5001/deleted:latest")
+	require.NoError(t, err)
+	require.Len(t, descs, 2)
+
+	_, err = l.Delete(ctx, "localhost:5001/deleted:latest")
+	var notStored *content.ErrNotStored
+	require.ErrorAs(t, err, &notStored)
+
+	for _, desc := range descs {
+		exists, err := l.Exists(ctx, desc)
+		require.NoError(t, err)
+		require.False(t, exists)
+		require.Nil(t, l.graph.NodeByID(desc.Digest.String()))
+	}
+
+	remaining, err := l.ResolveAll(ctx, "localhost:5001/kept:latest")
+	require.NoError(t, err)
+	require.Len(t, remaining, 3)
+
+	// The index and content are consistent after reloading the layout.
+	l, err = NewWithContext(ctx, dir)
+	require.NoError(t, err)
+	index, err := l.Index()
+	require.NoError(t, err)
+	require.Len(t, index.Manifests, 1)
+	require.Equal(t, kept.Digest, index.Manifests[0].Digest)
+}
+
+func pushManifest(t *testing.T, l *Layout, config, layer []byte) ocispec.Descriptor {
+	configDesc := descriptorFor(ocispec.MediaTypeImageConfig, config)
+	if exists, err := l.Exists(context.TODO(), configDesc); err == nil && !exists {
+		pushBlob(t, l, ocispec.MediaTypeImageConfig, config)
+	}
+	manifest := ocispec.Manifest{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		MediaType: ocispec.MediaTypeImageManifest,
+		Config:    configDesc,
+		Layers:    []ocispec.Descriptor{pushBlob(t, l, "text/plain", layer)},
+	}
+	manifestJSON, err := json.Marshal(manifest)
+	require.NoError(t, err)
+	return pushBlob(t, l, ocispec.MediaTypeImageManifest, manifestJSON)
+}
@@ -13,6 +13,7 @@ import (
 	"sync"
 
 	empspec "github.com/emporous/collection-spec/specs-go/v1alpha1"
+	"github.com/opencontainers/go-digest"
 	"github.com/opencontainers/image-spec/specs-go"
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	orascontent "oras.land/oras-go/v2/content"
@@ -31,6 +32,7 @@ var (
 	_ content.Store          = &Layout{}
 	_ content.GraphStore     = &Layout{}
 	_ content.AttributeStore = &Layout{}
+	_ content.BlobLocator    = &Layout{}
 )
 
 const indexFile = "index.json"
@@ -95,6 +97,23 @@ func (l *Layout) Exists(ctx context.Context, desc ocispec.Descriptor) (bool, err
 	return l.internal.Exists(ctx, desc)
 }
 
+// BlobPath returns the path of the file storing the blob described by the descriptor.
+// The file must not be modified.
+func (l *Layout) BlobPath(desc ocispec.Descriptor) (string, error) {
+	if err := desc.Digest.Validate(); err != nil {
+		return "", err
+	}
+	path := filepath.Join(l.rootPath, "blobs", desc.Digest.Algorithm().String(), desc.Digest.Encoded())
+	info, err := os.Stat(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist) || (err == nil && info.Size() != desc.Size):
+		return "", fmt.Errorf("%s: %w", desc.Digest, errdef.ErrNotFound)
+	case err != nil:
+		return "", err
+	}
+	return path, nil
+}
+
 // Resolve resolves a reference to a descriptor.
 func (l *Layout) Resolve(_ context.Context, reference string) (ocispec.Descriptor, error) {
 	desc, ok := l.resolver.Load(reference)
@@ -251,7 +270,7 @@ func (l *Layout) AttributeSchema(ctx context.Context, reference string) (ocispec
 // A reference should be either a valid tag (e.g. "latest"),
 // or a digest matching the descriptor (e.g. "@sha256:abc123").
 func (l *Layout) Tag(ctx context.Context, desc ocispec.Descriptor, reference string) error {
-	if err := validateReference(reference); err != nil {
+	if err := validateReference(reference, desc); err != nil {
 		return err
 	}
 
@@ -263,10 +282,14 @@ func (l *Layout) Tag(ctx context.Context, desc ocispec.Descriptor, reference str
 		return fmt.Errorf("%s: %s: %w", desc.Digest, desc.MediaType, errdef.ErrNotFound)
 	}
 
-	if desc.Annotations == nil {
-		desc.Annotations = map[string]string{}
+	// Copy the annotations so descriptors stored
+	// under other references are not modified.
+	annotations := make(map[string]string, len(desc.Annotations)+1)
+	for key, value := range desc.Annotations {
+		annotations[key] = value
 	}
-	desc.Annotations[ocispec.AnnotationRefName] = reference
+	annotations[ocispec.AnnotationRefName] = reference
+	desc.Annotations = annotations
 
 	l.resolver.Store(reference, desc)
 
@@ -284,10 +307,12 @@ func (l *Layout) SaveIndex() error {
 	var descs []ocispec.Descriptor
 	l.resolver.Range(func(key, value interface{}) bool {
 		desc := value.(ocispec.Descriptor)
-		if desc.Annotations == nil {
-			desc.Annotations = map[string]string{}
+		annotations := make(map[string]string, len(desc.Annotations)+1)
+		for k, v := range desc.Annotations {
+			annotations[k] = v
 		}
-		desc.Annotations[ocispec.AnnotationRefName] = key.(string)
+		annotations[ocispec.AnnotationRefName] = key.(string)
+		desc.Annotations = annotations
 		descs = append(descs, desc)
 		return true
 	})
@@ -394,15 +419,18 @@ func (l *Layout) validateOCILayoutFile() error {
 	return nil
 }
 
-// validateReference ensures the build reference
-// contains a tag component.
-func validateReference(name string) error {
+// validateReference ensures the build reference contains a
+// tag component or the digest of the tagged descriptor.
+func validateReference(name string, desc ocispec.Descriptor) error {
 	parts := strings.SplitN(name, "/", 2)
 	if len(parts) == 1 {
 		return fmt.Errorf("reference %q: missing repository", name)
 	}
 	path := parts[1]
 	if index := strings.Index(path, "@"); index != -1 {
+		if dgst, err := digest.Parse(path[index+1:]); err == nil && dgst == desc.Digest {
+			return nil
+		}
 		return fmt.Errorf("%q: %w", name, errdef.ErrInvalidReference)
 	} else if index := strings.Index(path, ":"); index != -1 {
 		// tag found
@@ -1,7 +1,9 @@
 package layout
 
 import (
+	"bytes"
 	"context"
+	"fmt"
 	"io/fs"
 	"io/ioutil"
 	"os"
@@ -10,6 +12,7 @@ import (
 	"testing"
 
 	empspec "github.com/emporous/collection-spec/specs-go/v1alpha1"
+	"github.com/opencontainers/go-digest"
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/stretchr/testify/require"
 
@@ -127,6 +130,21 @@ func TestTag(t *testing.T) {
 	require.Equal(t, "test/test:tag", refName)
 }
 
+func TestTagDigest(t *testing.T) {
+	l, err := NewWithContext(context.TODO(), t.TempDir())
+	require.NoError(t, err)
+
+	desc := pushBlob(t, l, "text/plain", []byte("tagged"))
+	reference := "test/repo@" + desc.Digest.String()
+	require.NoError(t, l.Tag(context.TODO(), desc, reference))
+	resolved, err := l.Resolve(context.TODO(), reference)
+	require.NoError(t, err)
+	require.Equal(t, desc.Digest, resolved.Digest)
+
+	other := pushBlob(t, l, "text/plain", []byte("other"))
+	require.EqualError(t, l.Tag(context.TODO(), other, reference), fmt.Sprintf("%q: invalid reference", reference))
+}
+
 func TestSaveIndex(t *testing.T) {
 	cacheDir := t.TempDir()
 
@@ -192,6 +210,58 @@ func TestResolve(t *testing.T) {
 	}
 }
 
+func TestBlobPath(t *testing.T) {
+	cacheDir := t.TempDir()
+	l, err := NewWithContext(context.TODO(), cacheDir)
+	require.NoError(t, err)
+
+	data := []byte("Hello World!\n")
+	stored := ocispec.Descriptor{MediaType: "text/plain", Digest: digest.FromBytes(data), Size: int64(len(data))}
+	require.NoError(t, l.Push(context.TODO(), stored, bytes.NewReader(data)))
+
+	type spec struct {
+		name     string
+		desc     ocispec.Descriptor
+		expPath  string
+		expError string
+	}
+
+	cases := []spec{
+		{
+			name:    "Success/BlobExists",
+			desc:    stored,
+			expPath: filepath.Join(cacheDir, "blobs", "sha256", stored.Digest.Encoded()),
+		},
+		{
+			name:     "Failure/BlobDoesNotExist",
+			desc:     ocispec.Descriptor{MediaType: "text/plain", Digest: digest.FromBytes([]byte("missing")), Size: 7},
+			expError: "sha256:ffa63583dfa6706b87d284b86b0d693a161e4840aad2c5cf6b5d27c3b9621f7d: not found",
+		},
+		{
+			name:     "Failure/SizeMismatch",
+			desc:     ocispec.Descriptor{MediaType: "text/plain", Digest: stored.Digest, Size: 1},
+			expError: stored.Digest.String() + ": not found",
+		},
+		{
+			name:     "Failure/InvalidDigest",
+			desc:     ocispec.Descriptor{MediaType: "text/plain", Digest: "sha256:invalid", Size: 1},
+			expError: "invalid checksum digest length",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			path, err := l.BlobPath(c.desc)
+			if c.expError != "" {
+				require.EqualError(t, err, c.expError)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, c.expPath, path)
+			}
+		})
+	}
+}
+
 func TestLoadIndex(t *testing.T) {
 	cacheDir := "testdata/valid"
 	ctx := context.TODO()
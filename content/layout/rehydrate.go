@@ -0,0 +1,95 @@
+package layout
+
+import (
+	"context"
+	"fmt"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	orascontent "oras.land/oras-go/v2/content"
+
+	"github.com/emporous/emporous-go/nodes/descriptor/v2"
+)
+
+// Missing returns the descriptors referenced by the manifests stored under the reference
+// that are not stored in the layout. Linked collections are not included. Content is
+// missing when a filtered pull leaves a sparse manifest in the layout.
+func (l *Layout) Missing(ctx context.Context, reference string) ([]ocispec.Descriptor, error) {
+	return l.walkMissing(ctx, reference, nil)
+}
+
+// Rehydrate fetches the content missing from the manifests stored under the reference
+// using the fetcher and stores it in the layout. Fetched content is verified against the
+// descriptor. If successful, the descriptors that were stored are returned.
+func (l *Layout) Rehydrate(ctx context.Context, reference string, fetcher orascontent.Fetcher) ([]ocispec.Descriptor, error) {
+	if fetcher == nil {
+		return nil, fmt.Errorf("reference %s: fetcher must be set to rehydrate content", reference)
+	}
+	return l.walkMissing(ctx, reference, fetcher)
+}
+
+// walkMissing walks the content stored under the reference and returns the descriptors
+// that do not exist in the layout. If the fetcher is set, missing content is fetched and
+// stored so the walk can continue into manifests that were missing.
+func (l *Layout) walkMissing(ctx context.Context, reference string, fetcher orascontent.Fetcher) ([]ocispec.Descriptor, error) {
+	root, err := l.Resolve(ctx, reference)
+	if err != nil {
+		return nil, err
+	}
+
+	var missing []ocispec.Descriptor
+	seen := map[string]struct{}{}
+	stack := []ocispec.Descriptor{root}
+	for len(stack) != 0 {
+		desc := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if _, ok := seen[desc.Digest.String()]; ok {
+			continue
+		}
+		seen[desc.Digest.String()] = struct{}{}
+
+		exists, err := l.Exists(ctx, desc)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			missing = append(missing, desc)
+			if fetcher == nil {
+				continue
+			}
+			if err := l.fetchMissing(ctx, fetcher, desc); err != nil {
+				return missing, fmt.Errorf("reference %s: %w", reference, err)
+			}
+		}
+
+		successors, err := orascontent.Successors(ctx, l, desc)
+		if err != nil {
+			return missing, err
+		}
+		for _, s := range successors {
+			node, err := v2.NewNode(s.Digest.String(), s)
+			if err != nil {
+				return missing, err
+			}
+			// Linked collections are stored under their own reference.
+			if node.Properties != nil && node.Properties.IsALink() {
+				continue
+			}
+			stack = append(stack, s)
+		}
+	}
+	return missing, nil
+}
+
+// fetchMissing fetches the content for the descriptor and stores it.
+func (l *Layout) fetchMissing(ctx context.Context, fetcher orascontent.Fetcher, desc ocispec.Descriptor) error {
+	rc, err := fetcher.Fetch(ctx, desc)
+	if err != nil {
+		return fmt.Errorf("error fetching %s: %w", desc.Digest, err)
+	}
+	defer rc.Close()
+	// The underlying storage verifies the size and digest of the content.
+	if err := l.Push(ctx, desc, rc); err != nil {
+		return fmt.Errorf("error storing %s: %w", desc.Digest, err)
+	}
+	return nil
+}
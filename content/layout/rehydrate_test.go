@@ -0,0 +1,90 @@
+package layout
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/opencontainers/go-digest"
+	"github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+	"oras.land/oras-go/v2/content/memory"
+)
+
+func TestRehydrate(t *testing.T) {
+	ctx := context.TODO()
+	reference := "localhost:5001/sparse:latest"
+
+	type spec struct {
+		name       string
+		fetchLayer bool
+		expError   string
+	}
+
+	cases := []spec{
+		{
+			name:       "Success/MissingLayerFetched",
+			fetchLayer: true,
+		},
+		{
+			name:     "Failure/MissingFromSource",
+			expError: "reference localhost:5001/sparse:latest: error fetching",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			l, err := NewWithContext(ctx, t.TempDir())
+			require.NoError(t, err)
+			source := memory.New()
+
+			config := pushBlob(t, l, ocispec.MediaTypeImageConfig, []byte("{}"))
+			layer := descriptorFor("text/plain", []byte("sparse"))
+			if c.fetchLayer {
+				require.NoError(t, source.Push(ctx, layer, bytes.NewReader([]byte("sparse"))))
+			}
+			manifest := ocispec.Manifest{
+				Versioned: specs.Versioned{SchemaVersion: 2},
+				MediaType: ocispec.MediaTypeImageManifest,
+				Config:    config,
+				Layers:    []ocispec.Descriptor{layer},
+			}
+			manifestJSON, err := json.Marshal(manifest)
+			require.NoError(t, err)
+			manifestDesc := pushBlob(t, l, ocispec.MediaTypeImageManifest, manifestJSON)
+			require.NoError(t, l.Tag(ctx, manifestDesc, reference))
+
+			missing, err := l.Missing(ctx, reference)
+			require.NoError(t, err)
+			require.Equal(t, []ocispec.Descriptor{layer}, missing)
+
+			fetched, err := l.Rehydrate(ctx, reference, source)
+			if c.expError != "" {
+				require.ErrorContains(t, err, c.expError)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, []ocispec.Descriptor{layer}, fetched)
+
+			missing, err = l.Missing(ctx, reference)
+			require.NoError(t, err)
+			require.Empty(t, missing)
+		})
+	}
+}
+
+func descriptorFor(mediaType string, data []byte) ocispec.Descriptor {
+	return ocispec.Descriptor{
+		MediaType: mediaType,
+		Digest:    digest.FromBytes(data),
+		Size:      int64(len(data)),
+	}
+}
+
+func pushBlob(t *testing.T, l *Layout, mediaType string, data []byte) ocispec.Descriptor {
+	desc := descriptorFor(mediaType, data)
+	require.NoError(t, l.Push(context.TODO(), desc, bytes.NewReader(data)))
+	return desc
+}
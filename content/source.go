@@ -0,0 +1,18 @@
+package content
+
+import "context"
+
+// sourceKey is the context key for the source reference.
+type sourceKey struct{}
+
+// WithSource returns a context that records the reference of
+// the collection that content pushed with the context is copied from.
+func WithSource(ctx context.Context, reference string) context.Context {
+	return context.WithValue(ctx, sourceKey{}, reference)
+}
+
+// SourceFromContext returns the reference recorded with WithSource.
+func SourceFromContext(ctx context.Context) (string, bool) {
+	reference, ok := ctx.Value(sourceKey{}).(string)
+	return reference, ok
+}
@@ -39,3 +39,25 @@ type GraphStore interface {
 	// PredecessorFinder returns the nodes directly pointing to the current node.
 	content.PredecessorFinder
 }
+
+// FileStore defines the methods for a Store that writes
+// titled content to files in a local directory.
+type FileStore interface {
+	Store
+	// Dir returns the directory files are written to.
+	Dir() string
+	// Path returns the path relative to Dir that the titled
+	// content described by the descriptor is written to.
+	Path(ocispec.Descriptor) (string, bool)
+	// Shared returns whether the file for the descriptor is a hard link
+	// to a stored blob. Shared files must not be modified in place.
+	Shared(ocispec.Descriptor) bool
+}
+
+// BlobLocator defines the methods for a Store that
+// keeps blobs in files that can be linked to.
+type BlobLocator interface {
+	// BlobPath returns the path of the file storing the blob
+	// described by the descriptor.
+	BlobPath(ocispec.Descriptor) (string, error)
+}
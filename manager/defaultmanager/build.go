@@ -3,10 +3,11 @@ package defaultmanager
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"os"
-	"regexp"
+	"sort"
 	"strings"
 
 	empspec "github.com/emporous/collection-spec/specs-go/v1alpha1"
@@ -17,18 +18,144 @@ import (
 	"github.com/emporous/emporous-go/attributes"
 	load "github.com/emporous/emporous-go/config"
 	"github.com/emporous/emporous-go/content"
+	"github.com/emporous/emporous-go/manager"
 	"github.com/emporous/emporous-go/model"
 	"github.com/emporous/emporous-go/nodes/descriptor"
 	"github.com/emporous/emporous-go/nodes/descriptor/v2"
 	"github.com/emporous/emporous-go/registryclient"
+	"github.com/emporous/emporous-go/sbom"
 	"github.com/emporous/emporous-go/schema"
 	"github.com/emporous/emporous-go/util/workspace"
 )
 
 // Build builds collection from input and store it in the underlying content store.
-// If successful, the root descriptor is returned.
+// If successful, the root descriptor is returned. With deferred validation, the root
+// descriptor of a collection with invalid attributes is returned with an
+// *manager.ErrSchemaValidation error.
 func (d DefaultManager) Build(ctx context.Context, space workspace.Workspace, config clientapi.DataSetConfiguration, reference string, client registryclient.Client) (string, error) {
+	var invalid *[]manager.InvalidFile
+	if d.deferValidation {
+		invalid = &[]manager.InvalidFile{}
+	}
+	if len(config.Collection.Variants) != 0 {
+		if err := d.buildVariants(ctx, space, config, reference, client, invalid); err != nil {
+			return "", err
+		}
+	} else {
+		if _, _, err := d.buildManifest(ctx, space, config, reference, client, invalid); err != nil {
+			return "", err
+		}
+	}
+
+	desc, err := client.Save(ctx, reference, d.store)
+	if err != nil {
+		return "", fmt.Errorf("client save error for reference %s: %v", reference, err)
+	}
+	d.logger.Infof("Artifact %s built with reference name %s\n", desc.Digest, reference)
+
+	if invalid != nil && len(*invalid) != 0 {
+		files := *invalid
+		sort.SliceStable(files, func(i, j int) bool {
+			if files[i].Variant != files[j].Variant {
+				return files[i].Variant < files[j].Variant
+			}
+			return files[i].File < files[j].File
+		})
+		return desc.Digest.String(), &manager.ErrSchemaValidation{Schema: config.Collection.SchemaAddress, Files: files}
+	}
+	return desc.Digest.String(), nil
+}
+
+// buildVariants builds a manifest for each variant in the dataset configuration and
+// references the manifests from an index stored under the reference.
+func (d DefaultManager) buildVariants(ctx context.Context, space workspace.Workspace, config clientapi.DataSetConfiguration, reference string, client registryclient.Client, invalid *[]manager.InvalidFile) error {
+	names := map[string]struct{}{}
+	var manifests []ocispec.Descriptor
+	for _, variant := range config.Collection.Variants {
+		if variant.Name == "" {
+			return errors.New("variant name must be set")
+		}
+		if _, exists := names[variant.Name]; exists {
+			return fmt.Errorf("variant %q: duplicate name", variant.Name)
+		}
+		names[variant.Name] = struct{}{}
+
+		if variant.Workspace == "" {
+			return fmt.Errorf("variant %q: workspace must be set", variant.Name)
+		}
+		// Check the workspace exists before creating the workspace
+		// to avoid creating directories in the root workspace.
+		info, err := space.Stat(variant.Workspace)
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+			return fmt.Errorf("variant %q: workspace %s does not exist", variant.Name, variant.Workspace)
+		case err != nil:
+			return fmt.Errorf("variant %q: %w", variant.Name, err)
+		}
+		if !info.IsDir() {
+			return fmt.Errorf("variant %q: workspace %s is not a directory", variant.Name, variant.Workspace)
+		}
+		variantSpace, err := space.NewDirectory(variant.Workspace)
+		if err != nil {
+			return fmt.Errorf("variant %q: %w", variant.Name, err)
+		}
+
+		// The variant manifest is tagged with an internal reference in the
+		// artifact store and is only saved as a successor of the index.
+		d.logger.Infof("Building variant %s", variant.Name)
+		variantRef := fmt.Sprintf("%s-%s", reference, variant.Name)
+		var start int
+		if invalid != nil {
+			start = len(*invalid)
+		}
+		manifestDesc, schemaID, err := d.buildManifest(ctx, variantSpace, config, variantRef, client, invalid)
+		if err != nil {
+			return fmt.Errorf("variant %q: %w", variant.Name, err)
+		}
+		if invalid != nil {
+			for i := start; i < len(*invalid); i++ {
+				(*invalid)[i].Variant = variant.Name
+			}
+		}
+
+		platform := variant.Platform
+		if platform == "" {
+			platform = config.Collection.Components.Platform
+		}
+		if platform != "" {
+			p, err := descriptor.ParsePlatform(platform)
+			if err != nil {
+				return fmt.Errorf("variant %q: %w", variant.Name, err)
+			}
+			manifestDesc.Platform = &p
+		}
+
+		set, err := load.ConvertToModel(variant.Attributes)
+		if err != nil {
+			return fmt.Errorf("variant %q: %w", variant.Name, err)
+		}
+		prop := descriptor.Properties{Others: map[string]model.AttributeSet{schemaID: set}}
+		propsJSON, err := prop.MarshalJSON()
+		if err != nil {
+			return err
+		}
+		manifestDesc.Annotations = map[string]string{
+			descriptor.AnnotationVariant:         variant.Name,
+			empspec.AnnotationEmporousAttributes: string(propsJSON),
+		}
+		manifests = append(manifests, manifestDesc)
+	}
+
+	_, err := client.AddIndex(ctx, reference, nil, manifests...)
+	return err
+}
+
+// buildManifest builds a collection manifest from the workspace and tags it with the reference in
+// the client artifact store. The manifest descriptor and the collection schema ID are returned.
+// If invalid is not nil, schema validation errors are recorded in invalid.
+func (d DefaultManager) buildManifest(ctx context.Context, space workspace.Workspace, config clientapi.DataSetConfiguration, reference string, client registryclient.Client, invalid *[]manager.InvalidFile) (ocispec.Descriptor, string, error) {
 	var files []string
+	var sidecars []string
 	err := space.Walk(func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return fmt.Errorf("traversing %s: %v", path, err)
@@ -38,109 +165,143 @@ func (d DefaultManager) Build(ctx context.Context, space workspace.Workspace, co
 		}
 
 		if info.Mode().IsRegular() {
+			// Sidecar files configure the collection
+			// and are not added as content.
+			if isSidecar(path) {
+				sidecars = append(sidecars, path)
+				return nil
+			}
 			files = append(files, path)
 		}
 		return nil
 	})
 	if err != nil {
-		return "", err
+		return ocispec.Descriptor{}, "", err
 	}
 
 	if len(files) == 0 {
-		return "", fmt.Errorf("path %q empty workspace", space.Path("."))
+		return ocispec.Descriptor{}, "", fmt.Errorf("path %q empty workspace", space.Path("."))
 	}
 
-	var sets []model.AttributeSet
-	regexpByFilename := map[string]*regexp.Regexp{}
-	fileInfoByName := map[string]fileInformation{}
-	for _, file := range config.Collection.Files {
-		// Process each key into a regular expression and store it.
+	// Keep file processing order deterministic
+	sort.Strings(files)
 
-		// If the config has a grouping declared, make a valid regex.
-		var expression string
-		if strings.Contains(file.File, "*") && !strings.Contains(file.File, ".*") {
-			expression = strings.Replace(file.File, "*", ".*", -1)
-		} else {
-			expression = strings.Replace(file.File, file.File, "^"+file.File+"$", -1)
+	dirAttributes, err := loadDirectoryAttributes(ctx, space, sidecars)
+	if err != nil {
+		return ocispec.Descriptor{}, "", err
+	}
+
+	var sbomDoc sbom.Document
+	if config.Collection.SBOM != "" {
+		sbomDoc, err = readSBOM(config.Collection.SBOM)
+		if err != nil {
+			return ocispec.Descriptor{}, "", err
 		}
+		d.logger.Infof("Importing component information from %s SBOM %s", sbomDoc.Format, config.Collection.SBOM)
+	}
+	sbomIndex := sbom.NewIndex(sbomDoc)
 
-		nameSearch, err := regexp.Compile(expression)
+	var sets []model.AttributeSet
+	// File information is stored in configuration order
+	// to keep attribute merging deterministic.
+	var fileInfos []fileInformation
+	for _, file := range config.Collection.Files {
+		// Process each key into a file pattern and store it.
+		nameSearch, err := load.CompilePattern(file.File)
 		if err != nil {
-			return "", err
+			return ocispec.Descriptor{}, "", err
 		}
-		regexpByFilename[file.File] = nameSearch
 
 		set, err := load.ConvertToModel(file.Attributes)
 		if err != nil {
-			return "", err
+			return ocispec.Descriptor{}, "", err
 		}
 		sets = append(sets, set)
 
 		fileInfo := fileInformation{
 			AttributeSet: set,
 			File:         file.FileInfo,
+			hasFileInfo:  file.HasFileInfo(),
+			pattern:      nameSearch,
 		}
-
-		fileInfoByName[file.File] = fileInfo
+		fileInfos = append(fileInfos, fileInfo)
 	}
 
 	// Merge the sets to ensure the dataset configuration
 	// meet the schema require.
 	mergedSet, err := attributes.Merge(sets...)
 	if err != nil {
-		return "", fmt.Errorf("failed to merge attributes: %w", err)
+		return ocispec.Descriptor{}, "", fmt.Errorf("failed to merge attributes: %w", err)
 	}
 
 	// If a schema is present, pull it and do the validation before
 	// processing the files to get quick feedback to the user. Also, collection the schema ID
 	// to place in the descriptor properties.
 	schemaID := schema.UnknownSchemaID
+	var schemaDoc *schema.Schema
 	if config.Collection.SchemaAddress != "" {
 		d.logger.Infof("Validating dataset configuration against schema %s", config.Collection.SchemaAddress)
 
 		_, _, err := client.Pull(ctx, config.Collection.SchemaAddress, d.store)
 		if err != nil {
-			return "", fmt.Errorf("error configuring client: %v", err)
+			return ocispec.Descriptor{}, "", fmt.Errorf("error configuring client: %v", err)
 		}
 
-		schemaDoc, detectedSchemaID, err := fetchJSONSchema(ctx, config.Collection.SchemaAddress, d.store)
+		fetchedSchema, detectedSchemaID, err := fetchJSONSchema(ctx, config.Collection.SchemaAddress, d.store)
 		if err != nil {
-			return "", err
+			return ocispec.Descriptor{}, "", err
 		}
+		schemaDoc = &fetchedSchema
 
 		if detectedSchemaID != "" {
 			schemaID = detectedSchemaID
 		}
 
 		valid, err := schemaDoc.Validate(mergedSet)
-		if err != nil {
-			return "", fmt.Errorf("schema validation error: %w", err)
+		switch {
+		case err != nil:
+			err = fmt.Errorf("schema validation error: %w", err)
+		case !valid:
+			err = fmt.Errorf("attributes are not valid for schema %s", config.Collection.SchemaAddress)
 		}
-		if !valid {
-			return "", fmt.Errorf("attributes are not valid for schema %s", config.Collection.SchemaAddress)
+		if err := recordInvalid(invalid, "", err); err != nil {
+			return ocispec.Descriptor{}, "", err
 		}
 	}
 
-	// To allow the files to be loaded relative to the render
-	// workspace, change to the render directory. This is required
-	// to get path correct in the description annotations.
-	cwd, err := os.Getwd()
+	descs, err := client.AddWorkspaceFiles(ctx, "", space, files...)
 	if err != nil {
-		return "", err
+		return ocispec.Descriptor{}, "", err
 	}
 
-	if err := os.Chdir(space.Path()); err != nil {
-		return "", err
+	extractedByLocation, err := d.extractAttributes(ctx, space, config.Collection.Extractors, schemaID, files)
+	if err != nil {
+		return ocispec.Descriptor{}, "", err
 	}
-	defer func() {
-		if err := os.Chdir(cwd); err != nil {
-			d.logger.Errorf("%v", err)
-		}
-	}()
 
-	descs, err := client.AddFiles(ctx, "", files...)
-	if err != nil {
-		return "", err
+	// Validate extracted attributes stored under the collection
+	// schema with the attributes from the dataset configuration.
+	if schemaDoc != nil {
+		for location, extracted := range extractedByLocation {
+			set, ok := extracted[schemaID]
+			if !ok {
+				continue
+			}
+			withConfig, err := attributes.Merge(set, mergedSet)
+			if err != nil {
+				return ocispec.Descriptor{}, "", fmt.Errorf("file %s: failed to merge extracted attributes: %w", location, err)
+			}
+			valid, err := schemaDoc.Validate(withConfig)
+			switch {
+			case err != nil:
+				err = fmt.Errorf("file %s: schema validation error: %w", location, err)
+			case !valid:
+				err = fmt.Errorf("file %s: extracted attributes are not valid for schema %s", location, config.Collection.SchemaAddress)
+			}
+			if err := recordInvalid(invalid, location, err); err != nil {
+				return ocispec.Descriptor{}, "", err
+			}
+		}
 	}
 
 	// Create nodes and update node properties
@@ -154,7 +315,7 @@ func (d DefaultManager) Build(ctx context.Context, space workspace.Workspace, co
 		// the digest may not be.
 		node, err := v2.NewNode(location, desc)
 		if err != nil {
-			return "", err
+			return ocispec.Descriptor{}, "", err
 		}
 		node.Location = location
 		nodes = append(nodes, *node)
@@ -165,13 +326,20 @@ func (d DefaultManager) Build(ctx context.Context, space workspace.Workspace, co
 			return nil
 		}
 
+		// Attributes from sidecar files are applied first and
+		// overridden by attributes from the dataset configuration.
+		merged, sources := dirAttributes.resolve(node.Location)
+		hasSidecarAttributes := len(merged) != 0
+
 		var sets []model.AttributeSet
 		var fileConfig []empspec.File
-		for file, fileInfo := range fileInfoByName {
-			nameSearch := regexpByFilename[file]
-			if nameSearch.Match([]byte(node.Location)) {
+		for _, fileInfo := range fileInfos {
+			if fileInfo.pattern.Match(node.Location) {
 				if fileInfo.HasAttributes() {
 					sets = append(sets, fileInfo.AttributeSet)
+					for key := range fileInfo.AttributeSet.List() {
+						sources[key] = fmt.Sprintf("dataset configuration pattern %q", fileInfo.pattern)
+					}
 				}
 				if fileInfo.HasFileInfo() {
 					fileConfig = append(fileConfig, fileInfo.File)
@@ -179,27 +347,69 @@ func (d DefaultManager) Build(ctx context.Context, space workspace.Workspace, co
 			}
 		}
 
+		if component, ok := sbomIndex.Match(node.Location, node.Descriptor().Digest); ok {
+			node.Properties.Descriptor = &empspec.DescriptorAttributes{Component: component}
+		}
+
 		switch {
 		case len(fileConfig) == 1:
 			node.Properties.File = &fileConfig[0]
 		case len(fileConfig) > 1:
 			return fmt.Errorf("file %q: more than one match for file configuration", node.Location)
 		}
+		if d.normalizeFileInfo {
+			info, err := normalizedFileInfo(space, node.Location, node.Properties.File)
+			if err != nil {
+				return err
+			}
+			node.Properties.File = info
+		}
 
-		merged, err := attributes.Merge(sets...)
+		configSet, err := attributes.Merge(sets...)
 		if err != nil {
 			return err
 		}
+		for key, value := range configSet.List() {
+			merged[key] = value
+		}
+
+		if hasSidecarAttributes && schemaDoc != nil {
+			valid, err := schemaDoc.Validate(merged)
+			switch {
+			case err != nil:
+				err = fmt.Errorf("file %s: schema validation error: %w", node.Location, err)
+			case !valid:
+				err = fmt.Errorf("file %s: attributes are not valid for schema %s", node.Location, config.Collection.SchemaAddress)
+			}
+			if err := recordInvalid(invalid, node.Location, err); err != nil {
+				return err
+			}
+		}
+
 		if err := node.Properties.Merge(map[string]model.AttributeSet{schemaID: merged}); err != nil {
 			return fmt.Errorf("file %s: %w", node.Location, err)
 		}
+		// User provided attributes take precedence over extracted attributes.
+		if err := node.Properties.Merge(extractedByLocation[node.Location]); err != nil {
+			return fmt.Errorf("file %s: %w", node.Location, err)
+		}
+
+		d.traceAttributes(node.Location, node.Properties.Others, sources)
 		return nil
 	}
 
 	// Add user provided attributes to node properties
 	descs, err = v2.UpdateDescriptors(nodes, updateFN)
 	if err != nil {
-		return "", err
+		return ocispec.Descriptor{}, "", err
+	}
+	for _, desc := range descs {
+		if err := canonicalizeAnnotations(desc.Annotations); err != nil {
+			return ocispec.Descriptor{}, "", err
+		}
+	}
+	for _, entry := range sbomIndex.Unmatched() {
+		d.logger.Warnf("SBOM entry %s does not match any file in the workspace", entry)
 	}
 
 	// Store the DataSetConfiguration file in the manifest config of the OCI artifact for
@@ -207,11 +417,11 @@ func (d DefaultManager) Build(ctx context.Context, space workspace.Workspace, co
 	// Artifacts don't have configs. This will have to go with the regular descriptors.
 	configJSON, err := json.Marshal(config)
 	if err != nil {
-		return "", err
+		return ocispec.Descriptor{}, "", err
 	}
 	configDesc, err := client.AddContent(ctx, empspec.MediaTypeConfiguration, configJSON, nil)
 	if err != nil {
-		return "", err
+		return ocispec.Descriptor{}, "", err
 	}
 
 	// Build index manifest
@@ -219,33 +429,36 @@ func (d DefaultManager) Build(ctx context.Context, space workspace.Workspace, co
 	if len(config.Collection.LinkedCollections) != 0 {
 		aggregateDesc, err := d.addLinks(ctx, client, config.Collection.LinkedCollections)
 		if err != nil {
-			return "", err
+			return ocispec.Descriptor{}, "", err
 		}
 		aggregateDescJSON, err := json.Marshal(aggregateDesc)
 		if err != nil {
-			return "", err
+			return ocispec.Descriptor{}, "", err
 		}
 		manifestAnnotations[empspec.AnnotationLink] = string(aggregateDescJSON)
 	}
 
 	var prop descriptor.Properties
+	component := empspec.Component{
+		Name:      config.Collection.Components.Name,
+		Version:   config.Collection.Components.Version,
+		Type:      config.Collection.Components.Type,
+		FoundBy:   config.Collection.Components.FoundBy,
+		Locations: config.Collection.Components.Locations,
+		Licenses:  config.Collection.Components.Licenses,
+		Language:  config.Collection.Components.Language,
+		CPEs:      config.Collection.Components.CPEs,
+		PURL:      config.Collection.Components.PURL,
+	}
+	// Component information from the dataset configuration
+	// takes precedence over the SBOM subject.
+	if sbomDoc.Subject != nil {
+		fillComponent(&component, *sbomDoc.Subject)
+	}
 	// Add user specified component information to the manifest, if applicable.
-	if config.Collection.Components.Name != "" {
+	if component.Name != "" {
 		d.logger.Debugf("Component information detected. Adding inder core-descriptor schema.")
-		componentAttr := &empspec.DescriptorAttributes{
-			Component: empspec.Component{
-				Name:      config.Collection.Components.Name,
-				Version:   config.Collection.Components.Version,
-				Type:      config.Collection.Components.Type,
-				FoundBy:   config.Collection.Components.FoundBy,
-				Locations: config.Collection.Components.Locations,
-				Licenses:  config.Collection.Components.Licenses,
-				Language:  config.Collection.Components.Language,
-				CPEs:      config.Collection.Components.CPEs,
-				PURL:      config.Collection.Components.PURL,
-			},
-		}
-		prop.Descriptor = componentAttr
+		prop.Descriptor = &empspec.DescriptorAttributes{Component: component}
 	}
 
 	// Add user specified runtime information to the manifest, if applicable.
@@ -256,22 +469,18 @@ func (d DefaultManager) Build(ctx context.Context, space workspace.Workspace, co
 
 	propsJSON, err := prop.MarshalJSON()
 	if err != nil {
-		return "", err
+		return ocispec.Descriptor{}, "", err
 	}
 	manifestAnnotations[empspec.AnnotationEmporousAttributes] = string(propsJSON)
-
-	_, err = client.AddManifest(ctx, reference, configDesc, manifestAnnotations, descs...)
-	if err != nil {
-		return "", err
+	if err := canonicalizeAnnotations(manifestAnnotations); err != nil {
+		return ocispec.Descriptor{}, "", err
 	}
 
-	desc, err := client.Save(ctx, reference, d.store)
+	manifestDesc, err := client.AddManifest(ctx, reference, configDesc, manifestAnnotations, descs...)
 	if err != nil {
-		return "", fmt.Errorf("client save error for reference %s: %v", reference, err)
+		return ocispec.Descriptor{}, "", err
 	}
-	d.logger.Infof("Artifact %s built with reference name %s\n", desc.Digest, reference)
-
-	return desc.Digest.String(), nil
+	return manifestDesc, schemaID, nil
 }
 
 func (d DefaultManager) addLinks(ctx context.Context, client registryclient.Client, links []string) ([]ocispec.Descriptor, error) {
@@ -343,11 +552,68 @@ func fetchJSONSchema(ctx context.Context, schemaAddress string, store content.At
 	return sc, schemaID, err
 }
 
+// canonicalizeAnnotations re-encodes JSON annotation values with sorted keys and no
+// insignificant whitespace, so the values do not depend on how they were produced.
+func canonicalizeAnnotations(annotations map[string]string) error {
+	for _, key := range []string{empspec.AnnotationEmporousAttributes, empspec.AnnotationLink} {
+		value, ok := annotations[key]
+		if !ok {
+			continue
+		}
+		decoder := json.NewDecoder(strings.NewReader(value))
+		decoder.UseNumber()
+		var data interface{}
+		if err := decoder.Decode(&data); err != nil {
+			return fmt.Errorf("annotation %q: %w", key, err)
+		}
+		canonical, err := json.Marshal(data)
+		if err != nil {
+			return fmt.Errorf("annotation %q: %w", key, err)
+		}
+		annotations[key] = string(canonical)
+	}
+	return nil
+}
+
+// recordInvalid records the validation error of the file at location in invalid and
+// returns nil. If invalid is nil, the validation error is returned to stop the build.
+func recordInvalid(invalid *[]manager.InvalidFile, location string, err error) error {
+	if err == nil || invalid == nil {
+		return err
+	}
+	*invalid = append(*invalid, manager.InvalidFile{File: location, Err: err})
+	return nil
+}
+
+// normalizedFileInfo returns the file information recorded for the file at location when
+// file information is normalized. Permissions that are not configured are derived from
+// the executable bit of the file.
+func normalizedFileInfo(space workspace.Workspace, location string, configured *empspec.File) (*empspec.File, error) {
+	info := empspec.File{UID: -1, GID: -1}
+	if configured != nil {
+		info = *configured
+	}
+	if info.Permissions != 0 {
+		return &info, nil
+	}
+	stat, err := space.Stat(location)
+	if err != nil {
+		return nil, fmt.Errorf("file %s: %w", location, err)
+	}
+	info.Permissions = 0644
+	if stat.Mode().Perm()&0100 != 0 {
+		info.Permissions = 0755
+	}
+	return &info, nil
+}
+
 // fileInformation pairs information configurable
 // file attributes for comparison.
 type fileInformation struct {
 	model.AttributeSet
 	empspec.File
+	hasFileInfo bool
+	pattern     *load.Pattern
 }
 
 func (f fileInformation) HasAttributes() bool {
@@ -355,5 +621,5 @@ func (f fileInformation) HasAttributes() bool {
 }
 
 func (f fileInformation) HasFileInfo() bool {
-	return f.Permissions != 0 || f.UID != -1 || f.GID != -1
+	return f.hasFileInfo
 }
@@ -0,0 +1,55 @@
+package defaultmanager
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	empspec "github.com/emporous/collection-spec/specs-go/v1alpha1"
+
+	"github.com/emporous/emporous-go/sbom"
+)
+
+// readSBOM reads the SBOM document at the given path.
+func readSBOM(path string) (sbom.Document, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return sbom.Document{}, fmt.Errorf("sbom %s: %w", path, err)
+	}
+	doc, err := sbom.Decode(data)
+	if err != nil {
+		return sbom.Document{}, fmt.Errorf("sbom %s: %w", path, err)
+	}
+	return doc, nil
+}
+
+// fillComponent sets the unset fields in the
+// component from the source component.
+func fillComponent(component *empspec.Component, source empspec.Component) {
+	if component.Name == "" {
+		component.Name = source.Name
+	}
+	if component.Version == "" {
+		component.Version = source.Version
+	}
+	if component.Type == "" {
+		component.Type = source.Type
+	}
+	if component.FoundBy == "" {
+		component.FoundBy = source.FoundBy
+	}
+	if len(component.Locations) == 0 {
+		component.Locations = source.Locations
+	}
+	if len(component.Licenses) == 0 {
+		component.Licenses = source.Licenses
+	}
+	if component.Language == "" {
+		component.Language = source.Language
+	}
+	if len(component.CPEs) == 0 {
+		component.CPEs = source.CPEs
+	}
+	if component.PURL == "" {
+		component.PURL = source.PURL
+	}
+}
@@ -10,12 +10,54 @@ import (
 type DefaultManager struct {
 	store  content.AttributeStore
 	logger log.Logger
+	owner  *owner
+	// normalizeFileInfo records normalized
+	// permissions for built files.
+	normalizeFileInfo bool
+	// deferValidation continues building collections
+	// with attributes that are not valid for the schema.
+	deferValidation bool
+}
+
+// Option configures optional settings for a DefaultManager.
+type Option func(*DefaultManager)
+
+// WithOwner sets the user and group ID applied to pulled files that have
+// recorded ownership in place of the recorded IDs. An ID of -1 keeps the
+// current owner.
+func WithOwner(uid, gid int) Option {
+	return func(d *DefaultManager) {
+		d.owner = &owner{uid: uid, gid: gid}
+	}
+}
+
+// WithNormalizedFileInfo records core-file permissions for each built file that has
+// none set in the dataset configuration: 0755 for files executable by their owner
+// and 0644 otherwise, so the mode of the files on the host is not recorded.
+// Ownership that is not set in the dataset configuration is not recorded.
+func WithNormalizedFileInfo() Option {
+	return func(d *DefaultManager) {
+		d.normalizeFileInfo = true
+	}
+}
+
+// WithDeferredValidation continues building collections with attributes that are not
+// valid for the collection schema. The invalid files are returned in a
+// *manager.ErrSchemaValidation error after the collection is built.
+func WithDeferredValidation() Option {
+	return func(d *DefaultManager) {
+		d.deferValidation = true
+	}
 }
 
 // New instantiates a new DefaultManager.
-func New(store content.AttributeStore, logger log.Logger) manager.Manager {
-	return DefaultManager{
+func New(store content.AttributeStore, logger log.Logger, options ...Option) manager.Manager {
+	d := DefaultManager{
 		store:  store,
 		logger: logger,
 	}
+	for _, option := range options {
+		option(&d)
+	}
+	return d
 }
@@ -0,0 +1,172 @@
+package defaultmanager
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+
+	clientapi "github.com/emporous/emporous-go/api/client/v1alpha1"
+	"github.com/emporous/emporous-go/attributes"
+	"github.com/emporous/emporous-go/attributes/extractors"
+	load "github.com/emporous/emporous-go/config"
+	"github.com/emporous/emporous-go/model"
+	"github.com/emporous/emporous-go/util/workspace"
+)
+
+// defaultPluginTimeout is the maximum duration of a single
+// plugin run when no timeout is configured.
+const defaultPluginTimeout = time.Minute
+
+// extractorPlugin pairs a configured plugin extractor with
+// the file patterns it runs against.
+type extractorPlugin struct {
+	name      string
+	extractor extractors.Extractor
+	patterns  []*load.Pattern
+}
+
+func (p extractorPlugin) matches(location string) bool {
+	for _, pattern := range p.patterns {
+		if pattern.Match(location) {
+			return true
+		}
+	}
+	return false
+}
+
+// extractAttributes runs the configured extractors against each file in parallel and returns
+// the derived attributes by file location and schema ID. Built-in attributes are stored under the
+// reserved extractors.SchemaID and plugin attributes are stored under the given schema ID.
+func (d DefaultManager) extractAttributes(ctx context.Context, space workspace.Workspace, spec clientapi.ExtractorSpec, schemaID string, files []string) (map[string]map[string]model.AttributeSet, error) {
+	extracted := map[string]map[string]model.AttributeSet{}
+	if !spec.BuiltIn && len(spec.Plugins) == 0 {
+		return extracted, nil
+	}
+
+	plugins, err := loadPlugins(spec.Plugins)
+	if err != nil {
+		return nil, err
+	}
+
+	d.logger.Infof("Extracting attributes from %d file(s)", len(files))
+
+	var mu sync.Mutex
+	builtInSets := map[string]model.AttributeSet{}
+	// Plugin results are stored by plugin order to keep
+	// merging deterministic.
+	pluginSets := map[string][]model.AttributeSet{}
+
+	builtIn := extractors.NewBuiltIn(d.logger)
+	eg, egCtx := errgroup.WithContext(ctx)
+	eg.SetLimit(runtime.NumCPU())
+	for _, file := range files {
+		file := file
+		location := filepath.ToSlash(filepath.Clean(file))
+
+		if spec.BuiltIn {
+			eg.Go(func() error {
+				set, err := builtIn.Extract(egCtx, space, file)
+				if err != nil {
+					return fmt.Errorf("file %q: attribute extraction: %w", location, err)
+				}
+				mu.Lock()
+				builtInSets[location] = set
+				mu.Unlock()
+				return nil
+			})
+		}
+
+		for i, p := range plugins {
+			if !p.matches(location) {
+				continue
+			}
+			i, p := i, p
+			mu.Lock()
+			if _, ok := pluginSets[location]; !ok {
+				pluginSets[location] = make([]model.AttributeSet, len(plugins))
+			}
+			mu.Unlock()
+			eg.Go(func() error {
+				d.logger.Debugf("Running extractor plugin %s on %s", p.name, location)
+				set, err := p.extractor.Extract(egCtx, space, file)
+				if err != nil {
+					return fmt.Errorf("file %q: extractor %s: %w", location, p.name, err)
+				}
+				mu.Lock()
+				pluginSets[location][i] = set
+				mu.Unlock()
+				return nil
+			})
+		}
+	}
+
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+
+	for location, set := range builtInSets {
+		extracted[location] = map[string]model.AttributeSet{extractors.SchemaID: set}
+	}
+
+	for location, sets := range pluginSets {
+		var nonEmpty []model.AttributeSet
+		for _, set := range sets {
+			if set != nil {
+				nonEmpty = append(nonEmpty, set)
+			}
+		}
+		merged, err := attributes.Merge(nonEmpty...)
+		if err != nil {
+			return nil, fmt.Errorf("file %q: failed to merge extracted attributes: %w", location, err)
+		}
+		if _, ok := extracted[location]; !ok {
+			extracted[location] = map[string]model.AttributeSet{}
+		}
+		extracted[location][schemaID] = merged
+	}
+
+	return extracted, nil
+}
+
+// loadPlugins creates plugin extractors from configuration.
+func loadPlugins(specs []clientapi.ExtractorPlugin) ([]extractorPlugin, error) {
+	var plugins []extractorPlugin
+	names := map[string]struct{}{}
+	for _, spec := range specs {
+		if spec.Name == "" || spec.Command == "" {
+			return nil, fmt.Errorf("extractor plugins require a name and command")
+		}
+		if _, ok := names[spec.Name]; ok {
+			return nil, fmt.Errorf("extractor %s: duplicate plugin name", spec.Name)
+		}
+		names[spec.Name] = struct{}{}
+
+		timeout := defaultPluginTimeout
+		if spec.Timeout != "" {
+			var err error
+			timeout, err = time.ParseDuration(spec.Timeout)
+			if err != nil {
+				return nil, fmt.Errorf("extractor %s: invalid timeout: %w", spec.Name, err)
+			}
+		}
+
+		p := extractorPlugin{
+			name:      spec.Name,
+			extractor: extractors.NewPlugin(spec.Command, spec.Args, timeout),
+		}
+		for _, file := range spec.Files {
+			pattern, err := load.CompilePattern(file)
+			if err != nil {
+				return nil, fmt.Errorf("extractor %s: %w", spec.Name, err)
+			}
+			p.patterns = append(p.patterns, pattern)
+		}
+		plugins = append(plugins, p)
+	}
+	return plugins, nil
+}
@@ -0,0 +1,127 @@
+package defaultmanager
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	empspec "github.com/emporous/collection-spec/specs-go/v1alpha1"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+	"github.com/emporous/emporous-go/content"
+	"github.com/emporous/emporous-go/nodes/descriptor"
+)
+
+// owner is the user and group ID applied to pulled files.
+type owner struct {
+	uid int
+	gid int
+}
+
+// applyFileInfo sets the core-file permissions and ownership recorded for the
+// pulled descriptors on the files written to the destination. Ownership is applied
+// when running as root or when an owner is configured. Files whose metadata cannot
+// be set are logged and returned. Files that are hard links to cached blobs
+// are kept read-only and their ownership is not changed.
+func (d DefaultManager) applyFileInfo(destination content.Store, descs []ocispec.Descriptor) []string {
+	fileStore, ok := destination.(content.FileStore)
+	if !ok {
+		return nil
+	}
+	dir := fileStore.Dir()
+	isRoot := os.Geteuid() == 0
+
+	var failed []string
+	for _, desc := range descs {
+		title, ok := desc.Annotations[ocispec.AnnotationTitle]
+		if !ok {
+			continue
+		}
+		if _, ok := desc.Annotations[empspec.AnnotationEmporousAttributes]; !ok {
+			continue
+		}
+		attrs, err := descriptor.AnnotationsToAttributes(desc.Annotations)
+		if err != nil {
+			d.logger.Warnf("file %s: error reading file information: %v", title, err)
+			failed = append(failed, title)
+			continue
+		}
+		props, err := descriptor.Parse(attrs)
+		if err != nil {
+			d.logger.Warnf("file %s: error reading file information: %v", title, err)
+			failed = append(failed, title)
+			continue
+		}
+		if !props.HasFileInfo() {
+			continue
+		}
+
+		rel, ok := fileStore.Path(desc)
+		if !ok {
+			continue
+		}
+		location := filepath.Join(dir, filepath.FromSlash(rel))
+		if rel, err := filepath.Rel(dir, location); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			d.logger.Warnf("file %s: path is outside of %s", title, dir)
+			failed = append(failed, title)
+			continue
+		}
+		info, err := os.Lstat(location)
+		if err != nil {
+			d.logger.Warnf("file %s: %v", title, err)
+			failed = append(failed, title)
+			continue
+		}
+		if info.Mode()&os.ModeSymlink != 0 {
+			continue
+		}
+
+		if err := d.setFileInfo(location, *props.File, isRoot, fileStore.Shared(desc)); err != nil {
+			d.logger.Warnf("file %s: %v", title, err)
+			failed = append(failed, title)
+			continue
+		}
+		d.logger.Debugf("Applied file information to %s", title)
+	}
+	return failed
+}
+
+// setFileInfo sets the recorded permissions and ownership on the file at location.
+// Write permissions and ownership are not applied to shared files.
+func (d DefaultManager) setFileInfo(location string, info empspec.File, isRoot, shared bool) error {
+	if info.Permissions != 0 {
+		perm := os.FileMode(info.Permissions).Perm()
+		if shared {
+			perm &^= 0222
+		}
+		if err := os.Chmod(location, perm); err != nil {
+			return fmt.Errorf("error setting permissions: %w", err)
+		}
+	}
+	if shared {
+		d.logger.Debugf("Skipping ownership for %s: file is linked to the cache", location)
+		return nil
+	}
+
+	uid, gid := info.UID, info.GID
+	if uid < 0 && gid < 0 {
+		return nil
+	}
+	switch {
+	case d.owner != nil:
+		if uid >= 0 {
+			uid = d.owner.uid
+		}
+		if gid >= 0 {
+			gid = d.owner.gid
+		}
+	case !isRoot:
+		d.logger.Debugf("Skipping ownership for %s: not running as root", location)
+		return nil
+	}
+	if err := os.Lchown(location, uid, gid); err != nil {
+		return fmt.Errorf("error setting ownership: %w", err)
+	}
+	return nil
+}
@@ -4,11 +4,16 @@ import (
 	"context"
 
 	"github.com/emporous/emporous-go/content"
+	"github.com/emporous/emporous-go/manager"
+	"github.com/emporous/emporous-go/model/traversal"
 	"github.com/emporous/emporous-go/registryclient"
 )
 
 // Pull pulls a single collection to a specified storage destination.
-// If successful, the file locations are returned.
+// If successful, the file locations are returned. If the destination is a
+// content.FileStore, recorded core-file information is applied to the pulled files
+// and a *manager.ErrFileInfo error is returned with the file locations if it cannot
+// be applied to all files.
 func (d DefaultManager) Pull(ctx context.Context, source string, remote registryclient.Remote, destination content.Store) ([]string, error) {
 	rootDesc, descs, err := remote.Pull(ctx, source, destination)
 	if err != nil {
@@ -22,28 +27,39 @@ func (d DefaultManager) Pull(ctx context.Context, source string, remote registry
 		}
 	}
 
+	failed := d.applyFileInfo(destination, descs)
+
 	var digests []string
 	for _, desc := range descs {
 		digests = append(digests, desc.Digest.String())
 		d.logger.Infof("Found matching digest %s", desc.Digest)
 	}
+	if len(failed) != 0 {
+		return digests, &manager.ErrFileInfo{Files: failed}
+	}
 	return digests, nil
 }
 
 // PullAll pulls linked collection to a specified storage destination.
 // If successful, the file locations are returned.
 // PullAll is similar to Pull with the exception that it walks a graph of linked collections
-// starting with the source collection reference.
-func (d DefaultManager) PullAll(ctx context.Context, source string, remote registryclient.Remote, destination content.Store) ([]string, error) {
-	descs, err := remote.PullWithLinks(ctx, source, destination)
+// starting with the source collection reference. A non-nil budget limits the depth of
+// the links followed, the number of nodes visited and the size of the pulled collections.
+func (d DefaultManager) PullAll(ctx context.Context, source string, remote registryclient.Remote, destination content.Store, budget *traversal.Budget) ([]string, error) {
+	descs, err := remote.PullWithLinks(ctx, source, destination, budget)
 	if err != nil {
 		return nil, err
 	}
 
+	failed := d.applyFileInfo(destination, descs)
+
 	var digests []string
 	for _, desc := range descs {
 		digests = append(digests, desc.Digest.String())
 		d.logger.Infof("Found matching digest %s", desc.Digest)
 	}
+	if len(failed) != 0 {
+		return digests, &manager.ErrFileInfo{Files: failed}
+	}
 	return digests, nil
 }
@@ -0,0 +1,104 @@
+package defaultmanager
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	empspec "github.com/emporous/collection-spec/specs-go/v1alpha1"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+
+	"github.com/emporous/emporous-go/content"
+	"github.com/emporous/emporous-go/log"
+	"github.com/emporous/emporous-go/manager"
+	"github.com/emporous/emporous-go/model/traversal"
+	"github.com/emporous/emporous-go/registryclient"
+)
+
+func TestPullFileInfoFailures(t *testing.T) {
+	testlogr, err := log.NewLogrusLogger(io.Discard, "debug")
+	require.NoError(t, err)
+
+	data := []byte("hello")
+	desc := ocispec.Descriptor{
+		MediaType: "text/plain",
+		Digest:    digest.FromBytes(data),
+		Size:      int64(len(data)),
+		Annotations: map[string]string{
+			ocispec.AnnotationTitle:              "hello.txt",
+			empspec.AnnotationEmporousAttributes: `{"core-file":{"permissions":384,"uid":-1,"gid":-1}}`,
+		},
+	}
+	remote := &testRemote{descs: []ocispec.Descriptor{desc}}
+	// The file is not written to the destination,
+	// so its file information cannot be applied.
+	destination := &testFileStore{dir: t.TempDir()}
+	mg := New(nil, testlogr)
+
+	type spec struct {
+		name string
+		pull func() ([]string, error)
+	}
+
+	cases := []spec{
+		{
+			name: "Pull",
+			pull: func() ([]string, error) {
+				return mg.Pull(context.TODO(), "localhost:5001/test:latest", remote, destination)
+			},
+		},
+		{
+			name: "PullAll",
+			pull: func() ([]string, error) {
+				return mg.PullAll(context.TODO(), "localhost:5001/test:latest", remote, destination, nil)
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			digests, err := c.pull()
+			require.Equal(t, []string{desc.Digest.String()}, digests)
+			require.EqualError(t, err, "file information could not be applied to 1 file(s): hello.txt")
+			var fileInfoErr *manager.ErrFileInfo
+			require.ErrorAs(t, err, &fileInfoErr)
+			require.Equal(t, []string{"hello.txt"}, fileInfoErr.Files)
+		})
+	}
+}
+
+// testRemote returns pulled descriptors without writing content.
+type testRemote struct {
+	registryclient.Remote
+	descs []ocispec.Descriptor
+}
+
+func (r *testRemote) Pull(context.Context, string, content.Store) (ocispec.Descriptor, []ocispec.Descriptor, error) {
+	return ocispec.Descriptor{}, r.descs, nil
+}
+
+func (r *testRemote) PullWithLinks(context.Context, string, content.Store, *traversal.Budget) ([]ocispec.Descriptor, error) {
+	return r.descs, nil
+}
+
+// testFileStore is a content.FileStore that writes titled
+// content to the path of the title.
+type testFileStore struct {
+	content.Store
+	dir string
+}
+
+func (s *testFileStore) Dir() string {
+	return s.dir
+}
+
+func (s *testFileStore) Path(desc ocispec.Descriptor) (string, bool) {
+	title, ok := desc.Annotations[ocispec.AnnotationTitle]
+	return title, ok
+}
+
+func (s *testFileStore) Shared(ocispec.Descriptor) bool {
+	return false
+}
@@ -3,18 +3,75 @@ package defaultmanager
 import (
 	"context"
 	"fmt"
+	"strings"
 
+	"oras.land/oras-go/v2/registry"
+
+	"github.com/emporous/emporous-go/manager"
 	"github.com/emporous/emporous-go/registryclient"
 )
 
 // Push pushes collection to a remote location from the underlying content store.
-// If successful, the root descriptor is returned.
-func (d DefaultManager) Push(ctx context.Context, reference string, remote registryclient.Remote) (string, error) {
-	desc, err := remote.Push(ctx, d.store, reference)
+// The content is uploaded once and the destination repository is tagged with any additional tags.
+// If successful, the root descriptor and the result for each additional tag are returned.
+func (d DefaultManager) Push(ctx context.Context, reference string, remote registryclient.Remote, tags ...string) (string, []manager.TagResult, error) {
+	// Validate all tags before any content is uploaded so
+	// an invalid tag does not result in a partial push.
+	references, err := tagReferences(reference, tags)
 	if err != nil {
-		return "", fmt.Errorf("error publishing content to %s: %v", reference, err)
+		return "", nil, err
 	}
 
+	desc, err := remote.Push(ctx, d.store, reference)
+	if err != nil {
+		return "", nil, fmt.Errorf("error publishing content to %s: %v", reference, err)
+	}
 	d.logger.Infof("Artifact %s published to %s\n", desc.Digest, reference)
-	return desc.Digest.String(), nil
+
+	var results []manager.TagResult
+	var failed []string
+	for _, ref := range references {
+		result := manager.TagResult{Reference: ref}
+		if err := remote.Tag(ctx, desc, ref); err != nil {
+			result.Err = err
+			failed = append(failed, fmt.Sprintf("%s: %v", ref, err))
+			d.logger.Errorf("Artifact %s could not be tagged as %s: %v", desc.Digest, ref, err)
+		} else {
+			d.logger.Infof("Artifact %s tagged as %s", desc.Digest, ref)
+		}
+		results = append(results, result)
+	}
+
+	if len(failed) != 0 {
+		return desc.Digest.String(), results, fmt.Errorf("artifact %s published to %s, but %d tag(s) failed: %s",
+			desc.Digest, reference, len(failed), strings.Join(failed, "; "))
+	}
+	return desc.Digest.String(), results, nil
+}
+
+// tagReferences returns the references for the tags in the repository
+// of the destination reference. Duplicate tags and the destination tag are skipped.
+func tagReferences(destination string, tags []string) ([]string, error) {
+	if len(tags) == 0 {
+		return nil, nil
+	}
+	dest, err := registry.ParseReference(destination)
+	if err != nil {
+		return nil, err
+	}
+	seen := map[string]struct{}{dest.Reference: {}}
+	var references []string
+	for _, tag := range tags {
+		if _, ok := seen[tag]; ok {
+			continue
+		}
+		seen[tag] = struct{}{}
+		ref := dest
+		ref.Reference = tag
+		if err := ref.ValidateReferenceAsTag(); err != nil {
+			return nil, fmt.Errorf("tag %q: %w", tag, err)
+		}
+		references = append(references, ref.String())
+	}
+	return references, nil
 }
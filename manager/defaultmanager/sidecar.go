@@ -0,0 +1,124 @@
+package defaultmanager
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	clientapi "github.com/emporous/emporous-go/api/client/v1alpha1"
+	"github.com/emporous/emporous-go/attributes"
+	"github.com/emporous/emporous-go/attributes/extractors"
+	load "github.com/emporous/emporous-go/config"
+	"github.com/emporous/emporous-go/model"
+	"github.com/emporous/emporous-go/util/workspace"
+)
+
+// directoryAttributes stores the attributes declared in sidecar
+// files by workspace directory.
+type directoryAttributes map[string]sidecar
+
+// sidecar is the attribute set loaded from a sidecar file.
+type sidecar struct {
+	location string
+	set      model.AttributeSet
+}
+
+// isSidecar returns whether the workspace file
+// is a directory attributes sidecar file.
+func isSidecar(location string) bool {
+	return filepath.Base(location) == clientapi.DirectoryAttributesFileName
+}
+
+// loadDirectoryAttributes reads the sidecar files at the given locations in the workspace.
+func loadDirectoryAttributes(ctx context.Context, space workspace.Workspace, locations []string) (directoryAttributes, error) {
+	dirAttributes := directoryAttributes{}
+	for _, location := range locations {
+		reader, err := space.GetReader(ctx, location)
+		if err != nil {
+			return nil, err
+		}
+		data, err := ioutil.ReadAll(reader)
+		reader.Close()
+		if err != nil {
+			return nil, fmt.Errorf("sidecar %s: %w", location, err)
+		}
+		dirConfig, err := load.LoadDirectoryAttributes(data)
+		if err != nil {
+			return nil, fmt.Errorf("sidecar %s: %w", location, err)
+		}
+		set, err := load.ConvertToModel(dirConfig.Attributes)
+		if err != nil {
+			return nil, fmt.Errorf("sidecar %s: %w", location, err)
+		}
+		location = filepath.ToSlash(location)
+		dirAttributes[path.Dir(location)] = sidecar{location: location, set: set}
+	}
+	return dirAttributes, nil
+}
+
+// resolve returns the attributes that apply to the file at the given location and the
+// sidecar location each attribute was set from. Sidecar files in deeper directories override
+// attributes with the same key from parent directories.
+func (d directoryAttributes) resolve(location string) (attributes.Attributes, map[string]string) {
+	resolved := attributes.Attributes{}
+	sources := map[string]string{}
+	if len(d) == 0 {
+		return resolved, sources
+	}
+
+	// Collect the parent directories from the workspace root
+	// to the directory containing the file.
+	dirs := []string{"."}
+	dir := path.Dir(filepath.ToSlash(location))
+	if dir != "." {
+		parts := strings.Split(dir, "/")
+		for i := range parts {
+			dirs = append(dirs, strings.Join(parts[:i+1], "/"))
+		}
+	}
+
+	for _, dir := range dirs {
+		sc, ok := d[dir]
+		if !ok {
+			continue
+		}
+		for key, value := range sc.set.List() {
+			resolved[key] = value
+			sources[key] = sc.location
+		}
+	}
+	return resolved, sources
+}
+
+// traceAttributes logs where each attribute set on the file at the given location came from.
+// Attributes without a recorded source were set by an extractor.
+func (d DefaultManager) traceAttributes(location string, sets map[string]model.AttributeSet, sources map[string]string) {
+	var schemaIDs []string
+	for schemaID := range sets {
+		schemaIDs = append(schemaIDs, schemaID)
+	}
+	sort.Strings(schemaIDs)
+
+	for _, schemaID := range schemaIDs {
+		list := sets[schemaID].List()
+		keys := make([]string, 0, len(list))
+		for key := range list {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			source := sources[key]
+			switch {
+			case schemaID == extractors.SchemaID:
+				source = "built-in extractor"
+			case source == "":
+				source = "extractor plugin"
+			}
+			d.logger.Debugf("file %s: attribute %s.%s set from %s", location, schemaID, key, source)
+		}
+	}
+}
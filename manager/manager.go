@@ -2,9 +2,12 @@ package manager
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
 	clientapi "github.com/emporous/emporous-go/api/client/v1alpha1"
 	"github.com/emporous/emporous-go/content"
+	"github.com/emporous/emporous-go/model/traversal"
 	"github.com/emporous/emporous-go/registryclient"
 	"github.com/emporous/emporous-go/util/workspace"
 )
@@ -12,17 +15,80 @@ import (
 // Manager defines methods for building, publishing, and retrieving emporous collections.
 type Manager interface {
 	// Build builds collection from input and store it in the underlying content store.
-	// If successful, the root descriptor is returned.
+	// If successful, the root descriptor is returned. Builds that continue past schema
+	// validation failures return the root descriptor with an *ErrSchemaValidation error.
 	Build(ctx context.Context, source workspace.Workspace, config clientapi.DataSetConfiguration, destination string, client registryclient.Client) (string, error)
 	// Push pushes collection to a remote location from the underlying content store.
-	// If successful, the root descriptor is returned.
-	Push(ctx context.Context, destination string, remote registryclient.Remote) (string, error)
+	// The content is uploaded once and the destination repository is tagged with any additional tags.
+	// If successful, the root descriptor and the result for each additional tag are returned.
+	Push(ctx context.Context, destination string, remote registryclient.Remote, tags ...string) (string, []TagResult, error)
 	// Pull pulls a single collection to a specified storage destination.
-	// If successful, the file locations are returned.
+	// If successful, the file locations are returned. If recorded file information
+	// cannot be applied to the pulled files, the file locations are returned
+	// with an *ErrFileInfo error.
 	Pull(ctx context.Context, source string, remote registryclient.Remote, destination content.Store) ([]string, error)
 	// PullAll pulls linked collection to a specified storage destination.
 	// If successful, the file locations are returned.
 	// PullAll is similar to Pull with the exception that it walks a graph of linked collections
-	// starting with the source collection reference.
-	PullAll(ctx context.Context, source string, remote registryclient.Remote, destination content.Store) ([]string, error)
+	// starting with the source collection reference. A non-nil budget limits the depth of
+	// the links followed, the number of nodes visited and the size of the pulled collections.
+	// As with Pull, an *ErrFileInfo error is returned with the file locations if recorded
+	// file information cannot be applied to the pulled files.
+	PullAll(ctx context.Context, source string, remote registryclient.Remote, destination content.Store, budget *traversal.Budget) ([]string, error)
+}
+
+// TagResult describes the result of tagging a pushed collection.
+type TagResult struct {
+	// Reference is the tagged reference.
+	Reference string
+	// Err is set if the reference could not be tagged.
+	Err error
+}
+
+// ErrFileInfo denotes that the recorded core-file permissions or
+// ownership could not be applied to pulled files. The files
+// are still written to the destination.
+type ErrFileInfo struct {
+	// Files are the titles of the affected files.
+	Files []string
+}
+
+func (e *ErrFileInfo) Error() string {
+	return fmt.Sprintf("file information could not be applied to %d file(s): %s", len(e.Files), strings.Join(e.Files, ", "))
+}
+
+// InvalidFile describes a file with attributes that
+// are not valid for the collection schema.
+type InvalidFile struct {
+	// Variant is the name of the variant containing the file, if any.
+	Variant string
+	// File is the title of the file. It is empty if the
+	// dataset configuration attributes are not valid.
+	File string
+	// Err is the validation error.
+	Err error
+}
+
+// ErrSchemaValidation denotes that attributes are not valid
+// for the collection schema. The collection is still built.
+type ErrSchemaValidation struct {
+	// Schema is the address of the collection schema.
+	Schema string
+	// Files are the files with invalid attributes.
+	Files []InvalidFile
+}
+
+func (e *ErrSchemaValidation) Error() string {
+	var files []string
+	for _, file := range e.Files {
+		name := file.File
+		if name == "" {
+			name = "dataset configuration"
+		}
+		if file.Variant != "" {
+			name = fmt.Sprintf("%s (variant %s)", name, file.Variant)
+		}
+		files = append(files, fmt.Sprintf("%s: %v", name, file.Err))
+	}
+	return fmt.Sprintf("attributes are not valid for schema %s: %s", e.Schema, strings.Join(files, "; "))
 }
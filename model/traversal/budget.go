@@ -13,14 +13,54 @@ import (
 type Budget struct {
 	// Maximum numbers of nodes to visit in a single traversal operator before stopping.
 	NodeBudget int64
+	// Maximum number of bytes of content described by visited nodes that handlers
+	// may spend with SpendBytes in a single traversal operation before stopping.
+	// Zero or negative values do not limit the bytes spent.
+	ByteBudget int64
+	// Maximum depth of nodes that handlers visit below the root node. Handlers
+	// define how depth is counted and skip deeper nodes. Zero or negative values
+	// do not limit depth.
+	MaxDepth int
+	// spendingBytes is set once bytes are spent from a limited byte
+	// budget, so a budget spent down to zero still limits bytes.
+	spendingBytes bool
+}
+
+// SpendBytes spends the size in bytes of the content described by the node
+// from the byte budget. An ErrBudgetExceeded error is returned if the remaining
+// budget does not cover the size. A nil Budget or a Budget without a byte budget
+// does not limit the bytes spent.
+func (b *Budget) SpendBytes(node model.Node, size int64) error {
+	if b == nil || (b.ByteBudget <= 0 && !b.spendingBytes) {
+		return nil
+	}
+	b.spendingBytes = true
+	if b.ByteBudget < size {
+		return &ErrBudgetExceeded{Node: node, Bytes: size}
+	}
+	b.ByteBudget -= size
+	return nil
+}
+
+// ExceedsDepth returns whether the depth is deeper than the maximum depth.
+// A nil Budget or a Budget without a maximum depth does not limit depth.
+func (b *Budget) ExceedsDepth(depth int) bool {
+	return b != nil && b.MaxDepth > 0 && depth > b.MaxDepth
 }
 
 // ErrBudgetExceeded is an error that described the event where
-// the maximum amount of nodes have been visited with no match.
+// the maximum amount of nodes have been visited with no match or
+// the content of a node is larger than the remaining byte budget.
 type ErrBudgetExceeded struct {
 	Node model.Node
+	// Bytes is the size of the content that exceeded the byte
+	// budget. It is zero when the node budget was exceeded.
+	Bytes int64
 }
 
 func (e *ErrBudgetExceeded) Error() string {
+	if e.Bytes != 0 {
+		return fmt.Sprintf("traversal budget exceeded: byte budget cannot cover %d bytes for node %v", e.Bytes, e.Node.Address())
+	}
 	return fmt.Sprintf("traversal budget exceeded: node budget for reached zero while on node %v", e.Node.Address())
 }
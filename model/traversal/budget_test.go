@@ -0,0 +1,77 @@
+package traversal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/emporous/emporous-go/util/testutils"
+)
+
+func TestBudget_SpendBytes(t *testing.T) {
+	node := &testutils.FakeNode{I: "node1"}
+
+	budget := &Budget{ByteBudget: 10}
+	require.NoError(t, budget.SpendBytes(node, 6))
+	require.Equal(t, int64(4), budget.ByteBudget)
+	err := budget.SpendBytes(node, 5)
+	require.EqualError(t, err, "traversal budget exceeded: byte budget cannot cover 5 bytes for node address")
+	require.Equal(t, int64(4), budget.ByteBudget)
+
+	// A budget spent down to zero still limits bytes.
+	require.NoError(t, budget.SpendBytes(node, 4))
+	err = budget.SpendBytes(node, 1)
+	require.EqualError(t, err, "traversal budget exceeded: byte budget cannot cover 1 bytes for node address")
+
+	var unlimited *Budget
+	require.NoError(t, unlimited.SpendBytes(node, 100))
+
+	// An unset byte budget does not limit bytes.
+	partial := &Budget{NodeBudget: 100}
+	require.NoError(t, partial.SpendBytes(node, 100))
+	require.NoError(t, partial.SpendBytes(node, 100))
+	require.Equal(t, int64(0), partial.ByteBudget)
+}
+
+func TestBudget_ExceedsDepth(t *testing.T) {
+	type spec struct {
+		name   string
+		budget *Budget
+		depth  int
+		exp    bool
+	}
+
+	cases := []spec{
+		{
+			name:   "Success/WithinDepth",
+			budget: &Budget{MaxDepth: 2},
+			depth:  2,
+		},
+		{
+			name:   "Success/ExceedsDepth",
+			budget: &Budget{MaxDepth: 2},
+			depth:  3,
+			exp:    true,
+		},
+		{
+			name:   "Success/UnlimitedDepth",
+			budget: &Budget{MaxDepth: -1},
+			depth:  100,
+		},
+		{
+			name:   "Success/UnsetDepth",
+			budget: &Budget{NodeBudget: 100},
+			depth:  100,
+		},
+		{
+			name:  "Success/NilBudget",
+			depth: 100,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			require.Equal(t, c.exp, c.budget.ExceedsDepth(c.depth))
+		})
+	}
+}
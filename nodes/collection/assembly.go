@@ -25,6 +25,29 @@ func (c *Collection) UpdateNode(node model.Node) error {
 	return nil
 }
 
+// RemoveNode removes the node and any edges to or
+// from the node from the graph.
+func (c *Collection) RemoveNode(id string) {
+	if _, exists := c.nodes[id]; !exists {
+		return
+	}
+	for to := range c.from[id] {
+		delete(c.to[to], id)
+		if len(c.to[to]) == 0 {
+			delete(c.to, to)
+		}
+	}
+	for from := range c.to[id] {
+		delete(c.from[from], id)
+		if len(c.from[from]) == 0 {
+			delete(c.from, from)
+		}
+	}
+	delete(c.from, id)
+	delete(c.to, id)
+	delete(c.nodes, id)
+}
+
 // AddEdge adds an edge between two nodes in the graph
 func (c *Collection) AddEdge(edge model.Edge) error {
 	from := edge.From().ID()
@@ -99,3 +99,29 @@ func TestCollection_SubCollection(t *testing.T) {
 		})
 	}
 }
+
+func TestCollection_RemoveNode(t *testing.T) {
+	nodes := []model.Node{
+		&testutils.FakeNode{I: "node1"},
+		&testutils.FakeNode{I: "node2"},
+		&testutils.FakeNode{I: "node3"},
+	}
+	edges := []model.Edge{
+		&Edge{F: &testutils.FakeNode{I: "node1"}, T: &testutils.FakeNode{I: "node2"}},
+		&Edge{F: &testutils.FakeNode{I: "node2"}, T: &testutils.FakeNode{I: "node3"}},
+		&Edge{F: &testutils.FakeNode{I: "node1"}, T: &testutils.FakeNode{I: "node3"}},
+	}
+
+	collection := makeTestCollection(t, nodes, edges)
+	collection.RemoveNode("node2")
+	require.Nil(t, collection.NodeByID("node2"))
+	require.Len(t, collection.Nodes(), 2)
+	require.Len(t, collection.Edges(), 1)
+	require.False(t, collection.HasEdgeFromTo("node1", "node2"))
+	require.Len(t, collection.From("node1"), 1)
+	require.Empty(t, collection.To("node2"))
+
+	// Removing a node that does not exist is a no-op.
+	collection.RemoveNode("node4")
+	require.Len(t, collection.Nodes(), 2)
+}
@@ -0,0 +1,58 @@
+package descriptor
+
+import (
+	"fmt"
+	"strings"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+	"github.com/emporous/emporous-go/model"
+)
+
+// AnnotationVariant is the name of a variant manifest
+// referenced by a collection index.
+const AnnotationVariant = "emporous.variant"
+
+// ParsePlatform parses a platform string in the
+// form os/arch[/variant].
+func ParsePlatform(platform string) (ocispec.Platform, error) {
+	parts := strings.Split(platform, "/")
+	if len(parts) < 2 || len(parts) > 3 {
+		return ocispec.Platform{}, fmt.Errorf("platform %q: must be in the form os/arch[/variant]", platform)
+	}
+	for _, part := range parts {
+		if part == "" {
+			return ocispec.Platform{}, fmt.Errorf("platform %q: must be in the form os/arch[/variant]", platform)
+		}
+	}
+	p := ocispec.Platform{
+		OS:           parts[0],
+		Architecture: parts[1],
+	}
+	if len(parts) == 3 {
+		p.Variant = parts[2]
+	}
+	return p, nil
+}
+
+var _ model.Matcher = PlatformMatcher{}
+
+// PlatformMatcher checks that the platform of a descriptor node matches
+// the operating system and architecture. The variant is only compared if set.
+type PlatformMatcher ocispec.Platform
+
+// Matches determines whether a node descriptor has a matching platform.
+func (m PlatformMatcher) Matches(n model.Node) (bool, error) {
+	descNode, ok := n.(interface{ Descriptor() ocispec.Descriptor })
+	if !ok {
+		return false, nil
+	}
+	platform := descNode.Descriptor().Platform
+	if platform == nil {
+		return false, nil
+	}
+	if platform.OS != m.OS || platform.Architecture != m.Architecture {
+		return false, nil
+	}
+	return m.Variant == "" || platform.Variant == m.Variant, nil
+}
@@ -0,0 +1,101 @@
+package descriptor
+
+import (
+	"testing"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+
+	"github.com/emporous/emporous-go/util/testutils"
+)
+
+func TestParsePlatform(t *testing.T) {
+	type spec struct {
+		name     string
+		platform string
+		exp      ocispec.Platform
+		expError string
+	}
+
+	cases := []spec{
+		{
+			name:     "Success/OSAndArch",
+			platform: "linux/amd64",
+			exp:      ocispec.Platform{OS: "linux", Architecture: "amd64"},
+		},
+		{
+			name:     "Success/WithVariant",
+			platform: "linux/arm/v7",
+			exp:      ocispec.Platform{OS: "linux", Architecture: "arm", Variant: "v7"},
+		},
+		{
+			name:     "Failure/MissingArch",
+			platform: "linux",
+			expError: "platform \"linux\": must be in the form os/arch[/variant]",
+		},
+		{
+			name:     "Failure/EmptyPart",
+			platform: "linux//v7",
+			expError: "platform \"linux//v7\": must be in the form os/arch[/variant]",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			p, err := ParsePlatform(c.platform)
+			if c.expError != "" {
+				require.EqualError(t, err, c.expError)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, c.exp, p)
+			}
+		})
+	}
+}
+
+func TestPlatformMatcher_Matches(t *testing.T) {
+	type spec struct {
+		name    string
+		matcher PlatformMatcher
+		node    *platformNode
+		exp     bool
+	}
+
+	cases := []spec{
+		{
+			name:    "Success/Match",
+			matcher: PlatformMatcher{OS: "linux", Architecture: "arm"},
+			node:    &platformNode{platform: &ocispec.Platform{OS: "linux", Architecture: "arm", Variant: "v7"}},
+			exp:     true,
+		},
+		{
+			name:    "Success/VariantMismatch",
+			matcher: PlatformMatcher{OS: "linux", Architecture: "arm", Variant: "v6"},
+			node:    &platformNode{platform: &ocispec.Platform{OS: "linux", Architecture: "arm", Variant: "v7"}},
+			exp:     false,
+		},
+		{
+			name:    "Success/NoPlatform",
+			matcher: PlatformMatcher{OS: "linux", Architecture: "amd64"},
+			node:    &platformNode{},
+			exp:     false,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			match, err := c.matcher.Matches(c.node)
+			require.NoError(t, err)
+			require.Equal(t, c.exp, match)
+		})
+	}
+}
+
+type platformNode struct {
+	testutils.FakeNode
+	platform *ocispec.Platform
+}
+
+func (n *platformNode) Descriptor() ocispec.Descriptor {
+	return ocispec.Descriptor{Platform: n.platform}
+}
@@ -7,7 +7,9 @@ import (
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 
 	"github.com/emporous/emporous-go/content"
+	"github.com/emporous/emporous-go/model/traversal"
 	"github.com/emporous/emporous-go/nodes/collection"
+	"github.com/emporous/emporous-go/util/workspace"
 )
 
 // Client defines methods to interact with OCI artifacts
@@ -23,20 +25,46 @@ type Remote interface {
 	// Push pushes an artifact to a remote registry from a source
 	// content store and returns the root manifest digest.
 	Push(context.Context, content.Store, string) (ocispec.Descriptor, error)
+	// Tag tags a manifest that exists in a remote registry
+	// with the specified reference.
+	Tag(context.Context, ocispec.Descriptor, string) error
 	// Pull pulls an artifact from a remote registry to a local
 	// content store. If successful it returns the root descriptor and all the descriptors pulled.
 	Pull(context.Context, string, content.Store) (ocispec.Descriptor, []ocispec.Descriptor, error)
 	// PullWithLinks pulls an artifact from a remote registry to a local
 	// content store and follows all the links. If successful it returns the root descriptor and all the descriptors pulled.
-	PullWithLinks(context.Context, string, content.Store) ([]ocispec.Descriptor, error)
+	// A non-nil budget limits the link depth, the number of nodes visited and the size of the pulled collections.
+	// An error is returned if the budget is exceeded or the links form a cycle.
+	PullWithLinks(context.Context, string, content.Store, *traversal.Budget) ([]ocispec.Descriptor, error)
+	// Copy copies an artifact from a source remote reference to a destination
+	// remote reference and returns the root descriptor.
+	Copy(context.Context, string, string) (ocispec.Descriptor, error)
+	// CopyWithLinks copies an artifact from a source remote reference to a destination
+	// remote reference and follows all the links. Linked collections are copied to the
+	// repositories returned by the LinkMapper and the link hints are rewritten. If successful,
+	// it returns the root descriptor at the destination.
+	CopyWithLinks(context.Context, string, string, LinkMapper) (ocispec.Descriptor, error)
 	// GetManifest retrieves the root manifest for a reference.
 	GetManifest(context.Context, string) (ocispec.Descriptor, io.ReadCloser, error)
 	// GetContent retrieves the content for a specified descriptor at a specified reference.
 	GetContent(context.Context, string, ocispec.Descriptor) ([]byte, error)
+	// FetchContent returns a reader for the content of a specified descriptor at a specified reference.
+	// The caller must close the reader.
+	FetchContent(context.Context, string, ocispec.Descriptor) (io.ReadCloser, error)
 	// LoadCollection loads a collection from a remote reference.
 	LoadCollection(context.Context, string) (collection.Collection, error)
+	// Referrers returns the descriptors of the artifacts that have the manifest at a
+	// reference as their subject. If an artifact type is set, only artifacts of that type are returned.
+	Referrers(context.Context, string, string) ([]ocispec.Descriptor, error)
+	// PullReferrer pulls a referrer artifact from the repository at a reference to a local
+	// content store without its subject. If successful it returns all the descriptors pulled.
+	PullReferrer(context.Context, string, ocispec.Descriptor, content.Store) ([]ocispec.Descriptor, error)
 }
 
+// LinkMapper returns the destination repository (e.g. registry/namespace)
+// for a linked collection in the source repository.
+type LinkMapper func(repository string) (string, error)
+
 // Local defines methods to interact with OCI artifacts
 // in a local context. An underlying store can be used to store
 // each descriptor and is returned the Store method for use with
@@ -59,6 +87,9 @@ type DescriptorAdder interface {
 	// AddFiles loads one or more files to create OCI descriptors with a specific
 	// media type and pushes them into underlying storage.
 	AddFiles(context.Context, string, ...string) ([]ocispec.Descriptor, error)
+	// AddWorkspaceFiles loads one or more files from a workspace to create OCI descriptors
+	// with a specific media type. File content is read through the workspace.
+	AddWorkspaceFiles(context.Context, string, workspace.Workspace, ...string) ([]ocispec.Descriptor, error)
 	// AddContent creates and stores a descriptor from content in bytes, a media type, and
 	// annotations.
 	AddContent(context.Context, string, []byte, map[string]string) (ocispec.Descriptor, error)
@@ -68,4 +99,7 @@ type DescriptorAdder interface {
 	// AddIndex creates and stores an index manifest for an image reference.
 	// This is generated from the config descriptor and artifact descriptors.
 	AddIndex(context.Context, string, map[string]string, ...ocispec.Descriptor) (ocispec.Descriptor, error)
+	// AddArtifact creates and stores an artifact manifest for an image reference with an artifact
+	// type. The subject descriptor is set as the subject of the artifact manifest.
+	AddArtifact(context.Context, string, string, ocispec.Descriptor, map[string]string, ...ocispec.Descriptor) (ocispec.Descriptor, error)
 }
@@ -0,0 +1,331 @@
+package orasclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	empspec "github.com/emporous/collection-spec/specs-go/v1alpha1"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2"
+	orascontent "oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/registry"
+	"oras.land/oras-go/v2/registry/remote"
+
+	"github.com/emporous/emporous-go/nodes/descriptor"
+	"github.com/emporous/emporous-go/nodes/descriptor/v2"
+	"github.com/emporous/emporous-go/registryclient"
+)
+
+// Copy performs a copy of OCI artifacts from a remote location to another remote location.
+// Linked collections are not copied.
+func (c *orasClient) Copy(ctx context.Context, src, dst string) (ocispec.Descriptor, error) {
+	if c.prePullFn != nil {
+		if err := c.prePullFn(ctx, src); err != nil {
+			return ocispec.Descriptor{}, err
+		}
+	}
+
+	srcRepo, err := c.setupRepo(src)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	dstRepo, err := c.setupRepo(dst)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+
+	// Create a copy of the options so the original copy
+	// options are not modified.
+	cCopyOpts := c.copyOptions(srcRepo.Reference.Registry, dstRepo.Reference.Registry)
+	cCopyOpts.FindSuccessors = successorFnWithSparseManifests
+
+	return oras.Copy(ctx, c.copySource(srcRepo), src, dstRepo, dst, cCopyOpts)
+}
+
+// CopyWithLinks performs a copy of OCI artifacts from a remote location to another remote location
+// and follows links to other artifacts. Linked collections are copied first and the link descriptors
+// and hints are rewritten to the copied collections. Manifests are only rewritten when links change,
+// so digests are preserved otherwise.
+func (c *orasClient) CopyWithLinks(ctx context.Context, src, dst string, mapper registryclient.LinkMapper) (ocispec.Descriptor, error) {
+	if mapper == nil {
+		return ocispec.Descriptor{}, fmt.Errorf("link mapper must be set to copy links")
+	}
+	lc := &linkCopier{
+		client: c,
+		mapper: mapper,
+		copied: map[string]ocispec.Descriptor{},
+	}
+	return lc.copyReference(ctx, src, dst)
+}
+
+// linkCopier copies collections and the collections they link to.
+type linkCopier struct {
+	client *orasClient
+	mapper registryclient.LinkMapper
+	// copied stores the destination descriptors of the
+	// collections that have been copied by source reference.
+	copied map[string]ocispec.Descriptor
+}
+
+// copyReference copies the collection at the source reference and tags
+// it with the destination reference if a tag is set.
+func (l *linkCopier) copyReference(ctx context.Context, src, dst string) (ocispec.Descriptor, error) {
+	if l.client.prePullFn != nil {
+		if err := l.client.prePullFn(ctx, src); err != nil {
+			return ocispec.Descriptor{}, err
+		}
+	}
+
+	srcRepo, err := l.client.setupRepo(src)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	dstRepo, err := l.client.setupRepo(dst)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+
+	root, err := srcRepo.Resolve(ctx, src)
+	if err != nil {
+		return ocispec.Descriptor{}, fmt.Errorf("reference %s: %w", src, err)
+	}
+	desc, err := l.copyNode(ctx, srcRepo, dstRepo, root)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+
+	ref, err := registry.ParseReference(dst)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	if ref.ValidateReferenceAsTag() == nil {
+		if err := dstRepo.Tag(ctx, desc, dst); err != nil {
+			return ocispec.Descriptor{}, err
+		}
+	}
+	return desc, nil
+}
+
+// copyNode copies the node described by desc and returns the descriptor of the copy.
+func (l *linkCopier) copyNode(ctx context.Context, srcRepo, dstRepo *remote.Repository, desc ocispec.Descriptor) (ocispec.Descriptor, error) {
+	var data []byte
+	var changed bool
+	var err error
+	switch desc.MediaType {
+	case ocispec.MediaTypeImageIndex:
+		data, changed, err = l.copyIndex(ctx, srcRepo, dstRepo, desc)
+	case ocispec.MediaTypeImageManifest:
+		data, changed, err = l.copyManifest(ctx, srcRepo, dstRepo, desc)
+	default:
+		cCopyGraphOpts := l.client.copyOptions(srcRepo.Reference.Registry, dstRepo.Reference.Registry).CopyGraphOptions
+		cCopyGraphOpts.FindSuccessors = successorFnWithSparseManifests
+		return desc, oras.CopyGraph(ctx, l.client.copySource(srcRepo), dstRepo, desc, cCopyGraphOpts)
+	}
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+
+	if changed {
+		desc.Digest = digest.FromBytes(data)
+		desc.Size = int64(len(data))
+	}
+	exists, err := dstRepo.Exists(ctx, desc)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	if !exists {
+		if err := dstRepo.Push(ctx, desc, bytes.NewReader(data)); err != nil {
+			return ocispec.Descriptor{}, err
+		}
+	}
+	return desc, nil
+}
+
+// copyIndex copies the manifests referenced by an index and returns the index content.
+// The index is rewritten if any of the manifests changed.
+func (l *linkCopier) copyIndex(ctx context.Context, srcRepo, dstRepo *remote.Repository, desc ocispec.Descriptor) ([]byte, bool, error) {
+	data, err := orascontent.FetchAll(ctx, srcRepo, desc)
+	if err != nil {
+		return nil, false, err
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, false, err
+	}
+	var manifests []ocispec.Descriptor
+	if err := json.Unmarshal(raw["manifests"], &manifests); err != nil {
+		return nil, false, err
+	}
+
+	var changed bool
+	for i, manifest := range manifests {
+		copied, err := l.copyNode(ctx, srcRepo, dstRepo, manifest)
+		if err != nil {
+			return nil, false, err
+		}
+		if copied.Digest != manifest.Digest {
+			manifests[i] = copied
+			changed = true
+		}
+	}
+	if !changed {
+		return data, false, nil
+	}
+
+	if raw["manifests"], err = json.Marshal(manifests); err != nil {
+		return nil, false, err
+	}
+	data, err = json.Marshal(raw)
+	return data, true, err
+}
+
+// copyManifest copies the blobs and linked collections referenced by a manifest and returns
+// the manifest content. The manifest is rewritten if any of the links changed.
+func (l *linkCopier) copyManifest(ctx context.Context, srcRepo, dstRepo *remote.Repository, desc ocispec.Descriptor) ([]byte, bool, error) {
+	data, err := orascontent.FetchAll(ctx, srcRepo, desc)
+	if err != nil {
+		return nil, false, err
+	}
+	var manifest ocispec.Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, false, err
+	}
+
+	blobs := append([]ocispec.Descriptor{manifest.Config}, manifest.Layers...)
+	for _, blob := range blobs {
+		if err := oras.CopyGraph(ctx, l.client.copySource(srcRepo), dstRepo, blob, l.client.copyOptions(srcRepo.Reference.Registry, dstRepo.Reference.Registry).CopyGraphOptions); err != nil {
+			return nil, false, err
+		}
+	}
+
+	links, ok := manifest.Annotations[empspec.AnnotationLink]
+	if !ok {
+		return data, false, nil
+	}
+	rewritten, changed, err := l.copyLinks(ctx, links)
+	if err != nil || !changed {
+		return data, false, err
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, false, err
+	}
+	manifest.Annotations[empspec.AnnotationLink] = rewritten
+	if raw["annotations"], err = json.Marshal(manifest.Annotations); err != nil {
+		return nil, false, err
+	}
+	data, err = json.Marshal(raw)
+	return data, true, err
+}
+
+// copyLinks copies the linked collections in the link annotation value and
+// returns the annotation value for the copied collections.
+func (l *linkCopier) copyLinks(ctx context.Context, links string) (string, bool, error) {
+	var descs []ocispec.Descriptor
+	if err := json.Unmarshal([]byte(links), &descs); err != nil {
+		return "", false, err
+	}
+
+	var changed bool
+	for i, desc := range descs {
+		node, err := v2.NewNode(desc.Digest.String(), desc)
+		if err != nil {
+			return "", false, err
+		}
+		if node.Properties == nil || !node.Properties.IsALink() {
+			continue
+		}
+		hints := node.Properties.Link
+		// Links without hints are in the same repository as the collection.
+		if hints.RegistryHint == "" && hints.NamespaceHint == "" {
+			continue
+		}
+
+		srcRepository := fmt.Sprintf("%s/%s", hints.RegistryHint, hints.NamespaceHint)
+		dstRepository, err := l.mapper(srcRepository)
+		if err != nil {
+			return "", false, fmt.Errorf("link %s: %w", srcRepository, err)
+		}
+		dst, err := registry.ParseReference(dstRepository)
+		if err != nil {
+			return "", false, fmt.Errorf("link %s: %w", srcRepository, err)
+		}
+
+		src := fmt.Sprintf("%s@%s", srcRepository, desc.Digest)
+		copied, ok := l.copied[src]
+		if !ok {
+			// Linked collections are referenced by digest, so the copies are not tagged.
+			copied, err = l.copyReference(ctx, src, fmt.Sprintf("%s/%s", dst.Registry, dst.Repository))
+			if err != nil {
+				return "", false, fmt.Errorf("link %s: %w", src, err)
+			}
+			l.copied[src] = copied
+		}
+
+		if copied.Digest == desc.Digest && dst.Registry == hints.RegistryHint && dst.Repository == hints.NamespaceHint {
+			continue
+		}
+		changed = true
+		newHints := *hints
+		newHints.RegistryHint = dst.Registry
+		newHints.NamespaceHint = dst.Repository
+		desc.Digest = copied.Digest
+		desc.Size = copied.Size
+		if desc.Annotations, err = setLinkAttributes(desc.Annotations, newHints); err != nil {
+			return "", false, err
+		}
+		descs[i] = desc
+	}
+	if !changed {
+		return links, false, nil
+	}
+
+	rewritten, err := canonicalJSON(descs)
+	return string(rewritten), true, err
+}
+
+// setLinkAttributes returns a copy of the annotations with the
+// core-link attributes replaced.
+func setLinkAttributes(annotations map[string]string, link empspec.LinkAttributes) (map[string]string, error) {
+	var attrs map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(annotations[empspec.AnnotationEmporousAttributes]), &attrs); err != nil {
+		return nil, err
+	}
+	linkJSON, err := json.Marshal(link)
+	if err != nil {
+		return nil, err
+	}
+	attrs[descriptor.TypeLink] = linkJSON
+	attrsJSON, err := canonicalJSON(attrs)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := make(map[string]string, len(annotations))
+	for key, value := range annotations {
+		updated[key] = value
+	}
+	updated[empspec.AnnotationEmporousAttributes] = string(attrsJSON)
+	return updated, nil
+}
+
+// canonicalJSON encodes the value with sorted keys and no
+// insignificant whitespace, matching annotations written during a build.
+func canonicalJSON(v interface{}) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(strings.NewReader(string(data)))
+	decoder.UseNumber()
+	var generic interface{}
+	if err := decoder.Decode(&generic); err != nil {
+		return nil, err
+	}
+	return json.Marshal(generic)
+}
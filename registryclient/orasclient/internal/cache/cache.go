@@ -2,12 +2,18 @@ package cache
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"io"
+	"os"
+	"path/filepath"
 	"sync"
 
+	"github.com/opencontainers/go-digest"
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"oras.land/oras-go/v2"
 	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/errdef"
 )
 
 // Pulled from https://github.com/oras-project/oras/blob/main/internal/cache/target.go
@@ -36,13 +42,24 @@ func (fn closer) Close() error {
 type target struct {
 	oras.Target
 	cache content.Storage
+	// ingestDir persists partially fetched blobs.
+	ingestDir string
+
+	mu sync.Mutex
+	// ingesting tracks the blobs with an open ingest file.
+	ingesting map[digest.Digest]bool
 }
 
-// New generates a new target storage with caching.
-func New(source oras.Target, cache content.Storage) oras.Target {
+// New generates a new target storage with caching. If ingestDir is set, blobs are
+// written to the directory as they are fetched and moved to the cache once verified.
+// An interrupted fetch resumes from the bytes persisted in the directory using a range
+// request if the source supports it.
+func New(source oras.Target, cache content.Storage, ingestDir string) oras.Target {
 	return &target{
-		Target: source,
-		cache:  cache,
+		Target:    source,
+		cache:     cache,
+		ingestDir: ingestDir,
+		ingesting: map[digest.Digest]bool{},
 	}
 }
 
@@ -53,7 +70,23 @@ func (p *target) Fetch(ctx context.Context, target ocispec.Descriptor) (io.ReadC
 		return rc, nil
 	}
 
-	rc, err = p.Target.Fetch(ctx, target)
+	// Blobs being ingested by another fetch are streamed
+	// without persisting the partial content.
+	if p.ingestDir == "" || target.Digest.Validate() != nil || !p.acquire(target.Digest) {
+		return p.fetch(ctx, target)
+	}
+	rc, err = p.fetchResumable(ctx, target)
+	if err != nil {
+		p.release(target.Digest)
+		return nil, err
+	}
+	return rc, nil
+}
+
+// fetch fetches the content from the source and pushes
+// it to the cache as it is read.
+func (p *target) fetch(ctx context.Context, target ocispec.Descriptor) (io.ReadCloser, error) {
+	rc, err := p.Target.Fetch(ctx, target)
 	if err != nil {
 		return nil, err
 	}
@@ -86,6 +119,154 @@ func (p *target) Fetch(ctx context.Context, target ocispec.Descriptor) (io.ReadC
 	}, nil
 }
 
+// fetchResumable fetches the content from the source starting after the bytes persisted in the
+// ingest file. The persisted bytes are read first, followed by the remaining bytes from the source,
+// which are appended to the ingest file. The blob is verified and moved to the cache on close once
+// all bytes are persisted. The ingest file is kept for the next fetch if the content is not fully read.
+func (p *target) fetchResumable(ctx context.Context, target ocispec.Descriptor) (io.ReadCloser, error) {
+	path := p.ingestPath(target)
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0640)
+	if err != nil {
+		return nil, err
+	}
+	rc, offset, err := p.resume(ctx, target, f)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	c := closer(func() error {
+		defer p.release(target.Digest)
+		rcErr := rc.Close()
+		info, err := f.Stat()
+		if err != nil {
+			f.Close()
+			return err
+		}
+		if info.Size() < target.Size {
+			// The content was not fully read, so the
+			// ingest file is kept to resume from.
+			if err := f.Close(); err != nil {
+				return err
+			}
+			return rcErr
+		}
+		if err := p.commit(ctx, target, f); err != nil {
+			return err
+		}
+		return rcErr
+	})
+
+	// The file offset is at the end of the persisted bytes after
+	// they are read, so the remaining bytes are appended.
+	return struct {
+		io.Reader
+		io.Closer
+	}{
+		Reader: io.MultiReader(io.LimitReader(f, offset), io.TeeReader(rc, f)),
+		Closer: c,
+	}, nil
+}
+
+// resume fetches the content from the source after the bytes persisted in the ingest file
+// and returns the number of persisted bytes. If the source cannot seek to the offset, the
+// ingest file is truncated and the content is fetched from the start.
+func (p *target) resume(ctx context.Context, target ocispec.Descriptor, f *os.File) (io.ReadCloser, int64, error) {
+	info, err := f.Stat()
+	if err != nil {
+		return nil, 0, err
+	}
+	offset := info.Size()
+	if offset >= target.Size {
+		offset = 0
+	}
+
+	rc, err := p.Target.Fetch(ctx, target)
+	if err != nil {
+		return nil, 0, err
+	}
+	if offset > 0 {
+		seeker, ok := rc.(io.Seeker)
+		if ok {
+			_, err = seeker.Seek(offset, io.SeekStart)
+		}
+		if !ok || err != nil {
+			// Restart the fetch since the
+			// reader position is unknown.
+			if err := rc.Close(); err != nil {
+				return nil, 0, err
+			}
+			if rc, err = p.Target.Fetch(ctx, target); err != nil {
+				return nil, 0, err
+			}
+			offset = 0
+		}
+	}
+
+	if err := f.Truncate(offset); err != nil {
+		rc.Close()
+		return nil, 0, err
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		rc.Close()
+		return nil, 0, err
+	}
+	return rc, offset, nil
+}
+
+// commit verifies the content of the ingest file, pushes
+// it to the cache and removes the ingest file.
+func (p *target) commit(ctx context.Context, target ocispec.Descriptor, f *os.File) error {
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	verifier := target.Digest.Verifier()
+	if _, err := io.Copy(verifier, f); err != nil {
+		return err
+	}
+	if !verifier.Verified() {
+		return fmt.Errorf("%s: %w", target.Digest, content.ErrMismatchedDigest)
+	}
+
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	if err := p.cache.Push(ctx, target, f); err != nil && !errors.Is(err, errdef.ErrAlreadyExists) {
+		return err
+	}
+	return nil
+}
+
+// ingestPath returns the path of the ingest file for the descriptor.
+func (p *target) ingestPath(target ocispec.Descriptor) string {
+	return filepath.Join(p.ingestDir, target.Digest.Algorithm().String(), target.Digest.Encoded())
+}
+
+// acquire marks the blob as ingesting. It returns
+// false if the blob is already being ingested.
+func (p *target) acquire(dgst digest.Digest) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.ingesting[dgst] {
+		return false
+	}
+	p.ingesting[dgst] = true
+	return true
+}
+
+// release marks the blob as no longer ingesting.
+func (p *target) release(dgst digest.Digest) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.ingesting, dgst)
+}
+
 // Exists returns true if the described content exists.
 func (p *target) Exists(ctx context.Context, desc ocispec.Descriptor) (bool, error) {
 	exists, err := p.cache.Exists(ctx, desc)
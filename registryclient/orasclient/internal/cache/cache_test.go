@@ -0,0 +1,159 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/content/memory"
+)
+
+var errInterrupted = errors.New("interrupted")
+
+// source serves the data and counts the bytes read. If seekable, fetched
+// readers can seek. If failAfter is positive, reads fail after that many bytes.
+type source struct {
+	oras.Target
+	data      []byte
+	seekable  bool
+	failAfter int
+	read      int
+}
+
+func (s *source) Fetch(_ context.Context, _ ocispec.Descriptor) (io.ReadCloser, error) {
+	r := &sourceReader{Reader: bytes.NewReader(s.data), source: s, remaining: s.failAfter}
+	if s.seekable {
+		return r, nil
+	}
+	return ioutil.NopCloser(r), nil
+}
+
+type sourceReader struct {
+	*bytes.Reader
+	source    *source
+	remaining int
+}
+
+func (r *sourceReader) Read(p []byte) (int, error) {
+	if r.source.failAfter > 0 {
+		if r.remaining == 0 {
+			return 0, errInterrupted
+		}
+		if len(p) > r.remaining {
+			p = p[:r.remaining]
+		}
+	}
+	n, err := r.Reader.Read(p)
+	r.remaining -= n
+	r.source.read += n
+	return n, err
+}
+
+func (r *sourceReader) Close() error {
+	return nil
+}
+
+func TestFetchResumable(t *testing.T) {
+	ctx := context.TODO()
+	data := bytes.Repeat([]byte("emporous"), 1024)
+	desc := ocispec.Descriptor{
+		MediaType: "text/plain",
+		Digest:    digest.FromBytes(data),
+		Size:      int64(len(data)),
+	}
+
+	type spec struct {
+		name       string
+		partial    []byte
+		seekable   bool
+		failAfter  int
+		expRead    int
+		expCached  bool
+		expPartial []byte
+		expError   error
+	}
+
+	cases := []spec{
+		{
+			name:       "Success/Interrupted",
+			seekable:   true,
+			failAfter:  1000,
+			expRead:    1000,
+			expPartial: data[:1000],
+			expError:   errInterrupted,
+		},
+		{
+			name:      "Success/Resumed",
+			partial:   data[:1000],
+			seekable:  true,
+			expRead:   len(data) - 1000,
+			expCached: true,
+		},
+		{
+			name:      "Success/RestartedWithoutSeek",
+			partial:   data[:1000],
+			expRead:   len(data),
+			expCached: true,
+		},
+		{
+			name:     "Failure/CorruptPartial",
+			partial:  bytes.Repeat([]byte("x"), 1000),
+			seekable: true,
+			expRead:  len(data) - 1000,
+			expError: content.ErrMismatchedDigest,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ingestDir := t.TempDir()
+			path := filepath.Join(ingestDir, desc.Digest.Algorithm().String(), desc.Digest.Encoded())
+			if c.partial != nil {
+				require.NoError(t, os.MkdirAll(filepath.Dir(path), 0750))
+				require.NoError(t, ioutil.WriteFile(path, c.partial, 0640))
+			}
+
+			src := &source{Target: memory.New(), data: data, seekable: c.seekable, failAfter: c.failAfter}
+			cache := memory.New()
+			rc, err := New(src, cache, ingestDir).Fetch(ctx, desc)
+			require.NoError(t, err)
+			got, readErr := ioutil.ReadAll(rc)
+			closeErr := rc.Close()
+			switch {
+			case errors.Is(c.expError, errInterrupted):
+				require.ErrorIs(t, readErr, c.expError)
+				require.NoError(t, closeErr)
+			case c.expError != nil:
+				require.NoError(t, readErr)
+				require.ErrorIs(t, closeErr, c.expError)
+			default:
+				require.NoError(t, readErr)
+				require.NoError(t, closeErr)
+				require.Equal(t, data, got)
+			}
+			require.Equal(t, c.expRead, src.read)
+
+			exists, err := cache.Exists(ctx, desc)
+			require.NoError(t, err)
+			require.Equal(t, c.expCached, exists)
+
+			partial, err := ioutil.ReadFile(path)
+			if c.expPartial == nil {
+				require.ErrorIs(t, err, os.ErrNotExist)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, c.expPartial, partial)
+			}
+		})
+	}
+}
@@ -0,0 +1,320 @@
+package upload
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/registry/remote"
+	"oras.land/oras-go/v2/registry/remote/auth"
+)
+
+// manifestMediaTypes are pushed to the
+// repository without a chunked upload.
+var manifestMediaTypes = map[string]bool{
+	"application/vnd.docker.distribution.manifest.v2+json":      true,
+	"application/vnd.docker.distribution.manifest.list.v2+json": true,
+	ocispec.MediaTypeImageManifest:                              true,
+	ocispec.MediaTypeImageIndex:                                 true,
+	ocispec.MediaTypeArtifactManifest:                           true,
+}
+
+// session is the persisted state of a chunked upload.
+type session struct {
+	// Location is the URL of the upload session.
+	Location string `json:"location"`
+}
+
+// target pushes blobs to a remote repository with chunked uploads.
+type target struct {
+	*remote.Repository
+	dir       string
+	chunkSize int64
+}
+
+// New returns a target that pushes blobs of at least chunkSize bytes to the repository
+// in chunks. The upload session of each blob is persisted in dir after every chunk,
+// so a push interrupted by an error resumes from the bytes the registry has received.
+// Blobs are pushed in a single request if the registry rejects the first chunk.
+func New(repo *remote.Repository, dir string, chunkSize int64) oras.Target {
+	return &target{
+		Repository: repo,
+		dir:        dir,
+		chunkSize:  chunkSize,
+	}
+}
+
+// Push pushes the content, matching the expected descriptor.
+func (t *target) Push(ctx context.Context, expected ocispec.Descriptor, content io.Reader) error {
+	if manifestMediaTypes[expected.MediaType] || expected.Size < t.chunkSize || expected.Digest.Validate() != nil {
+		return t.Repository.Push(ctx, expected, content)
+	}
+
+	ctx = auth.AppendScopes(ctx, auth.ScopeRepository(t.Reference.Repository, auth.ActionPull, auth.ActionPush))
+	path := t.sessionPath(expected)
+	location, offset, err := t.resume(ctx, path)
+	if err != nil {
+		return err
+	}
+	if location == nil {
+		if location, err = t.start(ctx); err != nil {
+			return err
+		}
+		if err := saveSession(path, location); err != nil {
+			return err
+		}
+	}
+
+	// Skip the bytes received by the registry.
+	if _, err := io.CopyN(ioutil.Discard, content, offset); err != nil {
+		return fmt.Errorf("%s: resume upload: %w", expected.Digest, err)
+	}
+	chunk := make([]byte, t.chunkSize)
+	for offset < expected.Size {
+		size := t.chunkSize
+		if remaining := expected.Size - offset; remaining < size {
+			size = remaining
+		}
+		n, err := io.ReadFull(content, chunk[:size])
+		if err != nil {
+			return fmt.Errorf("%s: read content: %w", expected.Digest, err)
+		}
+		next, err := t.patch(ctx, location, offset, chunk[:n])
+		if err != nil {
+			if offset == 0 && chunksUnsupported(err) {
+				// The registry does not support chunked uploads.
+				os.Remove(path)
+				return t.Repository.Push(ctx, expected, io.MultiReader(bytes.NewReader(chunk[:n]), content))
+			}
+			return err
+		}
+		location = next
+		offset += int64(n)
+		if err := saveSession(path, location); err != nil {
+			return err
+		}
+	}
+
+	if err := t.commit(ctx, location, expected.Digest); err != nil {
+		// An upload rejected by the registry cannot be resumed.
+		os.Remove(path)
+		return err
+	}
+	return os.Remove(path)
+}
+
+// resume returns the location of the persisted upload session and the number of bytes
+// the registry has received. If there is no session to resume, the location is nil.
+func (t *target) resume(ctx context.Context, path string) (*url.URL, int64, error) {
+	data, err := ioutil.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return nil, 0, nil
+	case err != nil:
+		return nil, 0, err
+	}
+	var s session
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, 0, nil
+	}
+	location, err := url.Parse(s.Location)
+	if err != nil {
+		return nil, 0, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, location.String(), nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	resp, err := t.client().Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+	// Sessions that expired or are not supported by the registry are started over.
+	if resp.StatusCode != http.StatusNoContent {
+		return nil, 0, nil
+	}
+	offset, err := parseRange(resp.Header.Get("Range"))
+	if err != nil {
+		return nil, 0, nil
+	}
+	if resp.Header.Get("Location") == "" {
+		return location, offset, nil
+	}
+	next, err := nextLocation(resp, location)
+	if err != nil {
+		return nil, 0, err
+	}
+	return next, offset, nil
+}
+
+// start starts an upload session and returns its location.
+func (t *target) start(ctx context.Context) (*url.URL, error) {
+	scheme := "https"
+	if t.PlainHTTP {
+		scheme = "http"
+	}
+	uploads := &url.URL{
+		Scheme: scheme,
+		Host:   t.Reference.Host(),
+		Path:   fmt.Sprintf("/v2/%s/blobs/uploads/", t.Reference.Repository),
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, uploads.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := t.client().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted {
+		return nil, statusError(resp)
+	}
+	return nextLocation(resp, uploads)
+}
+
+// patch uploads the chunk starting at offset and returns
+// the location to upload the next chunk to.
+func (t *target) patch(ctx context.Context, location *url.URL, offset int64, chunk []byte) (*url.URL, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPatch, location.String(), bytes.NewReader(chunk))
+	if err != nil {
+		return nil, err
+	}
+	req.ContentLength = int64(len(chunk))
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("Content-Range", fmt.Sprintf("%d-%d", offset, offset+int64(len(chunk))-1))
+	resp, err := t.client().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted && resp.StatusCode != http.StatusNoContent {
+		return nil, statusError(resp)
+	}
+	return nextLocation(resp, location)
+}
+
+// commit completes the upload session with the digest of the content.
+func (t *target) commit(ctx context.Context, location *url.URL, dgst digest.Digest) error {
+	committed := *location
+	query := committed.Query()
+	query.Set("digest", dgst.String())
+	committed.RawQuery = query.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, committed.String(), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := t.client().Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		return statusError(resp)
+	}
+	return nil
+}
+
+// client returns the HTTP client of the repository.
+func (t *target) client() remote.Client {
+	if t.Client == nil {
+		return auth.DefaultClient
+	}
+	return t.Client
+}
+
+// sessionPath returns the path of the upload session for the descriptor
+// in the repository.
+func (t *target) sessionPath(desc ocispec.Descriptor) string {
+	repository := digest.FromString(t.Reference.Host() + "/" + t.Reference.Repository)
+	return filepath.Join(t.dir, repository.Encoded(), desc.Digest.Algorithm().String(), desc.Digest.Encoded())
+}
+
+// saveSession persists the location of the upload session.
+func saveSession(path string, location *url.URL) error {
+	data, err := json.Marshal(session{Location: location.String()})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0600)
+}
+
+// nextLocation resolves the location in the response
+// relative to the request URL.
+func nextLocation(resp *http.Response, base *url.URL) (*url.URL, error) {
+	location := resp.Header.Get("Location")
+	if location == "" {
+		return nil, fmt.Errorf("%s %q: missing Location header", resp.Request.Method, resp.Request.URL)
+	}
+	next, err := base.Parse(location)
+	if err != nil {
+		return nil, fmt.Errorf("%s %q: invalid Location header: %w", resp.Request.Method, resp.Request.URL, err)
+	}
+	return next, nil
+}
+
+// parseRange parses the Range header of an upload
+// session and returns the number of bytes received.
+func parseRange(value string) (int64, error) {
+	var start, end int64
+	if _, err := fmt.Sscanf(value, "%d-%d", &start, &end); err != nil {
+		return 0, fmt.Errorf("invalid Range header %q: %w", value, err)
+	}
+	if start != 0 || end < 0 {
+		return 0, fmt.Errorf("invalid Range header %q", value)
+	}
+	return end + 1, nil
+}
+
+// errUnexpectedStatus describes an unexpected response status.
+type errUnexpectedStatus struct {
+	method     string
+	url        string
+	statusCode int
+	body       []byte
+}
+
+func (e *errUnexpectedStatus) Error() string {
+	return fmt.Sprintf("%s %q: unexpected status code %d: %s", e.method, e.url, e.statusCode, e.body)
+}
+
+// statusError returns an error describing the unexpected response status.
+func statusError(resp *http.Response) error {
+	body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+	return &errUnexpectedStatus{
+		method:     resp.Request.Method,
+		url:        resp.Request.URL.String(),
+		statusCode: resp.StatusCode,
+		body:       bytes.TrimSpace(body),
+	}
+}
+
+// chunksUnsupported returns whether the error is a response
+// of a registry that does not accept chunked uploads.
+func chunksUnsupported(err error) bool {
+	var statusErr *errUnexpectedStatus
+	if !errors.As(err, &statusErr) {
+		return false
+	}
+	switch statusErr.statusCode {
+	case http.StatusBadRequest, http.StatusNotFound, http.StatusRequestedRangeNotSatisfiable:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,195 @@
+package upload
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/registry/remote"
+)
+
+// uploadRecorder serves the upload session status, which the test registry does not
+// support, counts the bytes received by PATCH requests and fails the failPatch request.
+// If rejectPatch is set, every PATCH request is answered with the status code.
+type uploadRecorder struct {
+	handler     http.Handler
+	mu          sync.Mutex
+	ranges      map[string]string
+	patches     int
+	patched     int64
+	failPatch   int
+	rejectPatch int
+}
+
+func (u *uploadRecorder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.Contains(r.URL.Path, "/blobs/uploads/") {
+		u.handler.ServeHTTP(w, r)
+		return
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	switch r.Method {
+	case http.MethodGet:
+		rng, ok := u.ranges[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Location", r.URL.Path)
+		w.Header().Set("Range", rng)
+		w.WriteHeader(http.StatusNoContent)
+	case http.MethodPatch:
+		u.patches++
+		if u.rejectPatch != 0 {
+			w.WriteHeader(u.rejectPatch)
+			return
+		}
+		if u.patches == u.failPatch {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		u.patched += r.ContentLength
+		rec := httptest.NewRecorder()
+		u.handler.ServeHTTP(rec, r)
+		u.ranges[r.URL.Path] = rec.Header().Get("Range")
+		for key, values := range rec.Header() {
+			w.Header()[key] = values
+		}
+		w.WriteHeader(rec.Code)
+		_, _ = w.Write(rec.Body.Bytes())
+	default:
+		u.handler.ServeHTTP(w, r)
+	}
+}
+
+func TestPush(t *testing.T) {
+	ctx := context.TODO()
+	data := bytes.Repeat([]byte("emporous"), 625)
+	desc := ocispec.Descriptor{
+		MediaType: "text/plain",
+		Digest:    digest.FromBytes(data),
+		Size:      int64(len(data)),
+	}
+	const chunkSize = 1024
+
+	type spec struct {
+		name string
+		desc ocispec.Descriptor
+		data []byte
+		// failPatch fails the PATCH request of the first push.
+		failPatch int
+		// staleSession persists an unknown upload session.
+		staleSession bool
+		// rejectPatch rejects PATCH requests with the status code.
+		rejectPatch int
+		expPatches  int
+		expPatched  int64
+	}
+
+	cases := []spec{
+		{
+			name:       "Success/Chunked",
+			desc:       desc,
+			data:       data,
+			expPatches: 5,
+			expPatched: desc.Size,
+		},
+		{
+			name:       "Success/ResumedAfterFailure",
+			desc:       desc,
+			data:       data,
+			failPatch:  3,
+			expPatches: 6,
+			expPatched: desc.Size,
+		},
+		{
+			name:         "Success/StaleSession",
+			desc:         desc,
+			data:         data,
+			staleSession: true,
+			expPatches:   5,
+			expPatched:   desc.Size,
+		},
+		{
+			name:        "Success/FallbackWhenChunksRejected",
+			desc:        desc,
+			data:        data,
+			rejectPatch: http.StatusNotFound,
+			expPatches:  1,
+		},
+		{
+			name:        "Success/FallbackWhenRangeRejected",
+			desc:        desc,
+			data:        data,
+			rejectPatch: http.StatusRequestedRangeNotSatisfiable,
+			expPatches:  1,
+		},
+		{
+			name: "Success/Monolithic",
+			desc: ocispec.Descriptor{
+				MediaType: "text/plain",
+				Digest:    digest.FromBytes([]byte("small")),
+				Size:      5,
+			},
+			data: []byte("small"),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			recorder := &uploadRecorder{
+				handler:     registry.New(),
+				ranges:      map[string]string{},
+				failPatch:   c.failPatch,
+				rejectPatch: c.rejectPatch,
+			}
+			server := httptest.NewServer(recorder)
+			t.Cleanup(server.Close)
+			u, err := url.Parse(server.URL)
+			require.NoError(t, err)
+
+			repo, err := remote.NewRepository(u.Host + "/test")
+			require.NoError(t, err)
+			repo.PlainHTTP = true
+			dir := t.TempDir()
+			tgt := New(repo, dir, chunkSize).(*target)
+			path := tgt.sessionPath(c.desc)
+
+			if c.staleSession {
+				location := &url.URL{Scheme: "http", Host: u.Host, Path: "/v2/test/blobs/uploads/unknown"}
+				require.NoError(t, saveSession(path, location))
+			}
+
+			if c.failPatch != 0 {
+				require.Error(t, tgt.Push(ctx, c.desc, bytes.NewReader(c.data)))
+				_, err := os.Stat(path)
+				require.NoError(t, err)
+			}
+			require.NoError(t, tgt.Push(ctx, c.desc, bytes.NewReader(c.data)))
+			require.Equal(t, c.expPatches, recorder.patches)
+			require.Equal(t, c.expPatched, recorder.patched)
+
+			_, err = os.Stat(path)
+			require.ErrorIs(t, err, os.ErrNotExist)
+
+			rc, err := repo.Fetch(ctx, c.desc)
+			require.NoError(t, err)
+			defer rc.Close()
+			got, err := content.ReadAll(rc, c.desc)
+			require.NoError(t, err)
+			require.Equal(t, c.data, got)
+		})
+	}
+}
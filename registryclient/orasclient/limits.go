@@ -0,0 +1,110 @@
+package orasclient
+
+import (
+	"context"
+	"io"
+	"net/http"
+
+	"golang.org/x/time/rate"
+)
+
+// minRateLimitBurst is the minimum number of bytes that can be
+// transferred at once when a rate limit is set, so low limits
+// do not split transfers into tiny reads.
+const minRateLimitBurst = 32 * 1024
+
+// newRateLimiter returns a limiter allowing the bytes per second.
+func newRateLimiter(bytesPerSecond int64) *rate.Limiter {
+	burst := int(bytesPerSecond)
+	if burst < minRateLimitBurst {
+		burst = minRateLimitBurst
+	}
+	return rate.NewLimiter(rate.Limit(bytesPerSecond), burst)
+}
+
+// rateLimitedTransport limits the bytes per second of request and
+// response bodies. Requests to registries with their own limiter use
+// that limiter. Other requests share the global limiter, if set.
+type rateLimitedTransport struct {
+	base       http.RoundTripper
+	global     *rate.Limiter
+	registries map[string]*rate.Limiter
+}
+
+// newRateLimitedTransport returns a transport that limits the base transport
+// to the global rate limit and the per-registry rate limits by host.
+func newRateLimitedTransport(base http.RoundTripper, global int64, registries map[string]int64) *rateLimitedTransport {
+	t := &rateLimitedTransport{
+		base:       base,
+		registries: map[string]*rate.Limiter{},
+	}
+	if global > 0 {
+		t.global = newRateLimiter(global)
+	}
+	for host, limit := range registries {
+		t.registries[host] = newRateLimiter(limit)
+	}
+	return t
+}
+
+// RoundTrip executes a single HTTP transaction with the request and response bodies rate limited.
+func (t *rateLimitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	limiter, ok := t.registries[registryHost(req)]
+	if !ok {
+		limiter = t.global
+	}
+	if limiter == nil {
+		return t.base.RoundTrip(req)
+	}
+
+	if req.Body != nil && req.Body != http.NoBody {
+		req = req.Clone(req.Context())
+		req.Body = &rateLimitedReader{
+			ReadCloser: req.Body,
+			ctx:        req.Context(),
+			limiter:    limiter,
+		}
+	}
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = &rateLimitedReader{
+		ReadCloser: resp.Body,
+		ctx:        req.Context(),
+		limiter:    limiter,
+	}
+	return resp, nil
+}
+
+// registryHost returns the host of the registry the request was sent to. Redirected
+// requests, such as blob downloads from storage backends, return the host of the
+// request that was redirected.
+func registryHost(req *http.Request) string {
+	for req.Response != nil && req.Response.Request != nil {
+		req = req.Response.Request
+	}
+	return req.URL.Host
+}
+
+// rateLimitedReader waits for the limiter to allow the bytes read.
+type rateLimitedReader struct {
+	io.ReadCloser
+	ctx     context.Context
+	limiter *rate.Limiter
+}
+
+func (r *rateLimitedReader) Read(p []byte) (int, error) {
+	// Reads are capped at the burst size, so the
+	// limiter can always allow the bytes read.
+	if burst := r.limiter.Burst(); len(p) > burst {
+		p = p[:burst]
+	}
+	n, err := r.ReadCloser.Read(p)
+	if n > 0 {
+		if waitErr := r.limiter.WaitN(r.ctx, n); waitErr != nil {
+			return n, waitErr
+		}
+	}
+	return n, err
+}
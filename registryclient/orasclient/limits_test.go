@@ -0,0 +1,178 @@
+package orasclient
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"oras.land/oras-go/v2"
+)
+
+func TestRateLimitedTransport(t *testing.T) {
+	body := bytes.Repeat([]byte("a"), 2*minRateLimitBurst)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(ioutil.Discard, r.Body)
+		_, _ = w.Write(body)
+	})
+	unlimited := httptest.NewServer(handler)
+	t.Cleanup(unlimited.Close)
+	// The limited registry redirects requests to /redirect
+	// to the unlimited server, like a storage backend.
+	limited := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/redirect" {
+			http.Redirect(w, r, unlimited.URL, http.StatusTemporaryRedirect)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(limited.Close)
+
+	limitedURL, err := url.Parse(limited.URL)
+	require.NoError(t, err)
+
+	// The registry limit overrides the global limit. The limiter allows one
+	// burst immediately, so the second burst waits for about a second.
+	transport := newRateLimitedTransport(http.DefaultTransport, 1<<30, map[string]int64{
+		limitedURL.Host: minRateLimitBurst,
+	})
+	client := &http.Client{Transport: transport}
+
+	type spec struct {
+		name     string
+		url      string
+		upload   bool
+		expLimit bool
+	}
+
+	cases := []spec{
+		{
+			name:     "Success/DownloadLimitedByRegistry",
+			url:      limited.URL,
+			expLimit: true,
+		},
+		{
+			name: "Success/DownloadLimitedGlobally",
+			url:  unlimited.URL,
+		},
+		{
+			name:     "Success/RedirectLimitedByRegistry",
+			url:      limited.URL + "/redirect",
+			expLimit: true,
+		},
+		{
+			name:     "Success/UploadLimitedByRegistry",
+			url:      limited.URL,
+			upload:   true,
+			expLimit: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// Each case starts with a full burst.
+			transport.registries[limitedURL.Host] = newRateLimiter(minRateLimitBurst)
+
+			start := time.Now()
+			var resp *http.Response
+			if c.upload {
+				resp, err = client.Post(c.url, "application/octet-stream", bytes.NewReader(body))
+			} else {
+				resp, err = client.Get(c.url)
+			}
+			require.NoError(t, err)
+			data, err := ioutil.ReadAll(resp.Body)
+			require.NoError(t, err)
+			require.NoError(t, resp.Body.Close())
+			require.Equal(t, body, data)
+
+			elapsed := time.Since(start)
+			if c.expLimit {
+				require.GreaterOrEqual(t, elapsed, 900*time.Millisecond)
+			} else {
+				require.Less(t, elapsed, 900*time.Millisecond)
+			}
+		})
+	}
+}
+
+func TestTransferOptions(t *testing.T) {
+	type spec struct {
+		name           string
+		opts           []ClientOption
+		registries     []string
+		expConcurrency int64
+		expError       string
+	}
+
+	cases := []spec{
+		{
+			name:           "Success/Default",
+			registries:     []string{"localhost:5000"},
+			expConcurrency: oras.DefaultCopyOptions.Concurrency,
+		},
+		{
+			name:           "Success/Concurrency",
+			opts:           []ClientOption{WithConcurrency(8)},
+			registries:     []string{"localhost:5000"},
+			expConcurrency: 8,
+		},
+		{
+			name: "Success/RegistryConcurrency",
+			opts: []ClientOption{
+				WithConcurrency(2),
+				WithRegistryConcurrency("localhost:5000", 10),
+			},
+			registries:     []string{"localhost:5000"},
+			expConcurrency: 10,
+		},
+		{
+			name: "Success/LowestRegistryConcurrency",
+			opts: []ClientOption{
+				WithRegistryConcurrency("localhost:5000", 10),
+				WithRegistryConcurrency("localhost:5001", 4),
+			},
+			registries:     []string{"localhost:5000", "localhost:5001"},
+			expConcurrency: 4,
+		},
+		{
+			name:     "Failure/InvalidConcurrency",
+			opts:     []ClientOption{WithConcurrency(0)},
+			expError: "concurrency must be at least 1, got 0",
+		},
+		{
+			name:     "Failure/InvalidRegistryConcurrency",
+			opts:     []ClientOption{WithRegistryConcurrency("localhost:5000", -1)},
+			expError: "registry localhost:5000: concurrency must be at least 1, got -1",
+		},
+		{
+			name:     "Failure/InvalidRateLimit",
+			opts:     []ClientOption{WithRateLimit(0)},
+			expError: "rate limit must be at least 1 byte per second, got 0",
+		},
+		{
+			name:     "Failure/InvalidRegistryRateLimit",
+			opts:     []ClientOption{WithRegistryRateLimit("localhost:5000", 0)},
+			expError: "registry localhost:5000: rate limit must be at least 1 byte per second, got 0",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			client, err := NewClient(c.opts...)
+			if c.expError != "" {
+				require.EqualError(t, err, c.expError)
+				return
+			}
+			require.NoError(t, err)
+			defer client.Destroy()
+			opts := client.(*orasClient).copyOptions(c.registries...)
+			require.Equal(t, c.expConcurrency, opts.Concurrency)
+		})
+	}
+}
@@ -3,8 +3,10 @@ package orasclient
 import (
 	"context"
 	"crypto/tls"
+	"fmt"
 	"net/http"
 	"sync"
+	"time"
 
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"oras.land/oras-go/v2/registry/remote/auth"
@@ -16,6 +18,7 @@ import (
 	"github.com/emporous/emporous-go/content"
 	"github.com/emporous/emporous-go/model"
 	"github.com/emporous/emporous-go/registryclient"
+	"github.com/emporous/emporous-go/registryclient/progress"
 )
 
 // ClientOption is a function that configures
@@ -32,6 +35,19 @@ type ClientConfig struct {
 	cache      content.Store
 	copyOpts   oras.CopyOptions
 	attributes model.Matcher
+	variants   []model.Matcher
+	timestamp  *time.Time
+	progress   *progress.Tracker
+	// rateLimit is the global limit in bytes per second
+	// for traffic to registries. If zero, traffic is not limited.
+	rateLimit int64
+	// registryRateLimits override the global rate limit by registry.
+	registryRateLimits map[string]int64
+	// registryConcurrency overrides the copy
+	// concurrency for registries.
+	registryConcurrency map[string]int64
+	ingestDir           string
+	resumableUploads    bool
 }
 
 func (c *ClientConfig) apply(options []ClientOption) error {
@@ -63,13 +79,17 @@ func NewClient(options ...ClientOption) (registryclient.Client, error) {
 	}
 
 	// Setup auth client based on config inputs
+	var transport http.RoundTripper = &http.Transport{
+		TLSClientConfig: &tls.Config{
+			InsecureSkipVerify: config.insecure,
+		},
+	}
+	if config.rateLimit > 0 || len(config.registryRateLimits) != 0 {
+		transport = newRateLimitedTransport(transport, config.rateLimit, config.registryRateLimits)
+	}
 	authClient := &auth.Client{
 		Client: &http.Client{
-			Transport: &http.Transport{
-				TLSClientConfig: &tls.Config{
-					InsecureSkipVerify: config.insecure,
-				},
-			},
+			Transport: transport,
 		},
 		Cache: auth.NewCache(),
 	}
@@ -90,7 +110,13 @@ func NewClient(options ...ClientOption) (registryclient.Client, error) {
 	client.destroy = destroy
 	client.cache = config.cache
 	client.attributes = config.attributes
+	client.variants = config.variants
+	client.timestamp = config.timestamp
 	client.prePullFn = config.prePullFn
+	client.progress = config.progress
+	client.registryConcurrency = config.registryConcurrency
+	client.ingestDir = config.ingestDir
+	client.resumableUploads = config.resumableUploads
 
 	// We are not allowing this to be configurable since
 	// oras file stores turn artifacts into descriptors in
@@ -171,6 +197,93 @@ func WithPreCopy(preFn func(ctx context.Context, desc ocispec.Descriptor) error)
 	}
 }
 
+// WithProgress reports the progress of copies performed by the client
+// to the progress function. Bytes and blob counts are tracked per
+// descriptor and accumulated across all copies performed by the client.
+func WithProgress(fn progress.Func) ClientOption {
+	return func(config *ClientConfig) error {
+		config.progress = progress.NewTracker(fn)
+		return nil
+	}
+}
+
+// WithConcurrency sets the number of descriptors copied
+// concurrently by each copy operation.
+func WithConcurrency(n int64) ClientOption {
+	return func(config *ClientConfig) error {
+		if n < 1 {
+			return fmt.Errorf("concurrency must be at least 1, got %d", n)
+		}
+		config.copyOpts.Concurrency = n
+		return nil
+	}
+}
+
+// WithRegistryConcurrency overrides the number of descriptors copied concurrently
+// by copy operations to or from the registry (e.g. localhost:5000). When a copy involves
+// more than one registry with an override, the lowest concurrency is used.
+func WithRegistryConcurrency(registry string, n int64) ClientOption {
+	return func(config *ClientConfig) error {
+		if n < 1 {
+			return fmt.Errorf("registry %s: concurrency must be at least 1, got %d", registry, n)
+		}
+		if config.registryConcurrency == nil {
+			config.registryConcurrency = map[string]int64{}
+		}
+		config.registryConcurrency[registry] = n
+		return nil
+	}
+}
+
+// WithRateLimit limits the bytes per second read from and written to
+// registries. The limit is shared by all operations performed by the client.
+func WithRateLimit(bytesPerSecond int64) ClientOption {
+	return func(config *ClientConfig) error {
+		if bytesPerSecond < 1 {
+			return fmt.Errorf("rate limit must be at least 1 byte per second, got %d", bytesPerSecond)
+		}
+		config.rateLimit = bytesPerSecond
+		return nil
+	}
+}
+
+// WithRegistryRateLimit overrides the rate limit in bytes per second for traffic to the
+// registry (e.g. localhost:5000). Traffic to the registry does not count toward the
+// limit set with WithRateLimit.
+func WithRegistryRateLimit(registry string, bytesPerSecond int64) ClientOption {
+	return func(config *ClientConfig) error {
+		if bytesPerSecond < 1 {
+			return fmt.Errorf("registry %s: rate limit must be at least 1 byte per second, got %d", registry, bytesPerSecond)
+		}
+		if config.registryRateLimits == nil {
+			config.registryRateLimits = map[string]int64{}
+		}
+		config.registryRateLimits[registry] = bytesPerSecond
+		return nil
+	}
+}
+
+// WithIngestDir persists partially transferred blobs in the directory so interrupted
+// transfers resume where they stopped. Blobs pulled through the cache set with WithCache
+// resume with range requests and are verified before they are added to the cache.
+// When used with WithResumableUploads, upload sessions are also persisted in dir.
+func WithIngestDir(dir string) ClientOption {
+	return func(config *ClientConfig) error {
+		config.ingestDir = dir
+		return nil
+	}
+}
+
+// WithResumableUploads pushes blobs of at least 8 MiB in chunks when an ingest directory
+// is set, so a repeated push resumes from the last chunk received by the registry.
+// Blobs are pushed in a single request if the registry rejects chunked uploads.
+func WithResumableUploads(resumable bool) ClientOption {
+	return func(config *ClientConfig) error {
+		config.resumableUploads = resumable
+		return nil
+	}
+}
+
 // WithPullableAttributes adds a filter when pulling blobs that allows non-matching
 // blobs to be skipped.
 func WithPullableAttributes(filter model.Matcher) ClientOption {
@@ -179,3 +292,22 @@ func WithPullableAttributes(filter model.Matcher) ClientOption {
 		return nil
 	}
 }
+
+// WithVariantMatchers sets the criteria used to select a variant when pulling a
+// collection index. A variant is selected if it satisfies all matchers. If no matchers
+// are set, the variant for the current platform is selected.
+func WithVariantMatchers(matchers ...model.Matcher) ClientOption {
+	return func(config *ClientConfig) error {
+		config.variants = matchers
+		return nil
+	}
+}
+
+// WithTimestamp records the creation timestamp annotation on manifests
+// created by the client. If created is the zero time, the current time is recorded.
+func WithTimestamp(created time.Time) ClientOption {
+	return func(config *ClientConfig) error {
+		config.timestamp = &created
+		return nil
+	}
+}
@@ -9,7 +9,9 @@ import (
 	"io"
 	"path/filepath"
 	"sort"
+	"strings"
 	"sync"
+	"time"
 
 	empspec "github.com/emporous/collection-spec/specs-go/v1alpha1"
 	"github.com/gabriel-vasile/mimetype"
@@ -18,6 +20,7 @@ import (
 	"oras.land/oras-go/v2"
 	orascontent "oras.land/oras-go/v2/content"
 	"oras.land/oras-go/v2/content/file"
+	"oras.land/oras-go/v2/errdef"
 	"oras.land/oras-go/v2/registry/remote"
 	"oras.land/oras-go/v2/registry/remote/auth"
 
@@ -29,6 +32,9 @@ import (
 	"github.com/emporous/emporous-go/nodes/descriptor/v2"
 	"github.com/emporous/emporous-go/registryclient"
 	"github.com/emporous/emporous-go/registryclient/orasclient/internal/cache"
+	"github.com/emporous/emporous-go/registryclient/orasclient/internal/upload"
+	"github.com/emporous/emporous-go/registryclient/progress"
+	"github.com/emporous/emporous-go/util/workspace"
 )
 
 type orasClient struct {
@@ -42,6 +48,9 @@ type orasClient struct {
 	// underlying store for collection
 	// building on disk
 	artifactStore *file.Store
+	// workspace locations of content added
+	// to the artifact store.
+	workspaceFiles sync.Map // map[digest.Digest]workspaceFile
 	// Location of cached blobs
 	cache content.Store
 	// collection will store a cache of
@@ -51,8 +60,30 @@ type orasClient struct {
 	// attributes is set to filter
 	// collections by attribute.
 	attributes model.Matcher
+	// variants are used to select a
+	// manifest from a collection index.
+	variants []model.Matcher
+	// timestamp is the creation time recorded on
+	// manifests. If nil, no creation time is recorded.
+	timestamp *time.Time
+	// progress tracks the progress of copies.
+	// If nil, progress is not tracked.
+	progress *progress.Tracker
+	// registryConcurrency overrides the copy
+	// concurrency for registries.
+	registryConcurrency map[string]int64
+	// ingestDir persists partially transferred
+	// blobs so transfers can be resumed.
+	ingestDir string
+	// resumableUploads pushes large blobs in chunks
+	// persisted in the ingest directory.
+	resumableUploads bool
 }
 
+// uploadChunkSize is the size of the chunks used to upload blobs
+// when pushing with resumable uploads.
+const uploadChunkSize = 8 << 20
+
 var _ registryclient.Client = &orasClient{}
 
 // AddFiles loads one or more files to create OCI descriptors with a specific
@@ -68,6 +99,19 @@ func (c *orasClient) AddFiles(ctx context.Context, mediaType string, files ...st
 	return descs, nil
 }
 
+// AddWorkspaceFiles loads one or more files from a workspace to create OCI descriptors with
+// a specific media type. File content is read through the workspace when the artifact is saved.
+func (c *orasClient) AddWorkspaceFiles(ctx context.Context, mediaType string, space workspace.Workspace, files ...string) ([]ocispec.Descriptor, error) {
+	if err := c.checkFileStore(); err != nil {
+		return nil, err
+	}
+	descs, err := loadWorkspaceFiles(ctx, &c.workspaceFiles, space, mediaType, files...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load files: %w", err)
+	}
+	return descs, nil
+}
+
 // AddContent creates and stores a descriptor from content in bytes, a media type, and
 // annotations.
 func (c *orasClient) AddContent(ctx context.Context, mediaType string, content []byte, annotations map[string]string) (ocispec.Descriptor, error) {
@@ -81,7 +125,11 @@ func (c *orasClient) AddContent(ctx context.Context, mediaType string, content [
 		Annotations: annotations,
 	}
 
-	return configDesc, c.artifactStore.Push(ctx, configDesc, bytes.NewReader(content))
+	err := c.artifactStore.Push(ctx, configDesc, bytes.NewReader(content))
+	if err != nil && !errors.Is(err, errdef.ErrAlreadyExists) {
+		return ocispec.Descriptor{}, err
+	}
+	return configDesc, nil
 }
 
 // AddManifest creates and stores a manifest.
@@ -95,13 +143,11 @@ func (c *orasClient) AddManifest(ctx context.Context, ref string, configDesc oci
 	}
 
 	// Keep descriptor order deterministic
-	sort.Slice(descriptors, func(i, j int) bool {
-		return descriptors[i].Digest < descriptors[j].Digest
-	})
+	sortDescriptors(descriptors)
 
 	var packOpts PackOptions
 	packOpts.ManifestAnnotations = manifestAnnotations
-	packOpts.DisableTimestamp = true
+	c.setTimestamp(&packOpts)
 	packOpts.PackImageManifest = true
 	packOpts.ConfigDescriptor = &configDesc
 
@@ -123,13 +169,11 @@ func (c *orasClient) AddIndex(ctx context.Context, ref string, manifestAnnotatio
 	}
 
 	// Keep descriptor order deterministic
-	sort.Slice(descriptors, func(i, j int) bool {
-		return descriptors[i].Digest < descriptors[j].Digest
-	})
+	sortDescriptors(descriptors)
 
 	var packOpts PackOptions
 	packOpts.ManifestAnnotations = manifestAnnotations
-	packOpts.DisableTimestamp = true
+	c.setTimestamp(&packOpts)
 
 	manifestDesc, err := PackAggregate(ctx, c.artifactStore, descriptors, packOpts)
 	if err != nil {
@@ -139,13 +183,33 @@ func (c *orasClient) AddIndex(ctx context.Context, ref string, manifestAnnotatio
 	return manifestDesc, c.artifactStore.Tag(ctx, manifestDesc, ref)
 }
 
+// setTimestamp sets the creation timestamp
+// pack options from the client configuration.
+func (c *orasClient) setTimestamp(opts *PackOptions) {
+	opts.DisableTimestamp = c.timestamp == nil
+	if c.timestamp != nil {
+		opts.Created = *c.timestamp
+	}
+}
+
+// sortDescriptors sorts descriptors by digest. Descriptors with the
+// same digest are sorted by title.
+func sortDescriptors(descriptors []ocispec.Descriptor) {
+	sort.SliceStable(descriptors, func(i, j int) bool {
+		if descriptors[i].Digest != descriptors[j].Digest {
+			return descriptors[i].Digest < descriptors[j].Digest
+		}
+		return descriptors[i].Annotations[ocispec.AnnotationTitle] < descriptors[j].Annotations[ocispec.AnnotationTitle]
+	})
+}
+
 // Save saves the OCI artifact to local store location (e.g. cache)
 func (c *orasClient) Save(ctx context.Context, ref string, store content.Store) (ocispec.Descriptor, error) {
 	// Create a copy of the options so the original copy
 	// options are not modified.
-	cCopyOpts := c.copyOpts
+	cCopyOpts := c.copyOptions()
 	cCopyOpts.FindSuccessors = successorFnWithSparseManifests
-	return oras.Copy(ctx, c.artifactStore, ref, store, ref, cCopyOpts)
+	return oras.Copy(ctx, c.copySource(c.target()), ref, store, ref, cCopyOpts)
 }
 
 // LoadCollection loads a Emporous collection type from a remote registry path.
@@ -181,9 +245,17 @@ func (c *orasClient) LoadCollection(ctx context.Context, reference string) (coll
 }
 
 // PullWithLinks performs a copy of OCI artifacts to a local location from a remote location and follow links to
-// other artifacts.
-func (c *orasClient) PullWithLinks(ctx context.Context, ref string, store content.Store) ([]ocispec.Descriptor, error) {
-	seen := map[string]struct{}{}
+// other artifacts. The budget limits the link depth, the nodes visited, and the size of the pulled collections.
+// When the requested or a linked collection is an index, only the selected variant is pulled and only its
+// links are followed.
+func (c *orasClient) PullWithLinks(ctx context.Context, ref string, store content.Store, budget *traversal.Budget) ([]ocispec.Descriptor, error) {
+	// seen records the link depth that each node was visited at. A node is visited
+	// again when it is reached with fewer links so that depth limits apply to the
+	// shortest path to each collection.
+	seen := map[string]int{}
+	// depths records the fewest links found between the root and each link node.
+	depths := map[string]int{}
+	pulled := map[string]struct{}{}
 	var allDescs []ocispec.Descriptor
 
 	graph, err := c.LoadCollection(ctx, ref)
@@ -194,6 +266,10 @@ func (c *orasClient) PullWithLinks(ctx context.Context, ref string, store conten
 	if err != nil {
 		return nil, err
 	}
+	selection, err := c.selectVariant(graph)
+	if err != nil {
+		return nil, err
+	}
 
 	processFunc := func(currRef string) error {
 		rootDesc, descs, err := c.Pull(ctx, currRef, store)
@@ -209,12 +285,36 @@ func (c *orasClient) PullWithLinks(ctx context.Context, ref string, store conten
 		return nil
 	}
 
+	// recordDepth records the depth of linked nodes and
+	// removes those that are deeper than the budget allows.
+	recordDepth := func(nodes []model.Node, depth int) []model.Node {
+		var result []model.Node
+		for _, n := range nodes {
+			if isLink(n) {
+				if budget.ExceedsDepth(depth) {
+					continue
+				}
+				if existing, ok := depths[n.ID()]; !ok || depth < existing {
+					depths[n.ID()] = depth
+				}
+			}
+			result = append(result, n)
+		}
+		return result
+	}
+
+	if err := budget.SpendBytes(root, collectionSize(selection.nodes(graph, root))); err != nil {
+		return nil, fmt.Errorf("collection %s: %w", ref, err)
+	}
+
 	// Process and pull links before pulling the requested manifests
-	tracker := traversal.NewTracker(root, nil)
+	tracker := traversal.NewTracker(root, budget)
 	handler := traversal.HandlerFunc(func(ctx context.Context, tracker traversal.Tracker, node model.Node) ([]model.Node, error) {
-		if _, ok := seen[node.ID()]; ok {
+		depth := depths[node.ID()]
+		if visited, ok := seen[node.ID()]; ok && visited <= depth {
 			return nil, traversal.ErrSkip
 		}
+		seen[node.ID()] = depth
 
 		desc, ok := node.(*v2.Node)
 		if !ok {
@@ -222,20 +322,52 @@ func (c *orasClient) PullWithLinks(ctx context.Context, ref string, store conten
 		}
 
 		// Load link and provide access to those nodes.
-		if desc.Properties != nil && desc.Properties.IsALink() {
+		if isLink(node) {
 			constructedRef := fmt.Sprintf("%s/%s@%s", desc.Properties.Link.RegistryHint, desc.Properties.Link.NamespaceHint, desc.ID())
 			linkedCollection, err := c.LoadCollection(ctx, constructedRef)
 			if err != nil {
 				return nil, err
 			}
-			if err := processFunc(constructedRef); err != nil {
+			linkedRoot, err := linkedCollection.Root()
+			if err != nil {
+				return nil, err
+			}
+			// The linked collection is pulled with the same variant
+			// criteria, so links of other variants are not followed.
+			linkedSelection, err := c.selectVariant(linkedCollection)
+			if err != nil {
 				return nil, err
 			}
-			return linkedCollection.Nodes(), nil
+			selected := linkedSelection.nodes(linkedCollection, linkedRoot)
+
+			var linkedNodes []model.Node
+			links := linkPath(tracker, node)
+			for _, linked := range selected {
+				if !isLink(linked) {
+					continue
+				}
+				for _, ancestor := range links {
+					if ancestor.ID() == linked.ID() {
+						return nil, fmt.Errorf("link cycle detected: %s", formatLinkPath(append(links, linked)))
+					}
+				}
+				linkedNodes = append(linkedNodes, linked)
+			}
+
+			if _, ok := pulled[constructedRef]; !ok {
+				if err := budget.SpendBytes(node, collectionSize(selected)); err != nil {
+					return nil, fmt.Errorf("collection %s: %w", constructedRef, err)
+				}
+				if err := processFunc(constructedRef); err != nil {
+					return nil, err
+				}
+				pulled[constructedRef] = struct{}{}
+			}
+			return recordDepth(linkedNodes, depth+1), nil
 		}
 
-		successors := graph.From(node.ID())
-		return successors, err
+		successors := selection.filter(node.ID(), graph.From(node.ID()))
+		return recordDepth(successors, 1), nil
 	})
 
 	if err := tracker.Walk(ctx, handler, root); err != nil {
@@ -249,6 +381,48 @@ func (c *orasClient) PullWithLinks(ctx context.Context, ref string, store conten
 	return allDescs, nil
 }
 
+// isLink returns whether the node is a link to another collection.
+func isLink(node model.Node) bool {
+	desc, ok := node.(*v2.Node)
+	return ok && desc.Properties != nil && desc.Properties.IsALink()
+}
+
+// linkPath returns the root node and the link nodes on the
+// tracked path from the root node to the specified node.
+func linkPath(tracker traversal.Tracker, end model.Node) []model.Node {
+	path := tracker.List(end)
+	links := []model.Node{path[0]}
+	for _, node := range path[1:] {
+		if isLink(node) {
+			links = append(links, node)
+		}
+	}
+	return links
+}
+
+// formatLinkPath formats the IDs of the nodes in a link path.
+func formatLinkPath(links []model.Node) string {
+	ids := make([]string, len(links))
+	for i, node := range links {
+		ids[i] = node.ID()
+	}
+	return strings.Join(ids, " -> ")
+}
+
+// collectionSize returns the size of the content of the collection nodes,
+// excluding the content of linked collections.
+func collectionSize(nodes []model.Node) int64 {
+	var size int64
+	for _, node := range nodes {
+		desc, ok := node.(*v2.Node)
+		if !ok || isLink(node) {
+			continue
+		}
+		size += desc.Descriptor().Size
+	}
+	return size
+}
+
 // Pull performs a copy of OCI artifacts to a local location from a remote location.
 func (c *orasClient) Pull(ctx context.Context, ref string, store content.Store) (ocispec.Descriptor, []ocispec.Descriptor, error) {
 	var allDescs []ocispec.Descriptor
@@ -267,7 +441,7 @@ func (c *orasClient) Pull(ctx context.Context, ref string, store content.Store)
 	from = repo
 
 	if c.cache != nil {
-		from = cache.New(repo, c.cache)
+		from = cache.New(repo, c.cache, c.ingestDir)
 	}
 
 	graph, err := c.LoadCollection(ctx, ref)
@@ -275,6 +449,12 @@ func (c *orasClient) Pull(ctx context.Context, ref string, store content.Store)
 		return ocispec.Descriptor{}, allDescs, err
 	}
 
+	// Select a single variant if the collection is an index.
+	selection, err := c.selectVariant(graph)
+	if err != nil {
+		return ocispec.Descriptor{}, allDescs, err
+	}
+
 	// Filter the collection per the matcher criteria
 	if c.attributes != nil {
 		var matchedLeaf int
@@ -327,7 +507,7 @@ func (c *orasClient) Pull(ctx context.Context, ref string, store content.Store)
 	var mu sync.Mutex
 	successorFn := func(_ context.Context, fetcher orascontent.Fetcher, desc ocispec.Descriptor) ([]ocispec.Descriptor, error) {
 		mu.Lock()
-		successors := graph.From(desc.Digest.String())
+		successors := selection.filter(desc.Digest.String(), graph.From(desc.Digest.String()))
 		allDescs = append(allDescs, desc)
 		mu.Unlock()
 
@@ -347,10 +527,12 @@ func (c *orasClient) Pull(ctx context.Context, ref string, store content.Store)
 
 	// Create a copy of the options so the original copy
 	// options are not modified.
-	cCopyOpts := c.copyOpts
+	cCopyOpts := c.copyOptions(repo.Reference.Registry)
 	cCopyOpts.FindSuccessors = successorFn
 
-	desc, err := oras.Copy(ctx, from, ref, store, ref, cCopyOpts)
+	// Record the collection the content is pulled from
+	// for stores that place content by collection.
+	desc, err := oras.Copy(content.WithSource(ctx, ref), c.copySource(from), ref, store, ref, cCopyOpts)
 	if err != nil {
 		return ocispec.Descriptor{}, allDescs, err
 	}
@@ -367,10 +549,23 @@ func (c *orasClient) Push(ctx context.Context, store content.Store, ref string)
 
 	// Create a copy of the options so the original copy
 	// options are not modified.
-	cCopyOpts := c.copyOpts
+	cCopyOpts := c.copyOptions(repo.Reference.Registry)
 	cCopyOpts.FindSuccessors = successorFnWithSparseManifests
 
-	return oras.Copy(ctx, store, ref, repo, ref, cCopyOpts)
+	var to oras.Target = repo
+	if c.resumableUploads && c.ingestDir != "" {
+		to = upload.New(repo, filepath.Join(c.ingestDir, "uploads"), uploadChunkSize)
+	}
+	return oras.Copy(ctx, c.copySource(store), ref, to, ref, cCopyOpts)
+}
+
+// Tag tags the manifest described by desc with the reference.
+func (c *orasClient) Tag(ctx context.Context, desc ocispec.Descriptor, ref string) error {
+	repo, err := c.setupRepo(ref)
+	if err != nil {
+		return fmt.Errorf("could not create registry target: %w", err)
+	}
+	return repo.Tag(ctx, desc, ref)
 }
 
 // GetManifest returns the manifest the reference resolves to.
@@ -384,21 +579,27 @@ func (c *orasClient) GetManifest(ctx context.Context, reference string) (ocispec
 
 // GetContent retrieves the content for a specified descriptor at a specified reference.
 func (c *orasClient) GetContent(ctx context.Context, reference string, desc ocispec.Descriptor) ([]byte, error) {
-	repo, err := c.setupRepo(reference)
-	if err != nil {
-		return nil, fmt.Errorf("could not create registry target: %w", err)
-	}
-	r, err := repo.Fetch(ctx, desc)
+	r, err := c.FetchContent(ctx, reference, desc)
 	if err != nil {
 		return nil, err
 	}
+	defer r.Close()
 	return orascontent.ReadAll(r, desc)
 }
 
+// FetchContent returns a reader for the content of a specified descriptor at a specified reference.
+func (c *orasClient) FetchContent(ctx context.Context, reference string, desc ocispec.Descriptor) (io.ReadCloser, error) {
+	repo, err := c.setupRepo(reference)
+	if err != nil {
+		return nil, fmt.Errorf("could not create registry target: %w", err)
+	}
+	return repo.Fetch(ctx, desc)
+}
+
 // Store returns the source storage being used to store
 // the OCI artifact.
 func (c *orasClient) Store() (content.Store, error) {
-	return c.artifactStore, nil
+	return c.target(), nil
 }
 
 // Destroy cleans up any temporary on-disk resources used to track descriptors.
@@ -415,6 +616,15 @@ func (c *orasClient) checkFileStore() error {
 	return nil
 }
 
+// target returns the artifact store combined with
+// any content added from workspaces.
+func (c *orasClient) target() *artifactTarget {
+	return &artifactTarget{
+		Store: c.artifactStore,
+		files: &c.workspaceFiles,
+	}
+}
+
 // setupRepo configures the client to access the remote repository.
 func (c *orasClient) setupRepo(ref string) (*remote.Repository, error) {
 	repo, err := remote.NewRepository(ref)
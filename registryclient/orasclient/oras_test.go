@@ -2,12 +2,14 @@ package orasclient
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http/httptest"
 	"net/url"
 	"os"
 	"path/filepath"
+	"sort"
 	"testing"
 
 	empspec "github.com/emporous/collection-spec/specs-go/v1alpha1"
@@ -15,11 +17,18 @@ import (
 	"github.com/opencontainers/go-digest"
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/stretchr/testify/require"
+	orascontent "oras.land/oras-go/v2/content"
 	"oras.land/oras-go/v2/content/file"
 	"oras.land/oras-go/v2/content/memory"
 
 	"github.com/emporous/emporous-go/attributes"
 	"github.com/emporous/emporous-go/attributes/matchers"
+	"github.com/emporous/emporous-go/model"
+	"github.com/emporous/emporous-go/nodes/collection"
+	"github.com/emporous/emporous-go/nodes/descriptor"
+	"github.com/emporous/emporous-go/nodes/descriptor/v2"
+	"github.com/emporous/emporous-go/registryclient/progress"
+	"github.com/emporous/emporous-go/util/workspace"
 )
 
 func TestAddFiles(t *testing.T) {
@@ -36,6 +45,57 @@ func TestAddFiles(t *testing.T) {
 	})
 }
 
+func TestAddWorkspaceFiles(t *testing.T) {
+	t.Run("Success/LocalWorkspace", func(t *testing.T) {
+		ctx := context.TODO()
+		expDigest := "sha256:2e30f6131ce2164ed5ef017845130727291417d60a1be6fad669bdc4473289cd"
+		space, err := workspace.NewLocalWorkspace(filepath.Join("testdata", "workspace"))
+		require.NoError(t, err)
+		c, err := NewClient(WithPlainHTTP(true))
+		require.NoError(t, err)
+		desc, err := c.AddWorkspaceFiles(ctx, "", space, "fish.jpg")
+		require.NoError(t, err)
+		require.Len(t, desc, 1)
+		require.Equal(t, expDigest, desc[0].Digest.String())
+		require.Equal(t, "image/jpeg", desc[0].MediaType)
+		require.Equal(t, "fish.jpg", desc[0].Annotations[ocispec.AnnotationTitle])
+	})
+	t.Run("Success/SaveFromMemoryWorkspace", func(t *testing.T) {
+		ctx := context.TODO()
+		space, err := workspace.NewMemoryWorkspace()
+		require.NoError(t, err)
+		require.NoError(t, space.WriteObject(ctx, "hello.txt", "Hello World!\n"))
+		c, err := NewClient(WithPlainHTTP(true))
+		require.NoError(t, err)
+		descs, err := c.AddWorkspaceFiles(ctx, "", space, "hello.txt")
+		require.NoError(t, err)
+		require.Len(t, descs, 1)
+		require.Equal(t, "text/plain; charset=utf-8", descs[0].MediaType)
+		configDesc, err := c.AddContent(ctx, empspec.MediaTypeConfiguration, []byte("{}"), nil)
+		require.NoError(t, err)
+		ref := "localhost:5000/test:latest"
+		_, err = c.AddManifest(ctx, ref, configDesc, nil, descs...)
+		require.NoError(t, err)
+
+		memStore := memory.New()
+		_, err = c.Save(ctx, ref, memStore)
+		require.NoError(t, err)
+		require.NoError(t, c.Destroy())
+		data, err := orascontent.FetchAll(ctx, memStore, descs[0])
+		require.NoError(t, err)
+		require.Equal(t, "Hello World!\n", string(data))
+	})
+	t.Run("Failure/FileDoesNotExist", func(t *testing.T) {
+		ctx := context.TODO()
+		space, err := workspace.NewMemoryWorkspace()
+		require.NoError(t, err)
+		c, err := NewClient(WithPlainHTTP(true))
+		require.NoError(t, err)
+		_, err = c.AddWorkspaceFiles(ctx, "", space, "fish.jpg")
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
+
 func TestAddContent(t *testing.T) {
 	t.Run("Success/OneArtifact", func(t *testing.T) {
 		ctx := context.TODO()
@@ -215,6 +275,40 @@ func TestPushPull(t *testing.T) {
 		require.NoError(t, c.Destroy())
 	})
 
+	t.Run("Success/PullWithProgress", func(t *testing.T) {
+		var events []progress.Event
+		c, err := NewClient(WithPlainHTTP(true), WithProgress(func(event progress.Event) {
+			events = append(events, event)
+		}))
+		require.NoError(t, err)
+		_, descs, err := c.Pull(context.TODO(), ref, memory.New())
+		require.NoError(t, err)
+		require.NoError(t, c.Destroy())
+
+		var size int64
+		for _, desc := range descs {
+			size += desc.Size
+		}
+		require.NotEmpty(t, events)
+		last := events[len(events)-1]
+		require.Equal(t, progress.EventDone, last.Type)
+		require.Equal(t, progress.Status{
+			BlobsDone:  len(descs),
+			BlobsTotal: len(descs),
+			BytesDone:  size,
+			BytesTotal: size,
+		}, last.Status)
+
+		// Bytes are reported while the blobs are copied.
+		var reported bool
+		for _, event := range events {
+			if event.Type == progress.EventProgress && event.Descriptor.MediaType == "image/jpeg" {
+				reported = true
+			}
+		}
+		require.True(t, reported)
+	})
+
 	t.Run("Success/PushMultipleCollections", func(t *testing.T) {
 		c, err := NewClient(WithPlainHTTP(true))
 		require.NoError(t, err)
@@ -269,3 +363,183 @@ func TestPushPull(t *testing.T) {
 		require.NoError(t, c.Destroy())
 	})
 }
+
+func TestPullVariants(t *testing.T) {
+	server := httptest.NewServer(registry.New())
+	t.Cleanup(server.Close)
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	ref := fmt.Sprintf("%s/variants:latest", u.Host)
+	ctx := context.TODO()
+
+	c, err := NewClient(WithPlainHTTP(true))
+	require.NoError(t, err)
+	configDesc, err := c.AddContent(ctx, empspec.MediaTypeConfiguration, []byte("{}"), nil)
+	require.NoError(t, err)
+
+	var manifests []ocispec.Descriptor
+	for _, arch := range []string{"amd64", "arm64"} {
+		space, err := workspace.NewMemoryWorkspace()
+		require.NoError(t, err)
+		require.NoError(t, space.WriteObject(ctx, "binary", []byte(arch)))
+		fileDescs, err := c.AddWorkspaceFiles(ctx, "", space, "binary")
+		require.NoError(t, err)
+		mdesc, err := c.AddManifest(ctx, ref+"-"+arch, configDesc, nil, fileDescs...)
+		require.NoError(t, err)
+		mdesc.Platform = &ocispec.Platform{OS: "linux", Architecture: arch}
+		mdesc.Annotations = map[string]string{
+			descriptor.AnnotationVariant:         arch,
+			empspec.AnnotationEmporousAttributes: fmt.Sprintf(`{"unknown":{"arch":"%s"}}`, arch),
+		}
+		manifests = append(manifests, mdesc)
+	}
+	index, err := c.AddIndex(ctx, ref, nil, manifests...)
+	require.NoError(t, err)
+	source, err := c.Store()
+	require.NoError(t, err)
+	_, err = c.Push(ctx, source, ref)
+	require.NoError(t, err)
+	require.NoError(t, c.Destroy())
+
+	type spec struct {
+		name     string
+		matchers []model.Matcher
+		expArch  string
+		expError string
+	}
+
+	cases := []spec{
+		{
+			name:     "Success/SelectByPlatform",
+			matchers: []model.Matcher{descriptor.PlatformMatcher{OS: "linux", Architecture: "arm64"}},
+			expArch:  "arm64",
+		},
+		{
+			name: "Success/SelectByAttribute",
+			matchers: []model.Matcher{
+				descriptor.JSONSubsetMatcher(`{"unknown":{"arch":"amd64"}}`),
+			},
+			expArch: "amd64",
+		},
+		{
+			name:     "Failure/NoMatchingVariant",
+			matchers: []model.Matcher{descriptor.PlatformMatcher{OS: "windows", Architecture: "amd64"}},
+			expError: fmt.Sprintf("no variant of %s matches the selection criteria, available variants: "+
+				"amd64 (linux/amd64), arm64 (linux/arm64)", ref),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			tmp := t.TempDir()
+			client, err := NewClient(WithPlainHTTP(true), WithVariantMatchers(c.matchers...))
+			require.NoError(t, err)
+			defer func() {
+				require.NoError(t, client.Destroy())
+			}()
+			root, _, err := client.Pull(ctx, ref, file.New(tmp))
+			if c.expError != "" {
+				require.EqualError(t, err, c.expError)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, index.Digest, root.Digest)
+			data, err := os.ReadFile(filepath.Join(tmp, "binary"))
+			require.NoError(t, err)
+			require.Equal(t, c.expArch, string(data))
+		})
+	}
+}
+
+func TestPullWithLinksCycle(t *testing.T) {
+	ref := "localhost:5001/test:latest"
+	rootDigest := digest.FromString("root")
+	linkedDigest := digest.FromString("linked")
+
+	linkJSON, err := json.Marshal(descriptor.Properties{
+		Link: &empspec.LinkAttributes{
+			RegistryHint:  "localhost:5001",
+			NamespaceHint: "test",
+			Transitive:    true,
+		},
+	})
+	require.NoError(t, err)
+	linkAnnotations := map[string]string{empspec.AnnotationEmporousAttributes: string(linkJSON)}
+
+	// The root collection links to a collection that links back to the root.
+	newCollection := func(location string, manifest, link digest.Digest) collection.Collection {
+		co := collection.New(location)
+		manifestNode, err := v2.NewNode(manifest.String(), ocispec.Descriptor{MediaType: ocispec.MediaTypeImageManifest, Digest: manifest})
+		require.NoError(t, err)
+		linkNode, err := v2.NewNode(link.String(), ocispec.Descriptor{MediaType: ocispec.MediaTypeImageManifest, Digest: link, Annotations: linkAnnotations})
+		require.NoError(t, err)
+		require.NoError(t, co.AddNode(manifestNode))
+		require.NoError(t, co.AddNode(linkNode))
+		require.NoError(t, co.AddEdge(collection.NewEdge(manifestNode, linkNode)))
+		return *co
+	}
+
+	c, err := NewClient()
+	require.NoError(t, err)
+	client, ok := c.(*orasClient)
+	require.True(t, ok)
+	client.collections.Store(ref, newCollection(ref, rootDigest, linkedDigest))
+	linkedRef := fmt.Sprintf("localhost:5001/test@%s", linkedDigest)
+	client.collections.Store(linkedRef, newCollection(linkedRef, linkedDigest, rootDigest))
+
+	_, err = c.PullWithLinks(context.TODO(), ref, memory.New(), nil)
+	require.EqualError(t, err, fmt.Sprintf("link cycle detected: %s -> %s -> %s", rootDigest, linkedDigest, rootDigest))
+}
+
+func TestVariantSelectionNodes(t *testing.T) {
+	newNode := func(id, mediaType string) *v2.Node {
+		node, err := v2.NewNode(id, ocispec.Descriptor{MediaType: mediaType, Digest: digest.FromString(id)})
+		require.NoError(t, err)
+		return node
+	}
+
+	// Each variant of the index links to a different collection.
+	co := collection.New("localhost:5001/test:latest")
+	index := newNode("index", ocispec.MediaTypeImageIndex)
+	amd64 := newNode("amd64", ocispec.MediaTypeImageManifest)
+	arm64 := newNode("arm64", ocispec.MediaTypeImageManifest)
+	amd64Link := newNode("amd64-link", ocispec.MediaTypeImageManifest)
+	arm64Link := newNode("arm64-link", ocispec.MediaTypeImageManifest)
+	for _, node := range []*v2.Node{index, amd64, arm64, amd64Link, arm64Link} {
+		require.NoError(t, co.AddNode(node))
+	}
+	require.NoError(t, co.AddEdge(collection.NewEdge(index, amd64)))
+	require.NoError(t, co.AddEdge(collection.NewEdge(index, arm64)))
+	require.NoError(t, co.AddEdge(collection.NewEdge(amd64, amd64Link)))
+	require.NoError(t, co.AddEdge(collection.NewEdge(arm64, arm64Link)))
+
+	type spec struct {
+		name      string
+		selection variantSelection
+		expIDs    []string
+	}
+
+	cases := []spec{
+		{
+			name:      "Success/SelectedVariant",
+			selection: variantSelection{indexID: "index", variantID: "arm64"},
+			expIDs:    []string{"arm64", "arm64-link", "index"},
+		},
+		{
+			name:   "Success/NoSelection",
+			expIDs: []string{"amd64", "amd64-link", "arm64", "arm64-link", "index"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var ids []string
+			for _, node := range c.selection.nodes(*co, index) {
+				ids = append(ids, node.ID())
+			}
+			sort.Strings(ids)
+			require.Equal(t, c.expIDs, ids)
+		})
+	}
+}
@@ -38,6 +38,7 @@ var (
 //
 // Changes:
 // - Added `DisableTimestamp` option to disable the creation timestamp annotation.
+// - Added `Created` option to set the creation timestamp annotation value.
 
 // TODO(jpower432): PR this back to `oras-go` if it makes sense.
 
@@ -65,6 +66,9 @@ type PackOptions struct {
 	// DisableTimeStamp controls whether the artifact creation timestamp
 	// annotation is created.
 	DisableTimestamp bool
+	// Created is the creation timestamp used when the creation timestamp
+	// annotation is created. If zero, the current time is used.
+	Created time.Time
 }
 
 func Pack(ctx context.Context, pusher content.Pusher, artifactType string, blobs []ocispec.Descriptor, opts PackOptions) (ocispec.Descriptor, error) {
@@ -86,7 +90,7 @@ func packArtifact(ctx context.Context, pusher content.Pusher, artifactType strin
 	annotations := opts.ManifestAnnotations
 
 	if !opts.DisableTimestamp {
-		annotations, err = ensureAnnotationCreated(opts.ManifestAnnotations, ocispec.AnnotationArtifactCreated)
+		annotations, err = ensureAnnotationCreated(opts.ManifestAnnotations, ocispec.AnnotationArtifactCreated, opts.Created)
 		if err != nil {
 			return ocispec.Descriptor{}, err
 		}
@@ -132,7 +136,7 @@ func packImage(ctx context.Context, pusher content.Pusher, configMediaType strin
 
 	annotations := opts.ManifestAnnotations
 	if !opts.DisableTimestamp {
-		annotations, err = ensureAnnotationCreated(opts.ManifestAnnotations, ocispec.AnnotationArtifactCreated)
+		annotations, err = ensureAnnotationCreated(opts.ManifestAnnotations, ocispec.AnnotationArtifactCreated, opts.Created)
 		if err != nil {
 			return ocispec.Descriptor{}, err
 		}
@@ -172,7 +176,7 @@ func PackAggregate(ctx context.Context, pusher content.Pusher, manifests []ocisp
 	annotations := opts.ManifestAnnotations
 	var err error
 	if !opts.DisableTimestamp {
-		annotations, err = ensureAnnotationCreated(opts.ManifestAnnotations, ocispec.AnnotationArtifactCreated)
+		annotations, err = ensureAnnotationCreated(opts.ManifestAnnotations, ocispec.AnnotationArtifactCreated, opts.Created)
 		if err != nil {
 			return ocispec.Descriptor{}, err
 		}
@@ -269,8 +273,8 @@ func handleConfig(ctx context.Context, pusher content.Pusher, configMediaType st
 
 // ensureAnnotationCreated ensures that annotationCreatedKey is in annotations,
 // and that its value conforms to RFC 3339. Otherwise returns a new annotation
-// map with annotationCreatedKey created.
-func ensureAnnotationCreated(annotations map[string]string, annotationCreatedKey string) (map[string]string, error) {
+// map with annotationCreatedKey created from the created time or the current time if zero.
+func ensureAnnotationCreated(annotations map[string]string, annotationCreatedKey string, created time.Time) (map[string]string, error) {
 	if createdTime, ok := annotations[annotationCreatedKey]; ok {
 		// if annotationCreatedKey is provided, validate its format
 		if _, err := time.Parse(time.RFC3339, createdTime); err != nil {
@@ -286,7 +290,9 @@ func ensureAnnotationCreated(annotations map[string]string, annotationCreatedKey
 	}
 	// set creation time in RFC 3339 format
 	// reference: https://github.com/opencontainers/image-spec/blob/v1.1.0-rc2/annotations.md#pre-defined-annotation-keys
-	now := time.Now().UTC()
-	copied[annotationCreatedKey] = now.Format(time.RFC3339)
+	if created.IsZero() {
+		created = time.Now()
+	}
+	copied[annotationCreatedKey] = created.UTC().Format(time.RFC3339)
 	return copied, nil
 }
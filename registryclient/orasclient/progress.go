@@ -0,0 +1,91 @@
+package orasclient
+
+import (
+	"context"
+	"io"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2"
+
+	"github.com/emporous/emporous-go/registryclient/progress"
+)
+
+// copyOptions returns a copy of the client copy options for a copy involving the
+// registries. If progress is tracked, the copy hooks report to the progress tracker
+// before calling any hooks set on the client.
+func (c *orasClient) copyOptions(registries ...string) oras.CopyOptions {
+	opts := c.copyOpts
+	var overridden bool
+	for _, registry := range registries {
+		n, ok := c.registryConcurrency[registry]
+		if ok && (!overridden || n < opts.Concurrency) {
+			opts.Concurrency = n
+			overridden = true
+		}
+	}
+	if c.progress == nil {
+		return opts
+	}
+
+	preCopy := opts.PreCopy
+	opts.PreCopy = func(ctx context.Context, desc ocispec.Descriptor) error {
+		if preCopy != nil {
+			if err := preCopy(ctx, desc); err != nil {
+				return err
+			}
+		}
+		c.progress.Start(desc)
+		return nil
+	}
+	postCopy := opts.PostCopy
+	opts.PostCopy = func(ctx context.Context, desc ocispec.Descriptor) error {
+		c.progress.Done(desc)
+		if postCopy != nil {
+			return postCopy(ctx, desc)
+		}
+		return nil
+	}
+	onCopySkipped := opts.OnCopySkipped
+	opts.OnCopySkipped = func(ctx context.Context, desc ocispec.Descriptor) error {
+		c.progress.Skip(desc)
+		if onCopySkipped != nil {
+			return onCopySkipped(ctx, desc)
+		}
+		return nil
+	}
+	return opts
+}
+
+// copySource returns the source of a copy. If progress is tracked,
+// bytes read from the source are reported to the progress tracker.
+func (c *orasClient) copySource(src oras.ReadOnlyTarget) oras.ReadOnlyTarget {
+	if c.progress == nil {
+		return src
+	}
+	return &progressTarget{
+		ReadOnlyTarget: src,
+		tracker:        c.progress,
+	}
+}
+
+// progressTarget reports the bytes read from the
+// underlying target to a progress tracker.
+type progressTarget struct {
+	oras.ReadOnlyTarget
+	tracker *progress.Tracker
+}
+
+// Fetch fetches the content identified by the descriptor.
+func (t *progressTarget) Fetch(ctx context.Context, desc ocispec.Descriptor) (io.ReadCloser, error) {
+	rc, err := t.ReadOnlyTarget.Fetch(ctx, desc)
+	if err != nil {
+		return nil, err
+	}
+	return struct {
+		io.Reader
+		io.Closer
+	}{
+		Reader: t.tracker.Reader(desc, rc),
+		Closer: rc,
+	}, nil
+}
@@ -0,0 +1,133 @@
+package orasclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2"
+	orascontent "oras.land/oras-go/v2/content"
+
+	"github.com/emporous/emporous-go/content"
+)
+
+// AddArtifact creates and stores an artifact manifest with the subject set to the
+// manifest described by subject. If the reference is set, the artifact is tagged with the reference
+// in the underlying storage.
+func (c *orasClient) AddArtifact(ctx context.Context, ref string, artifactType string, subject ocispec.Descriptor, manifestAnnotations map[string]string, descriptors ...ocispec.Descriptor) (ocispec.Descriptor, error) {
+	if err := c.checkFileStore(); err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	if descriptors == nil {
+		descriptors = []ocispec.Descriptor{}
+	}
+
+	// Keep descriptor order deterministic
+	sortDescriptors(descriptors)
+
+	// Only the fields identifying the subject are kept.
+	subject = ocispec.Descriptor{
+		MediaType: subject.MediaType,
+		Digest:    subject.Digest,
+		Size:      subject.Size,
+	}
+
+	var packOpts PackOptions
+	packOpts.ManifestAnnotations = manifestAnnotations
+	c.setTimestamp(&packOpts)
+	packOpts.Subject = &subject
+
+	manifestDesc, err := Pack(ctx, c.artifactStore, artifactType, descriptors, packOpts)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	if ref == "" {
+		return manifestDesc, nil
+	}
+
+	return manifestDesc, c.artifactStore.Tag(ctx, manifestDesc, ref)
+}
+
+// Referrers returns the descriptors of the manifests that have the manifest at the reference
+// as their subject. The registry referrers API is used when available, otherwise the referrers
+// tag schema is used. If the artifact type is set, only referrers of that type are returned.
+func (c *orasClient) Referrers(ctx context.Context, reference string, artifactType string) ([]ocispec.Descriptor, error) {
+	repo, err := c.setupRepo(reference)
+	if err != nil {
+		return nil, err
+	}
+	desc, err := repo.Resolve(ctx, reference)
+	if err != nil {
+		return nil, fmt.Errorf("reference %s: %w", reference, err)
+	}
+	var referrers []ocispec.Descriptor
+	err = repo.Referrers(ctx, desc, artifactType, func(page []ocispec.Descriptor) error {
+		referrers = append(referrers, page...)
+		return nil
+	})
+	return referrers, err
+}
+
+// PullReferrer pulls the referrer manifest described by desc and its blobs from the repository at the
+// reference to a local content store. The subject of the referrer is not pulled.
+// If successful, it returns the descriptors pulled.
+func (c *orasClient) PullReferrer(ctx context.Context, reference string, desc ocispec.Descriptor, store content.Store) ([]ocispec.Descriptor, error) {
+	if c.prePullFn != nil {
+		if err := c.prePullFn(ctx, reference); err != nil {
+			return nil, err
+		}
+	}
+
+	repo, err := c.setupRepo(reference)
+	if err != nil {
+		return nil, err
+	}
+
+	var allDescs []ocispec.Descriptor
+	var mu sync.Mutex
+	successorFn := func(ctx context.Context, fetcher orascontent.Fetcher, desc ocispec.Descriptor) ([]ocispec.Descriptor, error) {
+		mu.Lock()
+		allDescs = append(allDescs, desc)
+		mu.Unlock()
+		return referrerSuccessors(ctx, fetcher, desc)
+	}
+
+	// Create a copy of the options so the original copy
+	// options are not modified.
+	cCopyGraphOpts := c.copyOptions(repo.Reference.Registry).CopyGraphOptions
+	cCopyGraphOpts.FindSuccessors = successorFn
+	if err := oras.CopyGraph(ctx, c.copySource(repo), store, desc, cCopyGraphOpts); err != nil {
+		return allDescs, err
+	}
+	return allDescs, nil
+}
+
+// referrerSuccessors returns the successors of a referrer manifest
+// without the subject, so the content being referred to is not followed.
+func referrerSuccessors(ctx context.Context, fetcher orascontent.Fetcher, desc ocispec.Descriptor) ([]ocispec.Descriptor, error) {
+	switch desc.MediaType {
+	case ocispec.MediaTypeArtifactManifest:
+		data, err := orascontent.FetchAll(ctx, fetcher, desc)
+		if err != nil {
+			return nil, err
+		}
+		var manifest ocispec.Artifact
+		if err := json.Unmarshal(data, &manifest); err != nil {
+			return nil, err
+		}
+		return manifest.Blobs, nil
+	case ocispec.MediaTypeImageManifest:
+		data, err := orascontent.FetchAll(ctx, fetcher, desc)
+		if err != nil {
+			return nil, err
+		}
+		var manifest ocispec.Manifest
+		if err := json.Unmarshal(data, &manifest); err != nil {
+			return nil, err
+		}
+		return append([]ocispec.Descriptor{manifest.Config}, manifest.Layers...), nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,131 @@
+package orasclient
+
+import (
+	"fmt"
+	"runtime"
+	"sort"
+	"strings"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+	"github.com/emporous/emporous-go/model"
+	"github.com/emporous/emporous-go/nodes/collection"
+	"github.com/emporous/emporous-go/nodes/descriptor"
+	"github.com/emporous/emporous-go/nodes/descriptor/v2"
+)
+
+// variantSelection is the manifest selected
+// from a collection index.
+type variantSelection struct {
+	indexID   string
+	variantID string
+}
+
+// filter removes any unselected variants from the successors
+// of the node with the given ID.
+func (s variantSelection) filter(id string, successors []model.Node) []model.Node {
+	if s.indexID == "" || id != s.indexID {
+		return successors
+	}
+	for _, successor := range successors {
+		if successor.ID() == s.variantID {
+			return []model.Node{successor}
+		}
+	}
+	return nil
+}
+
+// nodes returns the nodes of the collection that are reachable
+// from the root once unselected variants are removed.
+func (s variantSelection) nodes(graph collection.Collection, root model.Node) []model.Node {
+	seen := map[string]struct{}{}
+	var result []model.Node
+	stack := []model.Node{root}
+	for len(stack) != 0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if _, ok := seen[node.ID()]; ok {
+			continue
+		}
+		seen[node.ID()] = struct{}{}
+		result = append(result, node)
+		stack = append(stack, s.filter(node.ID(), graph.From(node.ID()))...)
+	}
+	return result
+}
+
+// selectVariant selects a manifest from the collection root if the root is
+// an index. An empty selection is returned if the root is not an index.
+func (c *orasClient) selectVariant(graph collection.Collection) (variantSelection, error) {
+	root, err := graph.Root()
+	if err != nil {
+		return variantSelection{}, err
+	}
+	rootDesc, ok := root.(*v2.Node)
+	if !ok || rootDesc.Descriptor().MediaType != ocispec.MediaTypeImageIndex {
+		return variantSelection{}, nil
+	}
+
+	matchers := c.variants
+	if len(matchers) == 0 {
+		matchers = []model.Matcher{descriptor.PlatformMatcher{OS: runtime.GOOS, Architecture: runtime.GOARCH}}
+	}
+
+	var matches []model.Node
+	var available []string
+	for _, variant := range graph.From(root.ID()) {
+		available = append(available, variantName(variant))
+		match := true
+		for _, matcher := range matchers {
+			m, err := matcher.Matches(variant)
+			if err != nil {
+				return variantSelection{}, err
+			}
+			if !m {
+				match = false
+				break
+			}
+		}
+		if match {
+			matches = append(matches, variant)
+		}
+	}
+
+	// Graph edges are unordered, so names are sorted
+	// for stable error messages.
+	sort.Strings(available)
+	switch len(matches) {
+	case 0:
+		return variantSelection{}, fmt.Errorf("no variant of %s matches the selection criteria, available variants: %s", graph.Location, strings.Join(available, ", "))
+	case 1:
+		return variantSelection{indexID: root.ID(), variantID: matches[0].ID()}, nil
+	default:
+		var names []string
+		for _, match := range matches {
+			names = append(names, variantName(match))
+		}
+		sort.Strings(names)
+		return variantSelection{}, fmt.Errorf("more than one variant of %s matches the selection criteria: %s", graph.Location, strings.Join(names, ", "))
+	}
+}
+
+// variantName returns a display name for a
+// manifest referenced by an index.
+func variantName(node model.Node) string {
+	desc, ok := node.(*v2.Node)
+	if !ok {
+		return node.ID()
+	}
+	name := desc.Descriptor().Annotations[descriptor.AnnotationVariant]
+	if name == "" {
+		name = desc.ID()
+	}
+	if platform := desc.Descriptor().Platform; platform != nil {
+		p := platform.OS + "/" + platform.Architecture
+		if platform.Variant != "" {
+			p += "/" + platform.Variant
+		}
+		name = fmt.Sprintf("%s (%s)", name, p)
+	}
+	return name
+}
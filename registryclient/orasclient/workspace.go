@@ -0,0 +1,114 @@
+package orasclient
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sync"
+
+	"github.com/gabriel-vasile/mimetype"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/content/file"
+
+	"github.com/emporous/emporous-go/util/workspace"
+)
+
+// workspaceFile is the location of file content
+// within a workspace.
+type workspaceFile struct {
+	space workspace.Workspace
+	path  string
+}
+
+// artifactTarget combines the file store used for
+// building with content referenced from workspaces so built
+// artifacts can be copied as a single target.
+type artifactTarget struct {
+	*file.Store
+	// files is the workspace location of content
+	// by digest.
+	files *sync.Map // map[digest.Digest]workspaceFile
+}
+
+// Fetch fetches the content identified by the descriptor. Workspace content
+// is read directly from the workspace.
+func (t *artifactTarget) Fetch(ctx context.Context, desc ocispec.Descriptor) (io.ReadCloser, error) {
+	value, ok := t.files.Load(desc.Digest)
+	if !ok {
+		return t.Store.Fetch(ctx, desc)
+	}
+	wf := value.(workspaceFile)
+	return wf.space.GetReader(ctx, wf.path)
+}
+
+// Exists returns true if the described content exists.
+func (t *artifactTarget) Exists(ctx context.Context, desc ocispec.Descriptor) (bool, error) {
+	if _, ok := t.files.Load(desc.Digest); ok {
+		return true, nil
+	}
+	return t.Store.Exists(ctx, desc)
+}
+
+// loadWorkspaceFiles creates descriptors representing each file in the workspace and records their
+// location for later retrieval.
+func loadWorkspaceFiles(ctx context.Context, files *sync.Map, space workspace.Workspace, mediaType string, paths ...string) ([]ocispec.Descriptor, error) {
+	var descs []ocispec.Descriptor
+	for _, path := range paths {
+		name := filepath.ToSlash(filepath.Clean(path))
+		desc, err := descriptorFromWorkspace(ctx, space, mediaType, path)
+		if err != nil {
+			return nil, fmt.Errorf("file %q: %w", name, err)
+		}
+		desc.Annotations = map[string]string{
+			ocispec.AnnotationTitle: name,
+		}
+		files.Store(desc.Digest, workspaceFile{space: space, path: path})
+		descs = append(descs, desc)
+	}
+	return descs, nil
+}
+
+// descriptorFromWorkspace generates a descriptor for a file in a workspace. The
+// media type is detected from the file content if not set.
+func descriptorFromWorkspace(ctx context.Context, space workspace.Workspace, mediaType, path string) (ocispec.Descriptor, error) {
+	reader, err := space.GetReader(ctx, path)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	defer reader.Close()
+
+	digester := digest.Canonical.Digester()
+	counter := &byteCounter{}
+	tee := io.TeeReader(reader, io.MultiWriter(digester.Hash(), counter))
+
+	if mediaType == "" {
+		mType, err := mimetype.DetectReader(tee)
+		if err != nil {
+			return ocispec.Descriptor{}, fmt.Errorf("error detecting media type: %v", err)
+		}
+		mediaType = mType.String()
+	}
+
+	// Consume the remaining content for the digest and size.
+	if _, err := io.Copy(io.Discard, tee); err != nil {
+		return ocispec.Descriptor{}, err
+	}
+
+	return ocispec.Descriptor{
+		MediaType: mediaType,
+		Digest:    digester.Digest(),
+		Size:      counter.n,
+	}, nil
+}
+
+// byteCounter counts the bytes written to it.
+type byteCounter struct {
+	n int64
+}
+
+func (b *byteCounter) Write(p []byte) (int, error) {
+	b.n += int64(len(p))
+	return len(p), nil
+}
@@ -0,0 +1,177 @@
+package progress
+
+import (
+	"io"
+	"sync"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// EventType describes the state change reported by an Event.
+type EventType int
+
+const (
+	// EventStart is reported when a descriptor starts copying.
+	EventStart EventType = iota
+	// EventProgress is reported when bytes of a descriptor are copied.
+	EventProgress
+	// EventDone is reported when a descriptor has been copied.
+	EventDone
+	// EventSkipped is reported when a descriptor exists
+	// at the destination and is not copied.
+	EventSkipped
+)
+
+// String returns the name of the event type.
+func (e EventType) String() string {
+	switch e {
+	case EventStart:
+		return "start"
+	case EventProgress:
+		return "progress"
+	case EventDone:
+		return "done"
+	case EventSkipped:
+		return "skipped"
+	}
+	return "unknown"
+}
+
+// Event describes the progress of a descriptor being copied.
+type Event struct {
+	Type       EventType
+	Descriptor ocispec.Descriptor
+	// Bytes is the number of bytes of the
+	// descriptor that have been copied.
+	Bytes int64
+	// Status is the progress of the whole transfer
+	// at the time of the event.
+	Status Status
+}
+
+// Status is the progress of a transfer across all descriptors.
+// Totals grow as descriptors are discovered during the transfer.
+type Status struct {
+	BlobsDone  int
+	BlobsTotal int
+	BytesDone  int64
+	BytesTotal int64
+}
+
+// Func receives progress events. Events are delivered sequentially,
+// but may be delivered from different goroutines.
+type Func func(Event)
+
+// Tracker tracks bytes and blob counts per descriptor and
+// reports the changes to a Func.
+type Tracker struct {
+	fn Func
+	mu sync.Mutex
+	// copied stores the bytes copied by digest for the
+	// descriptors that are being copied.
+	copied map[string]int64
+	status Status
+}
+
+// NewTracker returns a Tracker that reports events to fn.
+func NewTracker(fn Func) *Tracker {
+	return &Tracker{
+		fn:     fn,
+		copied: map[string]int64{},
+	}
+}
+
+// Start records that the descriptor has started copying.
+func (t *Tracker) Start(desc ocispec.Descriptor) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, ok := t.copied[desc.Digest.String()]; ok {
+		return
+	}
+	t.copied[desc.Digest.String()] = 0
+	t.status.BlobsTotal++
+	t.status.BytesTotal += desc.Size
+	t.report(EventStart, desc, 0)
+}
+
+// Add records that n bytes of the descriptor have been copied. Bytes
+// beyond the size of the descriptor or for descriptors that have not
+// been started are ignored.
+func (t *Tracker) Add(desc ocispec.Descriptor, n int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	copied, ok := t.copied[desc.Digest.String()]
+	if !ok || n <= 0 {
+		return
+	}
+	if copied+n > desc.Size {
+		n = desc.Size - copied
+	}
+	if n <= 0 {
+		return
+	}
+	copied += n
+	t.copied[desc.Digest.String()] = copied
+	t.status.BytesDone += n
+	t.report(EventProgress, desc, copied)
+}
+
+// Done records that the descriptor has been copied.
+func (t *Tracker) Done(desc ocispec.Descriptor) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	copied, ok := t.copied[desc.Digest.String()]
+	if !ok {
+		return
+	}
+	delete(t.copied, desc.Digest.String())
+	t.status.BytesDone += desc.Size - copied
+	t.status.BlobsDone++
+	t.report(EventDone, desc, desc.Size)
+}
+
+// Skip records that the descriptor exists at
+// the destination and will not be copied.
+func (t *Tracker) Skip(desc ocispec.Descriptor) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.report(EventSkipped, desc, 0)
+}
+
+// Status returns the progress of the transfer.
+func (t *Tracker) Status() Status {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.status
+}
+
+// Reader returns a reader that records the bytes
+// read from r as copied bytes of the descriptor.
+func (t *Tracker) Reader(desc ocispec.Descriptor, r io.Reader) io.Reader {
+	return &reader{Reader: r, tracker: t, desc: desc}
+}
+
+// report delivers an event to the Func. The caller must hold the lock.
+func (t *Tracker) report(eventType EventType, desc ocispec.Descriptor, copied int64) {
+	if t.fn == nil {
+		return
+	}
+	t.fn(Event{
+		Type:       eventType,
+		Descriptor: desc,
+		Bytes:      copied,
+		Status:     t.status,
+	})
+}
+
+type reader struct {
+	io.Reader
+	tracker *Tracker
+	desc    ocispec.Descriptor
+}
+
+func (r *reader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	r.tracker.Add(r.desc, int64(n))
+	return n, err
+}
@@ -0,0 +1,69 @@
+package progress
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTracker(t *testing.T) {
+	data := []byte("hello world")
+	desc := ocispec.Descriptor{
+		MediaType: "text/plain",
+		Digest:    digest.FromBytes(data),
+		Size:      int64(len(data)),
+	}
+	other := ocispec.Descriptor{
+		MediaType: ocispec.MediaTypeImageManifest,
+		Digest:    digest.FromString("manifest"),
+		Size:      8,
+	}
+
+	var events []Event
+	tracker := NewTracker(func(event Event) {
+		events = append(events, event)
+	})
+
+	// Bytes for descriptors that have not started are ignored.
+	tracker.Add(desc, 5)
+	require.Empty(t, events)
+
+	tracker.Start(desc)
+	tracker.Start(desc)
+	tracker.Start(other)
+	require.Equal(t, Status{BlobsTotal: 2, BytesTotal: 19}, tracker.Status())
+
+	r := tracker.Reader(desc, splitReader(data))
+	_, err := io.Copy(ioutil.Discard, r)
+	require.NoError(t, err)
+	require.Equal(t, Status{BlobsTotal: 2, BytesDone: 11, BytesTotal: 19}, tracker.Status())
+
+	// Bytes beyond the descriptor size are ignored.
+	tracker.Add(desc, 5)
+	require.Equal(t, int64(11), tracker.Status().BytesDone)
+
+	tracker.Done(desc)
+	// The remaining bytes are counted when a descriptor
+	// is done without being read through the tracker.
+	tracker.Done(other)
+	tracker.Skip(ocispec.Descriptor{Digest: digest.FromString("skipped")})
+	require.Equal(t, Status{BlobsDone: 2, BlobsTotal: 2, BytesDone: 19, BytesTotal: 19}, tracker.Status())
+
+	var types []EventType
+	for _, event := range events {
+		types = append(types, event.Type)
+	}
+	require.Equal(t, []EventType{EventStart, EventStart, EventProgress, EventProgress, EventDone, EventDone, EventSkipped}, types)
+	require.Equal(t, int64(11), events[3].Bytes)
+}
+
+// splitReader returns a reader that returns the data in two reads.
+func splitReader(data []byte) io.Reader {
+	half := len(data) / 2
+	return io.MultiReader(bytes.NewReader(data[:half]), bytes.NewReader(data[half:]))
+}
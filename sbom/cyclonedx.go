@@ -0,0 +1,176 @@
+package sbom
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/opencontainers/go-digest"
+)
+
+const cycloneDXVersion = "1.4"
+
+// cycloneDXTypes are the valid CycloneDX component types.
+var cycloneDXTypes = map[string]bool{
+	"application":      true,
+	"framework":        true,
+	"library":          true,
+	"container":        true,
+	"operating-system": true,
+	"device":           true,
+	"firmware":         true,
+	"file":             true,
+}
+
+// cycloneDXDocument is a CycloneDX 1.4 JSON document.
+type cycloneDXDocument struct {
+	BOMFormat    string                `json:"bomFormat"`
+	SpecVersion  string                `json:"specVersion"`
+	SerialNumber string                `json:"serialNumber"`
+	Version      int                   `json:"version"`
+	Metadata     cycloneDXMetadata     `json:"metadata"`
+	Components   []cycloneDXComponent  `json:"components,omitempty"`
+	Dependencies []cycloneDXDependency `json:"dependencies,omitempty"`
+}
+
+type cycloneDXMetadata struct {
+	Timestamp string             `json:"timestamp"`
+	Tools     []cycloneDXTool    `json:"tools"`
+	Component cycloneDXComponent `json:"component"`
+}
+
+type cycloneDXTool struct {
+	Vendor  string `json:"vendor"`
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type cycloneDXComponent struct {
+	BOMRef     string               `json:"bom-ref"`
+	Type       string               `json:"type"`
+	MimeType   string               `json:"mime-type,omitempty"`
+	Name       string               `json:"name"`
+	Version    string               `json:"version,omitempty"`
+	Hashes     []cycloneDXHash      `json:"hashes,omitempty"`
+	Licenses   []cycloneDXLicense   `json:"licenses,omitempty"`
+	CPE        string               `json:"cpe,omitempty"`
+	PURL       string               `json:"purl,omitempty"`
+	Properties []cycloneDXProperty  `json:"properties,omitempty"`
+	Components []cycloneDXComponent `json:"components,omitempty"`
+}
+
+type cycloneDXHash struct {
+	Algorithm string `json:"alg"`
+	Content   string `json:"content"`
+}
+
+type cycloneDXLicense struct {
+	License cycloneDXLicenseName `json:"license"`
+}
+
+type cycloneDXLicenseName struct {
+	Name string `json:"name"`
+}
+
+type cycloneDXProperty struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type cycloneDXDependency struct {
+	Ref       string   `json:"ref"`
+	DependsOn []string `json:"dependsOn,omitempty"`
+}
+
+// cycloneDXHashAlgorithms maps digest algorithms to
+// CycloneDX hash algorithm names.
+var cycloneDXHashAlgorithms = map[digest.Algorithm]string{
+	digest.SHA256: "SHA-256",
+	digest.SHA384: "SHA-384",
+	digest.SHA512: "SHA-512",
+}
+
+// encodeCycloneDX writes the inventory as a CycloneDX 1.4 JSON document. The requested
+// collection is the metadata component and each collection lists its files as nested
+// components. Links between collections are recorded as dependencies.
+func encodeCycloneDX(w io.Writer, inventory Inventory, opts EncodeOptions) error {
+	doc := cycloneDXDocument{
+		BOMFormat:    "CycloneDX",
+		SpecVersion:  cycloneDXVersion,
+		SerialNumber: "urn:uuid:" + documentID(inventory, FormatCycloneDXJSON, opts.Created),
+		Version:      1,
+		Metadata: cycloneDXMetadata{
+			Timestamp: opts.Created.UTC().Format(time.RFC3339),
+			Tools: []cycloneDXTool{
+				{Vendor: "emporous", Name: "emporous", Version: opts.ToolVersion},
+			},
+		},
+	}
+
+	refs := map[digest.Digest]string{}
+	for _, pkg := range inventory.Packages {
+		refs[pkg.Digest] = pkg.Digest.String()
+	}
+
+	for i, pkg := range inventory.Packages {
+		component := cycloneDXComponent{
+			BOMRef:  refs[pkg.Digest],
+			Type:    "application",
+			Name:    packageName(pkg),
+			Version: packageVersion(pkg),
+			Hashes:  cycloneDXHashes(pkg.Digest),
+			Properties: []cycloneDXProperty{
+				{Name: "emporous:reference", Value: pkg.Reference},
+			},
+		}
+		if pkg.Component != nil {
+			if cycloneDXTypes[pkg.Component.Type] {
+				component.Type = pkg.Component.Type
+			}
+			for _, license := range pkg.Component.Licenses {
+				component.Licenses = append(component.Licenses, cycloneDXLicense{License: cycloneDXLicenseName{Name: license}})
+			}
+			if len(pkg.Component.CPEs) != 0 {
+				component.CPE = pkg.Component.CPEs[0]
+			}
+			component.PURL = pkg.Component.PURL
+		}
+		for _, file := range pkg.Files {
+			component.Components = append(component.Components, cycloneDXComponent{
+				BOMRef:   refs[pkg.Digest] + "/" + file.Name,
+				Type:     "file",
+				MimeType: file.MediaType,
+				Name:     file.Name,
+				Hashes:   cycloneDXHashes(file.Digest),
+			})
+		}
+
+		if i == 0 {
+			doc.Metadata.Component = component
+		} else {
+			doc.Components = append(doc.Components, component)
+		}
+
+		dependency := cycloneDXDependency{Ref: refs[pkg.Digest]}
+		for _, d := range pkg.Dependencies {
+			if ref, ok := refs[d]; ok {
+				dependency.DependsOn = append(dependency.DependsOn, ref)
+			}
+		}
+		doc.Dependencies = append(doc.Dependencies, dependency)
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(doc)
+}
+
+// cycloneDXHashes returns the digest as a CycloneDX hash
+// if the algorithm is supported.
+func cycloneDXHashes(d digest.Digest) []cycloneDXHash {
+	alg, ok := cycloneDXHashAlgorithms[d.Algorithm()]
+	if !ok {
+		return nil
+	}
+	return []cycloneDXHash{{Algorithm: alg, Content: d.Encoded()}}
+}
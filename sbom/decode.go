@@ -0,0 +1,423 @@
+package sbom
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	empspec "github.com/emporous/collection-spec/specs-go/v1alpha1"
+	"github.com/opencontainers/go-digest"
+)
+
+// FormatSyftJSON is the syft JSON format. It
+// is only supported when decoding documents.
+const FormatSyftJSON Format = "syft-json"
+
+// Document is the component information read from an SBOM document.
+type Document struct {
+	// Format is the detected format of the document.
+	Format Format
+	// Subject is the component the document describes, if set.
+	Subject *empspec.Component
+	// Entries associate files with the
+	// components that contain them.
+	Entries []Entry
+}
+
+// Entry associates a file with a component.
+type Entry struct {
+	// Path is the slash separated path of the file
+	// relative to the scanned directory, if known.
+	Path string
+	// Digests are the known checksums of the file.
+	Digests []digest.Digest
+	// Component is the component containing the file.
+	Component empspec.Component
+}
+
+// String returns a display name for the entry.
+func (e Entry) String() string {
+	name := e.Path
+	if name == "" && len(e.Digests) != 0 {
+		name = e.Digests[0].String()
+	}
+	if e.Component.Version != "" {
+		return fmt.Sprintf("%s (%s %s)", name, e.Component.Name, e.Component.Version)
+	}
+	return fmt.Sprintf("%s (%s)", name, e.Component.Name)
+}
+
+// Decode reads a syft, SPDX, or CycloneDX JSON document. The format is detected
+// from the document content.
+func Decode(data []byte) (Document, error) {
+	var detect struct {
+		SPDXVersion string          `json:"spdxVersion"`
+		BOMFormat   string          `json:"bomFormat"`
+		Artifacts   json.RawMessage `json:"artifacts"`
+	}
+	if err := json.Unmarshal(data, &detect); err != nil {
+		return Document{}, fmt.Errorf("error decoding SBOM: %w", err)
+	}
+
+	switch {
+	case detect.SPDXVersion != "":
+		return decodeSPDX(data)
+	case detect.BOMFormat == "CycloneDX":
+		return decodeCycloneDX(data)
+	case detect.Artifacts != nil:
+		return decodeSyft(data)
+	default:
+		return Document{}, errors.New("error decoding SBOM: unknown format, expected a syft, SPDX, or CycloneDX JSON document")
+	}
+}
+
+// cleanPath returns a slash separated path
+// relative to the scanned directory.
+func cleanPath(p string) string {
+	if p == "" {
+		return ""
+	}
+	p = path.Clean(strings.ReplaceAll(p, "\\", "/"))
+	return strings.TrimPrefix(p, "/")
+}
+
+// parseDigest converts an algorithm and hex encoded
+// checksum to a digest. Unsupported algorithms are skipped.
+func parseDigest(algorithm, value string) (digest.Digest, bool) {
+	alg := digest.Algorithm(strings.ToLower(strings.ReplaceAll(algorithm, "-", "")))
+	if !alg.Available() {
+		return "", false
+	}
+	d := digest.NewDigestFromEncoded(alg, strings.ToLower(value))
+	if d.Validate() != nil {
+		return "", false
+	}
+	return d, true
+}
+
+// syftDocument is the subset of the syft JSON format
+// needed to read component information.
+type syftDocument struct {
+	Artifacts []struct {
+		Name      string            `json:"name"`
+		Version   string            `json:"version"`
+		Type      string            `json:"type"`
+		FoundBy   string            `json:"foundBy"`
+		Language  string            `json:"language"`
+		PURL      string            `json:"purl"`
+		Licenses  []json.RawMessage `json:"licenses"`
+		CPEs      []json.RawMessage `json:"cpes"`
+		Locations []struct {
+			Path string `json:"path"`
+		} `json:"locations"`
+	} `json:"artifacts"`
+	Files []struct {
+		Location struct {
+			Path string `json:"path"`
+		} `json:"location"`
+		Digests []struct {
+			Algorithm string `json:"algorithm"`
+			Value     string `json:"value"`
+		} `json:"digests"`
+	} `json:"files"`
+	Source struct {
+		Type    string `json:"type"`
+		Name    string `json:"name"`
+		Version string `json:"version"`
+	} `json:"source"`
+}
+
+// decodeSyft reads a syft JSON document. Each artifact location is an entry
+// and file digests are matched to the entries by path.
+func decodeSyft(data []byte) (Document, error) {
+	var doc syftDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return Document{}, fmt.Errorf("error decoding syft SBOM: %w", err)
+	}
+
+	result := Document{Format: FormatSyftJSON}
+	if doc.Source.Name != "" {
+		result.Subject = &empspec.Component{
+			Name:    doc.Source.Name,
+			Version: doc.Source.Version,
+			Type:    doc.Source.Type,
+			FoundBy: "syft",
+		}
+	}
+
+	digests := map[string][]digest.Digest{}
+	for _, file := range doc.Files {
+		p := cleanPath(file.Location.Path)
+		for _, d := range file.Digests {
+			if dgst, ok := parseDigest(d.Algorithm, d.Value); ok {
+				digests[p] = append(digests[p], dgst)
+			}
+		}
+	}
+
+	for _, artifact := range doc.Artifacts {
+		component := empspec.Component{
+			Name:     artifact.Name,
+			Version:  artifact.Version,
+			Type:     artifact.Type,
+			FoundBy:  artifact.FoundBy,
+			Language: artifact.Language,
+			PURL:     artifact.PURL,
+			Licenses: syftValues(artifact.Licenses, "value"),
+			CPEs:     syftValues(artifact.CPEs, "cpe"),
+		}
+		for _, location := range artifact.Locations {
+			component.Locations = append(component.Locations, cleanPath(location.Path))
+		}
+		for _, location := range component.Locations {
+			result.Entries = append(result.Entries, Entry{
+				Path:      location,
+				Digests:   digests[location],
+				Component: component,
+			})
+		}
+	}
+	return result, nil
+}
+
+// syftValues reads a list that is either a list of strings or a list
+// of objects, depending on the syft schema version.
+func syftValues(raw []json.RawMessage, key string) []string {
+	var values []string
+	for _, r := range raw {
+		var s string
+		if err := json.Unmarshal(r, &s); err == nil {
+			values = append(values, s)
+			continue
+		}
+		var obj map[string]json.RawMessage
+		if err := json.Unmarshal(r, &obj); err != nil {
+			continue
+		}
+		if err := json.Unmarshal(obj[key], &s); err == nil && s != "" {
+			values = append(values, s)
+		}
+	}
+	return values
+}
+
+// decodeSPDX reads an SPDX JSON document. Files contained in a package are
+// entries for that package and files not contained in a package are entries for themselves.
+func decodeSPDX(data []byte) (Document, error) {
+	var doc struct {
+		DocumentDescribes []string           `json:"documentDescribes"`
+		Packages          []spdxPackage      `json:"packages"`
+		Files             []spdxFile         `json:"files"`
+		Relationships     []spdxRelationship `json:"relationships"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return Document{}, fmt.Errorf("error decoding SPDX SBOM: %w", err)
+	}
+
+	result := Document{Format: FormatSPDXJSON}
+	packages := map[string]empspec.Component{}
+	for _, pkg := range doc.Packages {
+		packages[pkg.SPDXID] = spdxComponent(pkg)
+	}
+
+	described := doc.DocumentDescribes
+	containedBy := map[string][]string{}
+	for _, r := range doc.Relationships {
+		switch r.RelationshipType {
+		case spdxDescribes:
+			if r.SPDXElementID == spdxDocumentID {
+				described = append(described, r.RelatedSPDXElement)
+			}
+		case spdxContains:
+			containedBy[r.RelatedSPDXElement] = append(containedBy[r.RelatedSPDXElement], r.SPDXElementID)
+		case "CONTAINED_BY":
+			containedBy[r.SPDXElementID] = append(containedBy[r.SPDXElementID], r.RelatedSPDXElement)
+		}
+	}
+	for _, id := range described {
+		if component, ok := packages[id]; ok {
+			result.Subject = &component
+			break
+		}
+	}
+
+	for _, file := range doc.Files {
+		entry := Entry{Path: cleanPath(file.FileName)}
+		for _, checksum := range file.Checksums {
+			if dgst, ok := parseDigest(checksum.Algorithm, checksum.ChecksumValue); ok {
+				entry.Digests = append(entry.Digests, dgst)
+			}
+		}
+
+		var owners []empspec.Component
+		for _, id := range containedBy[file.SPDXID] {
+			if component, ok := packages[id]; ok {
+				owners = append(owners, component)
+			}
+		}
+		if len(owners) == 0 {
+			component := empspec.Component{
+				Name: entry.Path,
+				Type: "file",
+			}
+			if license := spdxLicense(file.LicenseConcluded); license != "" {
+				component.Licenses = []string{license}
+			}
+			owners = append(owners, component)
+		}
+		for _, owner := range owners {
+			e := entry
+			e.Component = owner
+			result.Entries = append(result.Entries, e)
+		}
+	}
+	return result, nil
+}
+
+// spdxComponent converts an SPDX package to a component.
+func spdxComponent(pkg spdxPackage) empspec.Component {
+	component := empspec.Component{
+		Name:    pkg.Name,
+		Version: pkg.VersionInfo,
+		Type:    strings.ToLower(pkg.PrimaryPurpose),
+	}
+	license := spdxLicense(pkg.LicenseDeclared)
+	if license == "" {
+		license = spdxLicense(pkg.LicenseConcluded)
+	}
+	if license != "" {
+		component.Licenses = []string{license}
+	}
+	for _, ref := range pkg.ExternalRefs {
+		switch ref.ReferenceType {
+		case "cpe23Type", "cpe22Type":
+			component.CPEs = append(component.CPEs, ref.ReferenceLocator)
+		case "purl":
+			component.PURL = ref.ReferenceLocator
+		}
+	}
+	return component
+}
+
+// spdxLicense returns the license expression or an empty
+// string if the license is not asserted.
+func spdxLicense(license string) string {
+	switch license {
+	case "", spdxNoAssert, "NONE":
+		return ""
+	}
+	return license
+}
+
+// decodeCycloneDX reads a CycloneDX JSON document. File components are entries for
+// their parent component, or for themselves at the top level. Components with locations
+// recorded by syft are entries for each location.
+func decodeCycloneDX(data []byte) (Document, error) {
+	var doc struct {
+		Metadata struct {
+			Component *cycloneDXDecodeComponent `json:"component"`
+		} `json:"metadata"`
+		Components []cycloneDXDecodeComponent `json:"components"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return Document{}, fmt.Errorf("error decoding CycloneDX SBOM: %w", err)
+	}
+
+	result := Document{Format: FormatCycloneDXJSON}
+	if doc.Metadata.Component != nil {
+		subject := doc.Metadata.Component.component()
+		result.Subject = &subject
+		result.Entries = append(result.Entries, doc.Metadata.Component.entries(nil)...)
+	}
+	for _, c := range doc.Components {
+		result.Entries = append(result.Entries, c.entries(nil)...)
+	}
+	return result, nil
+}
+
+// cycloneDXDecodeComponent is a CycloneDX component
+// with fields from any supported specification version.
+type cycloneDXDecodeComponent struct {
+	Type     string          `json:"type"`
+	Name     string          `json:"name"`
+	Version  string          `json:"version"`
+	CPE      string          `json:"cpe"`
+	PURL     string          `json:"purl"`
+	Hashes   []cycloneDXHash `json:"hashes"`
+	Licenses []struct {
+		License struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		} `json:"license"`
+		Expression string `json:"expression"`
+	} `json:"licenses"`
+	Properties []cycloneDXProperty        `json:"properties"`
+	Components []cycloneDXDecodeComponent `json:"components"`
+}
+
+// component converts the CycloneDX component.
+func (c cycloneDXDecodeComponent) component() empspec.Component {
+	component := empspec.Component{
+		Name:    c.Name,
+		Version: c.Version,
+		Type:    c.Type,
+		PURL:    c.PURL,
+	}
+	if c.CPE != "" {
+		component.CPEs = []string{c.CPE}
+	}
+	for _, l := range c.Licenses {
+		switch {
+		case l.Expression != "":
+			component.Licenses = append(component.Licenses, l.Expression)
+		case l.License.ID != "":
+			component.Licenses = append(component.Licenses, l.License.ID)
+		case l.License.Name != "":
+			component.Licenses = append(component.Licenses, l.License.Name)
+		}
+	}
+	for _, p := range c.Properties {
+		if strings.HasPrefix(p.Name, "syft:location:") && strings.HasSuffix(p.Name, ":path") {
+			component.Locations = append(component.Locations, cleanPath(p.Value))
+		}
+		if p.Name == "syft:package:foundBy" {
+			component.FoundBy = p.Value
+		}
+		if p.Name == "syft:package:language" {
+			component.Language = p.Value
+		}
+	}
+	return component
+}
+
+// entries returns the entries for the component and nested components.
+// File components are entries for the parent component, if set.
+func (c cycloneDXDecodeComponent) entries(parent *empspec.Component) []Entry {
+	var entries []Entry
+	component := c.component()
+	if c.Type == "file" {
+		entry := Entry{Path: cleanPath(c.Name), Component: component}
+		if parent != nil {
+			entry.Component = *parent
+		}
+		for _, h := range c.Hashes {
+			if dgst, ok := parseDigest(h.Algorithm, h.Content); ok {
+				entry.Digests = append(entry.Digests, dgst)
+			}
+		}
+		entries = append(entries, entry)
+	}
+	for _, location := range component.Locations {
+		entries = append(entries, Entry{Path: location, Component: component})
+	}
+	for _, nested := range c.Components {
+		owner := &component
+		if c.Type == "file" {
+			owner = parent
+		}
+		entries = append(entries, nested.entries(owner)...)
+	}
+	return entries
+}
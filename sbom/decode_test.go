@@ -0,0 +1,233 @@
+package sbom
+
+import (
+	"io/ioutil"
+	"testing"
+
+	empspec "github.com/emporous/collection-spec/specs-go/v1alpha1"
+	"github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	fishFileDigest  = digest.Digest("sha256:2e30f6131ce2164ed5ef017845130727291417d60a1be6fad669bdc4473289cd")
+	aboutFileDigest = digest.Digest("sha256:5c29ebcf4a3e7ac6dca6dcea98b4fa98de57c4aca65fa0b49989fbeab1dfdf84")
+)
+
+func TestDecode(t *testing.T) {
+	fishComponent := empspec.Component{
+		Name:     "fish-images",
+		Version:  "1.0.0",
+		Type:     "library",
+		Licenses: []string{"MIT"},
+		CPEs:     []string{"cpe:2.3:a:test:fish-images:1.0.0:*:*:*:*:*:*:*"},
+		PURL:     "pkg:generic/fish-images@1.0.0",
+	}
+	aboutComponent := empspec.Component{
+		Name:      "about",
+		Version:   "0.2.0",
+		Type:      "library",
+		FoundBy:   "javascript-package-cataloger",
+		Locations: []string{"supplementary/about.json"},
+		PURL:      "pkg:npm/about@0.2.0",
+	}
+
+	type spec struct {
+		name     string
+		path     string
+		data     []byte
+		expected Document
+		expError string
+	}
+
+	cases := []spec{
+		{
+			name: "Success/Syft",
+			path: "testdata/syft.json",
+			expected: Document{
+				Format: FormatSyftJSON,
+				Subject: &empspec.Component{
+					Name:    "multi-level-workspace",
+					Version: "v1.0.0",
+					Type:    "directory",
+					FoundBy: "syft",
+				},
+				Entries: []Entry{
+					{
+						Path:    "images/fish.jpg",
+						Digests: []digest.Digest{fishFileDigest},
+						Component: empspec.Component{
+							Name:      "fish-images",
+							Version:   "1.0.0",
+							Type:      "binary",
+							FoundBy:   "binary-cataloger",
+							Locations: []string{"images/fish.jpg"},
+							Licenses:  []string{"MIT"},
+							CPEs:      []string{"cpe:2.3:a:test:fish-images:1.0.0:*:*:*:*:*:*:*"},
+							PURL:      "pkg:generic/fish-images@1.0.0",
+						},
+					},
+					{
+						Path: "supplementary/about.json",
+						Component: empspec.Component{
+							Name:      "about",
+							Version:   "0.2.0",
+							Type:      "npm",
+							FoundBy:   "javascript-package-cataloger",
+							Locations: []string{"supplementary/about.json"},
+							Licenses:  []string{"Apache-2.0"},
+							Language:  "javascript",
+							CPEs:      []string{"cpe:2.3:a:test:about:0.2.0:*:*:*:*:*:*:*"},
+							PURL:      "pkg:npm/about@0.2.0",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "Success/SPDX",
+			path: "testdata/spdx.json",
+			expected: Document{
+				Format: FormatSPDXJSON,
+				Subject: &empspec.Component{
+					Name:     "multi-level-workspace",
+					Version:  "v1.0.0",
+					Type:     "application",
+					Licenses: []string{"Apache-2.0"},
+					PURL:     "pkg:generic/multi-level-workspace@v1.0.0",
+				},
+				Entries: []Entry{
+					{
+						Path:    "images/fish.jpg",
+						Digests: []digest.Digest{fishFileDigest},
+						Component: empspec.Component{
+							Name:     "fish-images",
+							Version:  "1.0.0",
+							Licenses: []string{"MIT"},
+							CPEs:     []string{"cpe:2.3:a:test:fish-images:1.0.0:*:*:*:*:*:*:*"},
+						},
+					},
+					{
+						Path:    "docs/about.json",
+						Digests: []digest.Digest{aboutFileDigest},
+						Component: empspec.Component{
+							Name:     "docs/about.json",
+							Type:     "file",
+							Licenses: []string{"CC-BY-4.0"},
+						},
+					},
+					{
+						Path:    "missing.txt",
+						Digests: []digest.Digest{"sha256:a948904f2f0f479b8f8197694b30184b0d2ed1c1cd2a1ec0fb85d299a192a447"},
+						Component: empspec.Component{
+							Name: "missing.txt",
+							Type: "file",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "Success/CycloneDX",
+			path: "testdata/cyclonedx.json",
+			expected: Document{
+				Format: FormatCycloneDXJSON,
+				Subject: &empspec.Component{
+					Name:     "multi-level-workspace",
+					Version:  "v1.0.0",
+					Type:     "application",
+					Licenses: []string{"Apache-2.0 OR MIT"},
+				},
+				Entries: []Entry{
+					{
+						Path:      "images/fish.jpg",
+						Digests:   []digest.Digest{fishFileDigest},
+						Component: fishComponent,
+					},
+					{
+						Path:      "supplementary/about.json",
+						Component: aboutComponent,
+					},
+				},
+			},
+		},
+		{
+			name:     "Failure/UnknownFormat",
+			data:     []byte(`{"name":"test"}`),
+			expError: "error decoding SBOM: unknown format, expected a syft, SPDX, or CycloneDX JSON document",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			data := c.data
+			if c.path != "" {
+				var err error
+				data, err = ioutil.ReadFile(c.path)
+				require.NoError(t, err)
+			}
+			doc, err := Decode(data)
+			if c.expError != "" {
+				require.EqualError(t, err, c.expError)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, c.expected, doc)
+		})
+	}
+}
+
+func TestIndexMatch(t *testing.T) {
+	data, err := ioutil.ReadFile("testdata/spdx.json")
+	require.NoError(t, err)
+	doc, err := Decode(data)
+	require.NoError(t, err)
+
+	type spec struct {
+		name     string
+		location string
+		digest   digest.Digest
+		expName  string
+		expMatch bool
+	}
+
+	cases := []spec{
+		{
+			name:     "Success/MatchByPath",
+			location: "images/fish.jpg",
+			digest:   fishFileDigest,
+			expName:  "fish-images",
+			expMatch: true,
+		},
+		{
+			name:     "Success/MatchByChecksum",
+			location: "supplementary/about.json",
+			digest:   aboutFileDigest,
+			expName:  "docs/about.json",
+			expMatch: true,
+		},
+		{
+			name:     "Failure/ChecksumMismatch",
+			location: "missing.txt",
+			digest:   digest.FromString("changed"),
+		},
+		{
+			name:     "Failure/NoMatch",
+			location: "info.json",
+			digest:   digest.FromString("info"),
+		},
+	}
+
+	index := NewIndex(doc)
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			component, ok := index.Match(c.location, c.digest)
+			require.Equal(t, c.expMatch, ok)
+			require.Equal(t, c.expName, component.Name)
+		})
+	}
+
+	unmatched := index.Unmatched()
+	require.Len(t, unmatched, 1)
+	require.Equal(t, "missing.txt (missing.txt)", unmatched[0].String())
+}
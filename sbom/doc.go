@@ -0,0 +1,24 @@
+/*
+Copyright 2022 Emporous Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sbom
+
+// This package generates software bills of materials from Emporous collections.
+//
+// An Inventory is created by walking a collection and any linked collections. Each collection
+// is described as a package using the component information stored under the core-descriptor
+// attributes. Collection files are described by their checksums and linked collections are
+// recorded as dependencies. The Inventory can be encoded as an SPDX or CycloneDX JSON document.
+//
+// Existing syft, SPDX, and CycloneDX JSON documents can be decoded to import component information.
+// An Index matches workspace files to the decoded entries by path and checksum.
@@ -0,0 +1,103 @@
+package sbom
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/opencontainers/go-digest"
+)
+
+// Format is an SBOM document format.
+type Format string
+
+const (
+	// FormatSPDXJSON is the SPDX 2.3 JSON format.
+	FormatSPDXJSON Format = "spdx-json"
+	// FormatCycloneDXJSON is the CycloneDX 1.4 JSON format.
+	FormatCycloneDXJSON Format = "cyclonedx-json"
+)
+
+// Formats are the supported SBOM document formats.
+var Formats = []Format{FormatSPDXJSON, FormatCycloneDXJSON}
+
+// ParseFormat validates an SBOM format name.
+func ParseFormat(format string) (Format, error) {
+	for _, f := range Formats {
+		if string(f) == format {
+			return f, nil
+		}
+	}
+	var names []string
+	for _, f := range Formats {
+		names = append(names, string(f))
+	}
+	return "", fmt.Errorf("unsupported SBOM format %q: must be one of %s", format, strings.Join(names, ", "))
+}
+
+// EncodeOptions configure the generated SBOM document.
+type EncodeOptions struct {
+	// Created is the document creation time.
+	Created time.Time
+	// ToolVersion is the version of the tool generating the document.
+	ToolVersion string
+}
+
+// Encode writes the inventory to the writer in the given format.
+func Encode(w io.Writer, inventory Inventory, format Format, opts EncodeOptions) error {
+	if len(inventory.Packages) == 0 {
+		return fmt.Errorf("inventory is empty")
+	}
+	switch format {
+	case FormatSPDXJSON:
+		return encodeSPDX(w, inventory, opts)
+	case FormatCycloneDXJSON:
+		return encodeCycloneDX(w, inventory, opts)
+	default:
+		_, err := ParseFormat(string(format))
+		return err
+	}
+}
+
+// documentID returns a stable identifier for the document based on the content
+// of the inventory, the document format and the creation time, so documents
+// that differ in format or creation time have different identifiers.
+func documentID(inventory Inventory, format Format, created time.Time) string {
+	h := sha256.New()
+	fmt.Fprintln(h, format)
+	fmt.Fprintln(h, created.UTC().Format(time.RFC3339Nano))
+	for _, pkg := range inventory.Packages {
+		fmt.Fprintln(h, pkg.Digest)
+	}
+	sum := h.Sum(nil)
+	// Format the first 16 bytes as a name based (version 5) UUID.
+	sum[6] = (sum[6] & 0x0f) | 0x50
+	sum[8] = (sum[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", sum[0:4], sum[4:6], sum[6:8], sum[8:10], sum[10:16])
+}
+
+// packageName returns the component name for the package or
+// the collection reference if no component information is set.
+func packageName(pkg Package) string {
+	if pkg.Component != nil && pkg.Component.Name != "" {
+		return pkg.Component.Name
+	}
+	return pkg.Reference
+}
+
+// packageVersion returns the component version for
+// the package or the collection digest.
+func packageVersion(pkg Package) string {
+	if pkg.Component != nil && pkg.Component.Version != "" {
+		return pkg.Component.Version
+	}
+	return pkg.Digest.String()
+}
+
+// checksumAlgorithm returns the algorithm of the digest in upper case
+// without separators (e.g. SHA256).
+func checksumAlgorithm(d digest.Digest) string {
+	return strings.ToUpper(d.Algorithm().String())
+}
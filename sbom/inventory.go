@@ -0,0 +1,182 @@
+package sbom
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	empspec "github.com/emporous/collection-spec/specs-go/v1alpha1"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/registry"
+
+	"github.com/emporous/emporous-go/model"
+	"github.com/emporous/emporous-go/nodes/collection"
+	"github.com/emporous/emporous-go/nodes/descriptor/v2"
+)
+
+// LoaderFunc loads the collection stored at a reference.
+type LoaderFunc func(ctx context.Context, reference string) (collection.Collection, error)
+
+// Inventory describes a collection and the collections it links to.
+type Inventory struct {
+	// Packages are the collections in the inventory. The
+	// first package is the requested collection.
+	Packages []Package
+}
+
+// Package describes a single collection.
+type Package struct {
+	// Reference is the location the collection was loaded from.
+	Reference string
+	// Digest is the digest of the collection root manifest.
+	Digest digest.Digest
+	// Component is the component information stored under the
+	// core-descriptor attributes of the root manifest, if set.
+	Component *empspec.Component
+	// Files are the files contained in the collection.
+	Files []File
+	// Dependencies are the root manifest digests of
+	// the linked collections.
+	Dependencies []digest.Digest
+}
+
+// File describes a single file in a collection.
+type File struct {
+	// Name is the path of the file in the collection.
+	Name string
+	// Digest is the digest of the file content.
+	Digest digest.Digest
+	// Size is the size of the file content in bytes.
+	Size int64
+	// MediaType is the media type of the file.
+	MediaType string
+}
+
+// Root returns the requested collection.
+func (i Inventory) Root() Package {
+	if len(i.Packages) == 0 {
+		return Package{}
+	}
+	return i.Packages[0]
+}
+
+// NewInventory walks the collection at the reference and all linked
+// collections to create an Inventory.
+func NewInventory(ctx context.Context, reference string, load LoaderFunc) (Inventory, error) {
+	var inventory Inventory
+	seen := map[digest.Digest]struct{}{}
+	queue := []string{reference}
+	for len(queue) != 0 {
+		ref := queue[0]
+		queue = queue[1:]
+
+		graph, err := load(ctx, ref)
+		if err != nil {
+			return Inventory{}, fmt.Errorf("collection %s: %w", ref, err)
+		}
+		pkg, links, err := newPackage(ref, graph)
+		if err != nil {
+			return Inventory{}, fmt.Errorf("collection %s: %w", ref, err)
+		}
+		if _, ok := seen[pkg.Digest]; ok {
+			continue
+		}
+		seen[pkg.Digest] = struct{}{}
+		inventory.Packages = append(inventory.Packages, pkg)
+
+		for _, link := range links {
+			if _, ok := seen[digest.Digest(link.ID())]; ok {
+				continue
+			}
+			linkRef, err := linkReference(ref, link)
+			if err != nil {
+				return Inventory{}, err
+			}
+			queue = append(queue, linkRef)
+		}
+	}
+	return inventory, nil
+}
+
+// newPackage describes the collection graph as a Package and returns
+// the link nodes found in the collection.
+func newPackage(reference string, graph collection.Collection) (Package, []*v2.Node, error) {
+	root, err := graph.Root()
+	if err != nil {
+		return Package{}, nil, err
+	}
+	pkg := Package{
+		Reference: reference,
+		Digest:    digest.Digest(root.ID()),
+	}
+	// Files can describe their own components, so the collection
+	// component is only read from the root manifest.
+	if rootNode, ok := root.(*v2.Node); ok && rootNode.Properties != nil && rootNode.Properties.IsAComponent() {
+		pkg.Component = &rootNode.Properties.Descriptor.Component
+	}
+
+	var links []*v2.Node
+	seen := map[string]struct{}{}
+	var walk func(node model.Node)
+	walk = func(node model.Node) {
+		if _, ok := seen[node.ID()]; ok {
+			return
+		}
+		seen[node.ID()] = struct{}{}
+
+		desc, ok := node.(*v2.Node)
+		if !ok {
+			return
+		}
+		if desc.Properties != nil && desc.Properties.IsALink() {
+			links = append(links, desc)
+			pkg.Dependencies = append(pkg.Dependencies, digest.Digest(desc.ID()))
+			return
+		}
+
+		successors := graph.From(node.ID())
+		if len(successors) != 0 {
+			for _, successor := range successors {
+				walk(successor)
+			}
+			return
+		}
+
+		if title, ok := desc.Descriptor().Annotations[ocispec.AnnotationTitle]; ok {
+			pkg.Files = append(pkg.Files, File{
+				Name:      title,
+				Digest:    desc.Descriptor().Digest,
+				Size:      desc.Descriptor().Size,
+				MediaType: desc.Descriptor().MediaType,
+			})
+		}
+	}
+	walk(root)
+
+	sort.Slice(pkg.Files, func(i, j int) bool {
+		return pkg.Files[i].Name < pkg.Files[j].Name
+	})
+	sort.Slice(pkg.Dependencies, func(i, j int) bool {
+		return pkg.Dependencies[i] < pkg.Dependencies[j]
+	})
+	sort.Slice(links, func(i, j int) bool {
+		return links[i].ID() < links[j].ID()
+	})
+	return pkg, links, nil
+}
+
+// linkReference constructs the reference of a linked collection. Links without
+// registry and namespace hints are stored in the same repository as the parent.
+func linkReference(parent string, link *v2.Node) (string, error) {
+	hints := link.Properties.Link
+	if hints.RegistryHint != "" || hints.NamespaceHint != "" {
+		return fmt.Sprintf("%s/%s@%s", hints.RegistryHint, hints.NamespaceHint, link.ID()), nil
+	}
+	ref, err := registry.ParseReference(parent)
+	if err != nil {
+		return "", fmt.Errorf("link %s: %w", link.ID(), err)
+	}
+	ref.Reference = link.ID()
+	return ref.String(), nil
+}
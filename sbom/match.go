@@ -0,0 +1,84 @@
+package sbom
+
+import (
+	"github.com/opencontainers/go-digest"
+
+	empspec "github.com/emporous/collection-spec/specs-go/v1alpha1"
+)
+
+// Index matches files to the entries of an SBOM document
+// and tracks which entries have been matched.
+type Index struct {
+	entries []Entry
+	matched []bool
+}
+
+// NewIndex creates an Index for the document entries.
+func NewIndex(doc Document) *Index {
+	return &Index{
+		entries: doc.Entries,
+		matched: make([]bool, len(doc.Entries)),
+	}
+}
+
+// Match returns the component for the file at the location with the given digest.
+// Entries are matched by path if the entry checksums agree with the digest. If no entry
+// matches the path, entries are matched by checksum. The first matching entry is returned
+// and all matching entries are marked as matched.
+func (i *Index) Match(location string, dgst digest.Digest) (empspec.Component, bool) {
+	location = cleanPath(location)
+
+	var matches []int
+	for n, entry := range i.entries {
+		if entry.Path == location && checksumAgrees(entry, dgst) {
+			matches = append(matches, n)
+		}
+	}
+	if len(matches) == 0 {
+		for n, entry := range i.entries {
+			if hasDigest(entry, dgst) {
+				matches = append(matches, n)
+			}
+		}
+	}
+	if len(matches) == 0 {
+		return empspec.Component{}, false
+	}
+
+	for _, n := range matches {
+		i.matched[n] = true
+	}
+	return i.entries[matches[0]].Component, true
+}
+
+// Unmatched returns the entries that did not match any file.
+func (i *Index) Unmatched() []Entry {
+	var unmatched []Entry
+	for n, entry := range i.entries {
+		if !i.matched[n] {
+			unmatched = append(unmatched, entry)
+		}
+	}
+	return unmatched
+}
+
+// checksumAgrees returns false if the entry has a checksum
+// with the same algorithm as the digest that does not match.
+func checksumAgrees(entry Entry, dgst digest.Digest) bool {
+	for _, d := range entry.Digests {
+		if d.Algorithm() == dgst.Algorithm() && d != dgst {
+			return false
+		}
+	}
+	return true
+}
+
+// hasDigest returns whether the entry has the digest.
+func hasDigest(entry Entry, dgst digest.Digest) bool {
+	for _, d := range entry.Digests {
+		if d == dgst {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,239 @@
+package sbom
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	empspec "github.com/emporous/collection-spec/specs-go/v1alpha1"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+
+	"github.com/emporous/emporous-go/nodes/collection"
+	"github.com/emporous/emporous-go/nodes/descriptor/v2"
+)
+
+var (
+	rootDigest   = digest.FromString("root")
+	linkDigest   = digest.FromString("linked")
+	fishDigest   = digest.FromString("fish")
+	aboutDigest  = digest.FromString("about")
+	configDigest = digest.FromString("config")
+)
+
+func TestNewInventory(t *testing.T) {
+	type spec struct {
+		name     string
+		loader   LoaderFunc
+		expected Inventory
+		expError string
+	}
+
+	cases := []spec{
+		{
+			name:   "Success/WithLinks",
+			loader: testLoader(t),
+			expected: Inventory{
+				Packages: []Package{
+					{
+						Reference: "localhost:5000/test:latest",
+						Digest:    rootDigest,
+						Component: &empspec.Component{
+							Name:     "test",
+							Version:  "v0.1.0",
+							Licenses: []string{"Apache-2.0"},
+							PURL:     "pkg:oci/test@v0.1.0",
+						},
+						Files: []File{
+							{Name: "fish.jpg", Digest: fishDigest, Size: 4, MediaType: "image/jpeg"},
+						},
+						Dependencies: []digest.Digest{linkDigest},
+					},
+					{
+						Reference: "localhost:5000/linked@" + linkDigest.String(),
+						Digest:    linkDigest,
+						Files: []File{
+							{Name: "about.json", Digest: aboutDigest, Size: 5, MediaType: "application/json"},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "Failure/NotFound",
+			loader: func(ctx context.Context, reference string) (collection.Collection, error) {
+				return collection.Collection{}, errors.New("not found")
+			},
+			expError: "collection localhost:5000/test:latest: not found",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			inventory, err := NewInventory(context.Background(), "localhost:5000/test:latest", c.loader)
+			if c.expError != "" {
+				require.EqualError(t, err, c.expError)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, c.expected, inventory)
+		})
+	}
+}
+
+func TestEncode(t *testing.T) {
+	inventory, err := NewInventory(context.Background(), "localhost:5000/test:latest", testLoader(t))
+	require.NoError(t, err)
+	opts := EncodeOptions{
+		Created:     time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+		ToolVersion: "v0.0.0",
+	}
+
+	t.Run("Success/SPDX", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, Encode(&buf, inventory, FormatSPDXJSON, opts))
+		var doc spdxDocument
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &doc))
+		require.Equal(t, "SPDX-2.3", doc.SPDXVersion)
+		require.Equal(t, "2023-01-01T00:00:00Z", doc.CreationInfo.Created)
+		require.Len(t, doc.Packages, 2)
+		require.Equal(t, "test", doc.Packages[0].Name)
+		require.Equal(t, "Apache-2.0", doc.Packages[0].LicenseDeclared)
+		require.Equal(t, []spdxFile{
+			{
+				SPDXID:           "SPDXRef-File-1",
+				FileName:         "./fish.jpg",
+				Checksums:        []spdxChecksum{{Algorithm: "SHA256", ChecksumValue: fishDigest.Encoded()}},
+				LicenseConcluded: "NOASSERTION",
+				CopyrightText:    "NOASSERTION",
+			},
+			{
+				SPDXID:           "SPDXRef-File-2",
+				FileName:         "./about.json",
+				Checksums:        []spdxChecksum{{Algorithm: "SHA256", ChecksumValue: aboutDigest.Encoded()}},
+				LicenseConcluded: "NOASSERTION",
+				CopyrightText:    "NOASSERTION",
+			},
+		}, doc.Files)
+		rootID := "SPDXRef-Package-" + rootDigest.Encoded()
+		linkID := "SPDXRef-Package-" + linkDigest.Encoded()
+		require.Equal(t, []spdxRelationship{
+			{SPDXElementID: "SPDXRef-DOCUMENT", RelationshipType: "DESCRIBES", RelatedSPDXElement: rootID},
+			{SPDXElementID: rootID, RelationshipType: "CONTAINS", RelatedSPDXElement: "SPDXRef-File-1"},
+			{SPDXElementID: rootID, RelationshipType: "DEPENDS_ON", RelatedSPDXElement: linkID},
+			{SPDXElementID: linkID, RelationshipType: "CONTAINS", RelatedSPDXElement: "SPDXRef-File-2"},
+		}, doc.Relationships)
+	})
+
+	t.Run("Success/CycloneDX", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, Encode(&buf, inventory, FormatCycloneDXJSON, opts))
+		var doc cycloneDXDocument
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &doc))
+		require.Equal(t, "CycloneDX", doc.BOMFormat)
+		require.Equal(t, "test", doc.Metadata.Component.Name)
+		require.Equal(t, "pkg:oci/test@v0.1.0", doc.Metadata.Component.PURL)
+		require.Len(t, doc.Metadata.Component.Components, 1)
+		require.Equal(t, []cycloneDXHash{{Algorithm: "SHA-256", Content: fishDigest.Encoded()}}, doc.Metadata.Component.Components[0].Hashes)
+		require.Len(t, doc.Components, 1)
+		require.Equal(t, []cycloneDXDependency{
+			{Ref: rootDigest.String(), DependsOn: []string{linkDigest.String()}},
+			{Ref: linkDigest.String()},
+		}, doc.Dependencies)
+	})
+
+	t.Run("Success/DocumentID", func(t *testing.T) {
+		id := documentID(inventory, FormatSPDXJSON, opts.Created)
+		require.Equal(t, id, documentID(inventory, FormatSPDXJSON, opts.Created))
+		require.NotEqual(t, id, documentID(inventory, FormatCycloneDXJSON, opts.Created))
+		require.NotEqual(t, id, documentID(inventory, FormatSPDXJSON, opts.Created.Add(time.Second)))
+
+		var buf bytes.Buffer
+		require.NoError(t, Encode(&buf, inventory, FormatSPDXJSON, opts))
+		var doc spdxDocument
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &doc))
+		require.Equal(t, "https://emporous.io/spdxdocs/"+id, doc.DocumentNamespace)
+	})
+
+	t.Run("Failure/UnsupportedFormat", func(t *testing.T) {
+		err := Encode(&bytes.Buffer{}, inventory, "spdx-tag-value", opts)
+		require.EqualError(t, err, "unsupported SBOM format \"spdx-tag-value\": must be one of spdx-json, cyclonedx-json")
+	})
+}
+
+// testLoader returns a loader for a collection with a single
+// file that links to a collection in another repository.
+func testLoader(t *testing.T) LoaderFunc {
+	newNode := func(desc ocispec.Descriptor) *v2.Node {
+		node, err := v2.NewNode(desc.Digest.String(), desc)
+		require.NoError(t, err)
+		return node
+	}
+	newCollection := func(root *v2.Node, successors ...*v2.Node) collection.Collection {
+		c := collection.New(root.ID())
+		require.NoError(t, c.AddNode(root))
+		for _, successor := range successors {
+			require.NoError(t, c.AddNode(successor))
+			require.NoError(t, c.AddEdge(collection.NewEdge(root, successor)))
+		}
+		return *c
+	}
+
+	config := newNode(ocispec.Descriptor{MediaType: empspec.MediaTypeConfiguration, Digest: configDigest, Size: 2})
+	root := newCollection(
+		newNode(ocispec.Descriptor{
+			MediaType: ocispec.MediaTypeImageManifest,
+			Digest:    rootDigest,
+			Annotations: map[string]string{
+				empspec.AnnotationEmporousAttributes: `{"core-descriptor":{"name":"test","version":"v0.1.0","licenses":["Apache-2.0"],"purl":"pkg:oci/test@v0.1.0"}}`,
+			},
+		}),
+		config,
+		newNode(ocispec.Descriptor{
+			MediaType: "image/jpeg",
+			Digest:    fishDigest,
+			Size:      4,
+			Annotations: map[string]string{
+				ocispec.AnnotationTitle: "fish.jpg",
+				// File components do not describe the collection.
+				empspec.AnnotationEmporousAttributes: `{"core-descriptor":{"name":"fish","version":"v1.0.0"}}`,
+			},
+		}),
+		newNode(ocispec.Descriptor{
+			MediaType: ocispec.MediaTypeImageManifest,
+			Digest:    linkDigest,
+			Annotations: map[string]string{
+				empspec.AnnotationEmporousAttributes: `{"core-link":{"registryHint":"localhost:5000","namespaceHint":"linked"}}`,
+			},
+		}),
+	)
+	linked := newCollection(
+		newNode(ocispec.Descriptor{MediaType: ocispec.MediaTypeImageManifest, Digest: linkDigest}),
+		config,
+		newNode(ocispec.Descriptor{
+			MediaType: "application/json",
+			Digest:    aboutDigest,
+			Size:      5,
+			Annotations: map[string]string{
+				ocispec.AnnotationTitle:              "about.json",
+				empspec.AnnotationEmporousAttributes: `{"core-descriptor":{"name":"about","version":"v1.0.0"}}`,
+			},
+		}),
+	)
+
+	collections := map[string]collection.Collection{
+		"localhost:5000/test:latest":                   root,
+		"localhost:5000/linked@" + linkDigest.String(): linked,
+	}
+	return func(ctx context.Context, reference string) (collection.Collection, error) {
+		c, ok := collections[reference]
+		if !ok {
+			return collection.Collection{}, errors.New("not found")
+		}
+		return c, nil
+	}
+}
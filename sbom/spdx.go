@@ -0,0 +1,205 @@
+package sbom
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/opencontainers/go-digest"
+)
+
+const (
+	spdxVersion    = "SPDX-2.3"
+	spdxNoAssert   = "NOASSERTION"
+	spdxDocumentID = "SPDXRef-DOCUMENT"
+	// spdxNamespace is the prefix of the generated document namespace.
+	spdxNamespace = "https://emporous.io/spdxdocs/"
+)
+
+// SPDX relationship types.
+const (
+	spdxDescribes = "DESCRIBES"
+	spdxContains  = "CONTAINS"
+	spdxDependsOn = "DEPENDS_ON"
+)
+
+// spdxPurposes are the valid SPDX package purposes.
+var spdxPurposes = map[string]bool{
+	"APPLICATION":      true,
+	"FRAMEWORK":        true,
+	"LIBRARY":          true,
+	"CONTAINER":        true,
+	"OPERATING-SYSTEM": true,
+	"DEVICE":           true,
+	"FIRMWARE":         true,
+	"SOURCE":           true,
+	"ARCHIVE":          true,
+	"FILE":             true,
+	"INSTALL":          true,
+	"OTHER":            true,
+}
+
+// spdxDocument is an SPDX 2.3 JSON document.
+type spdxDocument struct {
+	SPDXVersion       string             `json:"spdxVersion"`
+	DataLicense       string             `json:"dataLicense"`
+	SPDXID            string             `json:"SPDXID"`
+	Name              string             `json:"name"`
+	DocumentNamespace string             `json:"documentNamespace"`
+	CreationInfo      spdxCreationInfo   `json:"creationInfo"`
+	Packages          []spdxPackage      `json:"packages"`
+	Files             []spdxFile         `json:"files,omitempty"`
+	Relationships     []spdxRelationship `json:"relationships"`
+}
+
+type spdxCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+type spdxPackage struct {
+	SPDXID           string            `json:"SPDXID"`
+	Name             string            `json:"name"`
+	VersionInfo      string            `json:"versionInfo,omitempty"`
+	DownloadLocation string            `json:"downloadLocation"`
+	FilesAnalyzed    bool              `json:"filesAnalyzed"`
+	Checksums        []spdxChecksum    `json:"checksums,omitempty"`
+	LicenseConcluded string            `json:"licenseConcluded"`
+	LicenseDeclared  string            `json:"licenseDeclared"`
+	CopyrightText    string            `json:"copyrightText"`
+	PrimaryPurpose   string            `json:"primaryPurpose,omitempty"`
+	ExternalRefs     []spdxExternalRef `json:"externalRefs,omitempty"`
+}
+
+type spdxFile struct {
+	SPDXID           string         `json:"SPDXID"`
+	FileName         string         `json:"fileName"`
+	Checksums        []spdxChecksum `json:"checksums"`
+	LicenseConcluded string         `json:"licenseConcluded"`
+	CopyrightText    string         `json:"copyrightText"`
+}
+
+type spdxChecksum struct {
+	Algorithm     string `json:"algorithm"`
+	ChecksumValue string `json:"checksumValue"`
+}
+
+type spdxExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+}
+
+type spdxRelationship struct {
+	SPDXElementID      string `json:"spdxElementId"`
+	RelationshipType   string `json:"relationshipType"`
+	RelatedSPDXElement string `json:"relatedSpdxElement"`
+}
+
+// encodeSPDX writes the inventory as an SPDX 2.3 JSON document. Each collection is
+// an SPDX package that contains its files and depends on its linked collections.
+func encodeSPDX(w io.Writer, inventory Inventory, opts EncodeOptions) error {
+	root := inventory.Root()
+	doc := spdxDocument{
+		SPDXVersion:       spdxVersion,
+		DataLicense:       "CC0-1.0",
+		SPDXID:            spdxDocumentID,
+		Name:              packageName(root),
+		DocumentNamespace: spdxNamespace + documentID(inventory, FormatSPDXJSON, opts.Created),
+		CreationInfo: spdxCreationInfo{
+			Created:  opts.Created.UTC().Format(time.RFC3339),
+			Creators: []string{fmt.Sprintf("Tool: emporous-%s", opts.ToolVersion)},
+		},
+	}
+
+	packageIDs := map[digest.Digest]string{}
+	for _, pkg := range inventory.Packages {
+		packageIDs[pkg.Digest] = "SPDXRef-Package-" + pkg.Digest.Encoded()
+	}
+
+	doc.Relationships = append(doc.Relationships, spdxRelationship{
+		SPDXElementID:      spdxDocumentID,
+		RelationshipType:   spdxDescribes,
+		RelatedSPDXElement: packageIDs[root.Digest],
+	})
+
+	fileCount := 0
+	for _, pkg := range inventory.Packages {
+		pkgID := packageIDs[pkg.Digest]
+		spdxPkg := spdxPackage{
+			SPDXID:           pkgID,
+			Name:             packageName(pkg),
+			VersionInfo:      packageVersion(pkg),
+			DownloadLocation: spdxNoAssert,
+			Checksums: []spdxChecksum{
+				{Algorithm: checksumAlgorithm(pkg.Digest), ChecksumValue: pkg.Digest.Encoded()},
+			},
+			LicenseConcluded: spdxNoAssert,
+			LicenseDeclared:  spdxNoAssert,
+			CopyrightText:    spdxNoAssert,
+			ExternalRefs: []spdxExternalRef{
+				{ReferenceCategory: "OTHER", ReferenceType: "oci", ReferenceLocator: pkg.Reference},
+			},
+		}
+		if pkg.Component != nil {
+			if len(pkg.Component.Licenses) != 0 {
+				spdxPkg.LicenseDeclared = strings.Join(pkg.Component.Licenses, " AND ")
+			}
+			if purpose := strings.ToUpper(pkg.Component.Type); spdxPurposes[purpose] {
+				spdxPkg.PrimaryPurpose = purpose
+			}
+			for _, cpe := range pkg.Component.CPEs {
+				spdxPkg.ExternalRefs = append(spdxPkg.ExternalRefs, spdxExternalRef{
+					ReferenceCategory: "SECURITY",
+					ReferenceType:     "cpe23Type",
+					ReferenceLocator:  cpe,
+				})
+			}
+			if pkg.Component.PURL != "" {
+				spdxPkg.ExternalRefs = append(spdxPkg.ExternalRefs, spdxExternalRef{
+					ReferenceCategory: "PACKAGE-MANAGER",
+					ReferenceType:     "purl",
+					ReferenceLocator:  pkg.Component.PURL,
+				})
+			}
+		}
+		doc.Packages = append(doc.Packages, spdxPkg)
+
+		for _, file := range pkg.Files {
+			fileCount++
+			fileID := fmt.Sprintf("SPDXRef-File-%d", fileCount)
+			doc.Files = append(doc.Files, spdxFile{
+				SPDXID:   fileID,
+				FileName: "./" + strings.TrimPrefix(file.Name, "/"),
+				Checksums: []spdxChecksum{
+					{Algorithm: checksumAlgorithm(file.Digest), ChecksumValue: file.Digest.Encoded()},
+				},
+				LicenseConcluded: spdxNoAssert,
+				CopyrightText:    spdxNoAssert,
+			})
+			doc.Relationships = append(doc.Relationships, spdxRelationship{
+				SPDXElementID:      pkgID,
+				RelationshipType:   spdxContains,
+				RelatedSPDXElement: fileID,
+			})
+		}
+
+		for _, dependency := range pkg.Dependencies {
+			dependencyID, ok := packageIDs[dependency]
+			if !ok {
+				continue
+			}
+			doc.Relationships = append(doc.Relationships, spdxRelationship{
+				SPDXElementID:      pkgID,
+				RelationshipType:   spdxDependsOn,
+				RelatedSPDXElement: dependencyID,
+			})
+		}
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(doc)
+}
@@ -2,15 +2,16 @@ package collectionmanager
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
-	"oras.land/oras-go/v2/content/file"
 
 	"github.com/emporous/emporous-go/api/client/v1alpha1"
 	managerapi "github.com/emporous/emporous-go/api/services/collectionmanager/v1alpha1"
 	"github.com/emporous/emporous-go/content"
+	"github.com/emporous/emporous-go/content/file"
 	"github.com/emporous/emporous-go/manager"
 	"github.com/emporous/emporous-go/nodes/descriptor"
 	"github.com/emporous/emporous-go/registryclient/orasclient"
@@ -94,14 +95,28 @@ func (s *service) PublishContent(ctx context.Context, message *managerapi.Publis
 		}
 	}
 
-	digest, err := s.mg.Push(ctx, message.Destination, client)
-	if err != nil {
-		if err != nil {
-			return nil, status.Error(codes.Internal, err.Error())
-		}
+	digest, results, err := s.mg.Push(ctx, message.Destination, client, message.Tags...)
+	if err != nil && digest == "" {
+		return nil, status.Error(codes.Internal, err.Error())
 	}
 
-	return &managerapi.Publish_Response{Digest: digest}, nil
+	response := &managerapi.Publish_Response{Digest: digest}
+	for _, result := range results {
+		tagResult := &managerapi.TagResult{
+			Reference: result.Reference,
+			Tagged:    result.Err == nil,
+		}
+		if result.Err != nil {
+			tagResult.Error = result.Err.Error()
+			response.Diagnostics = append(response.Diagnostics, &managerapi.Diagnostic{
+				Severity: managerapi.Diagnostic_SEVERITY_ERROR,
+				Summary:  fmt.Sprintf("tag %s failed", result.Reference),
+				Detail:   result.Err.Error(),
+			})
+		}
+		response.Tags = append(response.Tags, tagResult)
+	}
+	return response, nil
 }
 
 // RetrieveContent retrieves collection contact from a storage provider based on client input.
@@ -134,8 +149,18 @@ func (s *service) RetrieveContent(ctx context.Context, message *managerapi.Retri
 		}
 	}()
 
-	digests, err := s.mg.Pull(ctx, message.Source, client, file.New(message.Destination))
-	if err != nil {
+	destination := file.New(message.Destination)
+	if message.OutputTemplate != "" {
+		tmpl, err := file.ParseTemplate(message.OutputTemplate)
+		if err != nil {
+			return &managerapi.Retrieve_Response{}, status.Error(codes.InvalidArgument, fmt.Sprintf("output template: %v", err))
+		}
+		destination = file.NewWithTemplate(message.Destination, tmpl)
+	}
+
+	digests, err := s.mg.Pull(ctx, message.Source, client, destination)
+	var fileInfoErr *manager.ErrFileInfo
+	if err != nil && !errors.As(err, &fileInfoErr) {
 		return &managerapi.Retrieve_Response{}, status.Error(codes.Internal, err.Error())
 	}
 
@@ -152,5 +177,15 @@ func (s *service) RetrieveContent(ctx context.Context, message *managerapi.Retri
 		}, nil
 	}
 
-	return &managerapi.Retrieve_Response{Digests: digests}, nil
+	response := &managerapi.Retrieve_Response{Digests: digests}
+	if fileInfoErr != nil {
+		for _, file := range fileInfoErr.Files {
+			response.Diagnostics = append(response.Diagnostics, &managerapi.Diagnostic{
+				Severity: 2,
+				Summary:  "RetrieveWarning",
+				Detail:   fmt.Sprintf("file information could not be applied to %s", file),
+			})
+		}
+	}
+	return response, nil
 }
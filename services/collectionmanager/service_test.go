@@ -53,14 +53,17 @@ func TestCollectionManagerServer_All(t *testing.T) {
 	require.NoError(t, err)
 
 	cases := []struct {
-		name          string
-		pubAssertFunc func(*managerapi.Publish_Response) bool
-		workspace     string
-		collection    map[string]map[string]interface{}
-		filter        json.RawMessage
-		resAssertFunc func(*managerapi.Retrieve_Response, string) bool
-		sev           managerapi.Diagnostic_Severity
-		errMes        string
+		name           string
+		pubAssertFunc  func(*managerapi.Publish_Response) bool
+		workspace      string
+		tags           []string
+		collection     map[string]map[string]interface{}
+		filter         json.RawMessage
+		outputTemplate string
+		resAssertFunc  func(*managerapi.Retrieve_Response, string) bool
+		sev            managerapi.Diagnostic_Severity
+		errMes         string
+		retrieveErrMes string
 	}{
 		{
 			name:      "Success/ValidWorkspace",
@@ -84,6 +87,69 @@ func TestCollectionManagerServer_All(t *testing.T) {
 				return err == nil
 			},
 		},
+		{
+			name:      "Success/WithOverlappingPatterns",
+			workspace: "testdata/workspace",
+			collection: map[string]map[string]interface{}{
+				"*.jpg": {
+					"animal": true,
+				},
+				"fish.*": {
+					"size": "small",
+				},
+			},
+			filter: []byte(`{"unknown":{"animal":true,"size":"small"}}`),
+			resAssertFunc: func(_ *managerapi.Retrieve_Response, root string) bool {
+				_, err := os.Stat(path.Join(root, "fish.jpg"))
+				return err == nil
+			},
+		},
+		{
+			name:      "Success/WithTags",
+			workspace: "testdata/workspace",
+			tags:      []string{"1.4", "1.4.2"},
+			pubAssertFunc: func(resp *managerapi.Publish_Response) bool {
+				if len(resp.Tags) != 2 {
+					return false
+				}
+				for i, tag := range []string{"1.4", "1.4.2"} {
+					if resp.Tags[i].Reference != fmt.Sprintf("%s/test:%s", u.Host, tag) || !resp.Tags[i].Tagged {
+						return false
+					}
+				}
+				return true
+			},
+			resAssertFunc: func(_ *managerapi.Retrieve_Response, root string) bool {
+				_, err := os.Stat(path.Join(root, "fish.jpg"))
+				return err == nil
+			},
+		},
+		{
+			name:      "Success/WithOutputTemplate",
+			workspace: "testdata/workspace",
+			collection: map[string]map[string]interface{}{
+				"*.jpg": {
+					"animal": "fish",
+				},
+			},
+			outputTemplate: "{{.animal}}/{{base .title}}",
+			resAssertFunc: func(_ *managerapi.Retrieve_Response, root string) bool {
+				_, err := os.Stat(path.Join(root, "fish", "fish.jpg"))
+				return err == nil
+			},
+		},
+		{
+			name:           "Failure/OutputTemplateEscapesDestination",
+			workspace:      "testdata/workspace",
+			outputTemplate: "../{{.title}}",
+			retrieveErrMes: "file fish.jpg: path \"../fish.jpg\": path escapes the output directory",
+		},
+		{
+			name:           "Failure/InvalidOutputTemplate",
+			workspace:      "testdata/workspace",
+			outputTemplate: "{{.title",
+			retrieveErrMes: "rpc error: code = InvalidArgument desc = output template:",
+		},
 		{
 			name:      "Warning/FilteredCollection",
 			sev:       2,
@@ -117,6 +183,7 @@ func TestCollectionManagerServer_All(t *testing.T) {
 			pRequest := &managerapi.Publish_Request{
 				Source:      c.workspace,
 				Destination: fmt.Sprintf("%s/test:latest", u.Host),
+				Tags:        c.tags,
 			}
 
 			if c.collection != nil {
@@ -142,12 +209,16 @@ func TestCollectionManagerServer_All(t *testing.T) {
 			} else {
 				require.NoError(t, err)
 			}
+			if c.pubAssertFunc != nil {
+				require.True(t, c.pubAssertFunc(pResp))
+			}
 
 			require.NoError(t, err)
 			destination := t.TempDir()
 			rRequest := &managerapi.Retrieve_Request{
-				Source:      fmt.Sprintf("%s/test@%s", u.Host, pResp.Digest),
-				Destination: destination,
+				Source:         fmt.Sprintf("%s/test@%s", u.Host, pResp.Digest),
+				Destination:    destination,
+				OutputTemplate: c.outputTemplate,
 			}
 
 			if c.filter != nil {
@@ -160,6 +231,10 @@ func TestCollectionManagerServer_All(t *testing.T) {
 			}
 
 			rResp, err := client.RetrieveContent(ctx, rRequest, opts...)
+			if c.retrieveErrMes != "" {
+				require.ErrorContains(t, err, c.retrieveErrMes)
+				return
+			}
 			if c.errMes != "" {
 				require.EqualError(t, err, c.errMes)
 			} else {
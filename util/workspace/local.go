@@ -31,6 +31,22 @@ func NewLocalWorkspace(dir string) (Workspace, error) {
 	return &w, w.init()
 }
 
+// NewFsWorkspace returns a new workspace rooted at dir
+// on the provided afero.Fs.
+func NewFsWorkspace(fs afero.Fs, dir string) (Workspace, error) {
+	w := localWorkspace{
+		fs:  fs,
+		dir: filepath.Clean(dir),
+	}
+	return &w, w.init()
+}
+
+// NewMemoryWorkspace returns a new workspace backed by
+// an in-memory filesystem.
+func NewMemoryWorkspace() (Workspace, error) {
+	return NewFsWorkspace(afero.NewMemMapFs(), string(filepath.Separator))
+}
+
 func (w *localWorkspace) init() error {
 	if w.fs == nil {
 		w.fs = afero.NewOsFs()
@@ -117,6 +133,21 @@ func (w *localWorkspace) GetWriter(_ context.Context, path string) (io.Writer, e
 	return writer, nil
 }
 
+// GetReader returns an afero.File as a reader.
+// In this implementation, key is a file path.
+func (w *localWorkspace) GetReader(_ context.Context, path string) (io.ReadCloser, error) {
+	reader, err := w.fs.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening object file: %w", err)
+	}
+	return reader, nil
+}
+
+// Stat returns file information for the object at the path.
+func (w *localWorkspace) Stat(path string) (os.FileInfo, error) {
+	return w.fs.Stat(path)
+}
+
 // Walk traverses the workspace directory.
 func (w *localWorkspace) Walk(walkFunc filepath.WalkFunc) error {
 	return afero.Walk(w.fs, ".", walkFunc)
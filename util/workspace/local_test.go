@@ -2,6 +2,7 @@ package workspace
 
 import (
 	"context"
+	"io"
 	"os"
 	"path/filepath"
 	"testing"
@@ -50,6 +51,12 @@ func TestLocalWorkspace(t *testing.T) {
 	require.NoError(t, err)
 	require.True(t, info.Mode().IsRegular())
 
+	info, err = workspace.Stat("anotherworkspace")
+	require.NoError(t, err)
+	require.True(t, info.IsDir())
+	_, err = workspace.Stat("missing")
+	require.ErrorIs(t, err, os.ErrNotExist)
+
 	var outObj2 object
 	require.NoError(t, subWorkspace.ReadObject(ctx, testpath, &outObj2))
 	require.Equal(t, inObj, outObj)
@@ -58,3 +65,35 @@ func TestLocalWorkspace(t *testing.T) {
 	_, err = underlyingFS.Stat("foo/anotherworkspace")
 	require.ErrorIs(t, err, os.ErrNotExist)
 }
+
+func TestMemoryWorkspace(t *testing.T) {
+	workspace, err := NewMemoryWorkspace()
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	testpath := filepath.Join("level1", "foo.txt")
+	require.NoError(t, workspace.WriteObject(ctx, testpath, "bar"))
+
+	var files []string
+	err = workspace.Walk(func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.Mode().IsRegular() {
+			files = append(files, path)
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{testpath}, files)
+
+	reader, err := workspace.GetReader(ctx, testpath)
+	require.NoError(t, err)
+	data, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	require.NoError(t, reader.Close())
+	require.Equal(t, "bar", string(data))
+
+	_, err = workspace.GetReader(ctx, "missing.txt")
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
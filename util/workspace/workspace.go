@@ -3,11 +3,12 @@ package workspace
 import (
 	"context"
 	"io"
+	"os"
 	"path/filepath"
 )
 
 // Workspace defines methods for accessing and publishing
-// files in a local context.
+// files in a local or in-memory context.
 type Workspace interface {
 	// ReadObject reads the provided object from disk.
 	ReadObject(context.Context, string, interface{}) error
@@ -15,6 +16,12 @@ type Workspace interface {
 	WriteObject(context.Context, string, interface{}) error
 	// GetWriter returns an os.File as a writer.
 	GetWriter(context.Context, string) (io.Writer, error)
+	// GetReader returns a reader for the object at the
+	// provided path.
+	GetReader(context.Context, string) (io.ReadCloser, error)
+	// Stat returns file information for the object
+	// at the provided path.
+	Stat(string) (os.FileInfo, error)
 	// Walk will traverse the workspace directory.
 	Walk(filepath.WalkFunc) error
 	// NewDirectory creates a new workspace under the current workspace.